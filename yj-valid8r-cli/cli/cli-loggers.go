@@ -61,6 +61,22 @@ func legacyCLILogger(results internal.ValidationResponse) {
 
 	fmt.Printf("🆗 %s", cyan(fmt.Sprintf("Validation going on for %s data type.\n", results.ValidationSummary.ValidationDataType)))
 
+	if jsonLines := results.ValidationSummary.JSONLines; jsonLines != nil {
+		fmt.Printf("🆗 %s", cyan(fmt.Sprintf("Data contains %d records: %d valid, %d invalid.\n", jsonLines.Records, jsonLines.ValidRecords, jsonLines.InvalidRecords)))
+		for _, doc := range results.Documents {
+			fmt.Printf("❌ %s\n", red(fmt.Sprintf("Record on line %d is invalid", doc.StartLine)))
+		}
+	} else if len(results.Documents) > 0 {
+		fmt.Printf("🆗 %s", cyan(fmt.Sprintf("Data contains %d documents.\n", results.ValidationSummary.DocumentCount)))
+		for _, doc := range results.Documents {
+			if !doc.Valid {
+				fmt.Printf("❌ %s\n", red(fmt.Sprintf("Document %d (line %d) is invalid", doc.Index, doc.StartLine)))
+			} else {
+				fmt.Printf("✅ %s\n", green(fmt.Sprintf("Document %d (line %d) is valid", doc.Index, doc.StartLine)))
+			}
+		}
+	}
+
 	for _, result := range results.SchemaResults {
 		if !result.Valid {
 			fmt.Printf("❌ %s ( ERRORS: %s | WARNINGS: %s)\n", red(fmt.Sprintf("Validated against schema '%s'", result.Schema)), red(len(result.Errors)), yellow(len(result.Warnings)))
@@ -76,6 +92,23 @@ func legacyCLILogger(results internal.ValidationResponse) {
 		}
 	}
 
+	if lint := results.YAMLLint; lint != nil {
+		if !lint.Valid {
+			fmt.Printf("❌ %s ( ERRORS: %s | WARNINGS: %s)\n", red("YAML lint"), red(len(lint.Errors)), yellow(len(lint.Warnings)))
+		} else {
+			fmt.Printf("✅ %s ( WARNINGS: %s)\n", green("YAML lint"), yellow(len(lint.Warnings)))
+		}
+		for _, err := range lint.Errors {
+			fmt.Printf("- [ERROR] %s\n", err)
+		}
+		for _, warning := range lint.Warnings {
+			fmt.Printf("- [WARNING] %s\n", warning)
+		}
+		for _, msg := range lint.Messages {
+			fmt.Printf("- [INFO] %s\n", msg)
+		}
+	}
+
 	if len(results.RegexPatterns) > 0 {
 		fmt.Println(green("✔ Regex Patterns:"))
 		for _, r := range results.RegexPatterns {
@@ -112,10 +145,17 @@ func legacyCLILogger(results internal.ValidationResponse) {
 		for _, r := range results.PathSearchOutput {
 			fmt.Printf("  ➡️  PathName: %s | PathKey: %s\n", r.PathName, r.PathKey)
 
+			for _, e := range r.Errors {
+				fmt.Printf("     - [ERROR] %v\n", e)
+			}
+			for _, w := range r.Warnings {
+				fmt.Printf("     - [WARNING] %v\n", w)
+			}
+
 			if len(r.Results) > 0 {
 				fmt.Println("     Results:")
 				for _, m := range r.Results {
-					fmt.Printf("        - FullPath: %s | RawData: %s\n", m.FullPath, m.Raw)
+					fmt.Printf("        - FullPath: %s | Line: %d | RawData: %s\n", m.FullPath, m.Line, m.Raw)
 				}
 			} else {
 				fmt.Printf("     - Data: %v | No results found.\n", r.Results)
@@ -187,6 +227,25 @@ func prettyCLILogger(results internal.ValidationResponse) {
 
 	fmt.Println(white(fmt.Sprintf("Validation going on for %s data type.\n", results.ValidationSummary.ValidationDataType)))
 
+	// Record counts and invalid records of JSON Lines data, or per-document status for multi-document streams
+	if jsonLines := results.ValidationSummary.JSONLines; jsonLines != nil {
+		fmt.Println(cyan(fmt.Sprintf("ℹ Records: %d ( VALID: %d | INVALID: %d )", jsonLines.Records, jsonLines.ValidRecords, jsonLines.InvalidRecords)))
+		for _, doc := range results.Documents {
+			fmt.Printf("  %s\n", redBold(fmt.Sprintf("✖ Line %d", doc.StartLine)))
+		}
+		fmt.Println()
+	} else if len(results.Documents) > 0 {
+		fmt.Println(cyan(fmt.Sprintf("ℹ Documents: %d", results.ValidationSummary.DocumentCount)))
+		for _, doc := range results.Documents {
+			if !doc.Valid {
+				fmt.Printf("  %s %s\n", redBold(fmt.Sprintf("✖ Document %d:", doc.Index)), white(fmt.Sprintf("starts at line %d", doc.StartLine)))
+			} else {
+				fmt.Printf("  %s %s\n", greenBold(fmt.Sprintf("✔ Document %d:", doc.Index)), white(fmt.Sprintf("starts at line %d", doc.StartLine)))
+			}
+		}
+		fmt.Println()
+	}
+
 	// Loop through each schema result
 	for _, result := range results.SchemaResults {
 		// Print schema header with colored status
@@ -224,6 +283,32 @@ func prettyCLILogger(results internal.ValidationResponse) {
 		fmt.Println()
 	}
 
+	// YAML lint results
+	if lint := results.YAMLLint; lint != nil {
+		if !lint.Valid {
+			fmt.Printf("%s ( ERRORS: %s | WARNINGS: %s )\n",
+				redBold("✖ YAML Lint:"),
+				redBold(len(lint.Errors)),
+				yellowBold(len(lint.Warnings)),
+			)
+		} else {
+			fmt.Printf("%s ( WARNINGS: %s )\n",
+				greenBold("✔ YAML Lint:"),
+				yellowBold(len(lint.Warnings)),
+			)
+		}
+		for _, errMsg := range lint.Errors {
+			fmt.Printf("  %s %s\n", redBold("ERROR"), white(errMsg))
+		}
+		for _, warnMsg := range lint.Warnings {
+			fmt.Printf("  %s %s\n", yellowBold("WARNING"), white(warnMsg))
+		}
+		for _, msg := range lint.Messages {
+			fmt.Printf("  %s %s\n", cyan("INFO"), white(msg))
+		}
+		fmt.Println()
+	}
+
 	// If there are regex patterns, list them
 	if len(results.RegexPatterns) > 0 {
 		fmt.Println(greenBold("✔ Regex Patterns:"))
@@ -272,13 +357,19 @@ func prettyCLILogger(results internal.ValidationResponse) {
 		for _, output := range results.PathSearchOutput {
 			fmt.Printf("  %s %s\n", greenBold("PathName:"), white(output.PathName))
 			fmt.Printf("  %s %s\n", greenBold("PathKey:"), white(output.PathKey))
+			for _, errMsg := range output.Errors {
+				fmt.Printf("    %s %s\n", redBold("ERROR:"), white(errMsg))
+			}
+			for _, warnMsg := range output.Warnings {
+				fmt.Printf("    %s %s\n", yellowBold("WARNING:"), white(warnMsg))
+			}
 			if len(output.Results) == 0 {
 				fmt.Printf("    %s\n", yellowBold("No results found"))
 			} else {
 				fmt.Printf("    %s\n", greenBold("Results:"))
 				for _, res := range output.Results {
 					// FullPath and Raw in aligned fashion, indent nicely
-					fmt.Printf("      %s %s\n", cyan("FullPath:"), white(res.FullPath))
+					fmt.Printf("      %s %s %s\n", cyan("FullPath:"), white(res.FullPath), cyan(fmt.Sprintf("(line %d, column %d)", res.Line, res.Column)))
 					fmt.Printf("      %s %s\n", cyan("Raw:"), white(res.Raw))
 				}
 			}
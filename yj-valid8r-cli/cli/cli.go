@@ -13,22 +13,43 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
-func StartCLI(configPath, flagData, flagCLIOutputFormat string, flagPlugins string,
-	schemaList []string,
-	flagStrictValidationMode *bool,
-	flagWhitespace *bool,
-	flagRegexPatterns []validator.RegexPatternRules,
-	flagSearchPaths []validator.SearchPathsDef,
-) {
+// Options holds the command line flags of a validation run. Flags left unset (empty, zero or nil)
+// keep the setting of the config file, or its default.
+type Options struct {
+	ConfigPath              string
+	Data                    string
+	CLIOutputFormat         string
+	Plugins                 string
+	Schemas                 []string
+	SchemaRoutes            []validator.SchemaRoute
+	SchemaCache             *validator.SchemaCacheConfig
+	SchemaMappings          []validator.SchemaMapping
+	SchemaDraft             string
+	StrictValidation        *bool
+	CheckTrailingWhitespace *bool
+	CheckDuplicateKeys      *bool
+	EditorConfig            *bool
+	Fix                     bool // Fix whitespace issues in the data file before validating it
+	DryRun                  bool // Print the fixes as a diff instead of writing them
+	IndentWidth             int  // Indentation width of the fixes; the formatting rules' width when 0
+	YAMLLint                *validator.YAMLLintConfig
+	JSONLines               *validator.JSONLinesConfig
+	MaxAliasExpansion       int
+	RegexPatternRules       []validator.RegexPatternRules
+	SearchPaths             []validator.SearchPathsDef
+}
+
+// StartCLI validates the data file of the config at opts.ConfigPath, overridden by the other flags.
+func StartCLI(opts Options) {
 	log.Println("Validation started")
 
-	cfg, err := loadConfig(configPath)
+	cfg, err := loadConfig(opts.ConfigPath)
 	if err != nil {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
 	// Apply overrides or defaults
-	applyOverrides(cfg, schemaList, flagData, flagCLIOutputFormat, flagPlugins, flagRegexPatterns, flagSearchPaths, flagStrictValidationMode, flagWhitespace)
+	applyOverrides(cfg, opts)
 
 	if strings.TrimSpace(cfg.Data) == "" {
 		log.Fatalf("Data file paths must be specified either via config file or flags")
@@ -39,11 +60,63 @@ func StartCLI(configPath, flagData, flagCLIOutputFormat string, flagPlugins stri
 		log.Fatal(err)
 	}
 
-	if validator.IsUnknownDataType(dataBytes) {
-		log.Fatalf("Provided data is neither valid JSON nor valid YAML. Please check if your YAML/JSON is correct.")
+	if *cfg.EditorConfig {
+		cfg.Formatting, err = validator.ApplyEditorConfig(cfg.Data, cfg.Formatting)
+		if err != nil {
+			log.Fatalf("Failed to read .editorconfig: %v", err)
+		}
+	}
+
+	// Fix whitespace first: tab indentation alone can make YAML unparsable
+	if opts.Fix {
+		indentWidth := opts.IndentWidth
+		if indentWidth == 0 {
+			indentWidth = cfg.Formatting.IndentWidth
+		}
+		dataBytes = fixWhitespace(cfg.Data, dataBytes, opts.DryRun, indentWidth, cfg.Formatting)
 	}
 
-	results := internal.InitValidation(cfg.Schemas, dataBytes, *cfg.CheckTrailingWhitespace, cfg.RegexPatternRules, cfg.SearchPaths, cfg.Plugins)
+	// In JSON Lines mode, lines that are not valid JSON are reported with the other results
+	detection := validator.DetectDataFormat(dataBytes)
+	if detection.Format == validator.DataTypeUNKNOWN && cfg.JSONLines == nil {
+		log.Fatalf("Provided data is not valid JSON, YAML, TOML or JSON5: %s", detection.Error)
+	}
+
+	var schemaCache *validator.SchemaCache
+	if cfg.SchemaCache != nil {
+		schemaCache, err = validator.NewSchemaCache(*cfg.SchemaCache)
+		if err != nil {
+			log.Fatalf("Failed to set up schema cache: %v", err)
+		}
+	}
+
+	schemaDraft, err := validator.ParseSchemaDraft(cfg.SchemaDraft)
+	if err != nil {
+		log.Fatalf("Invalid schemaDraft: %v", err)
+	}
+
+	schemaRegistry := validator.NewSchemaRegistry(validator.SchemaOptions{
+		Cache:    schemaCache,
+		Resolver: validator.NewPrefixSchemaResolver(cfg.SchemaMappings),
+		Draft:    schemaDraft,
+	})
+
+	results := internal.InitValidation(dataBytes, internal.ValidationOptions{
+		Detection:               &detection,
+		Schemas:                 cfg.Schemas,
+		SchemaRoutes:            cfg.SchemaRoutes,
+		SchemaRegistry:          schemaRegistry,
+		JSONLines:               cfg.JSONLines,
+		MaxAliasExpansion:       cfg.MaxAliasExpansion,
+		CheckTrailingWhitespace: *cfg.CheckTrailingWhitespace,
+		Formatting:              cfg.Formatting,
+		CheckDuplicateKeys:      *cfg.CheckDuplicateKeys,
+		YAMLLint:                cfg.YAMLLint,
+		RegexPatternRules:       cfg.RegexPatternRules,
+		SearchPaths:             cfg.SearchPaths,
+		Plugins:                 cfg.Plugins,
+	})
+	results.SetFile(cfg.Data)
 
 	switch cfg.CLIOutputFormat {
 	case string(CLIOutputFormatTypeJSON):
@@ -67,34 +140,66 @@ func StartCLI(configPath, flagData, flagCLIOutputFormat string, flagPlugins stri
 }
 
 // applyOverrides applies command line overrides or defaults to config
-func applyOverrides(
-	cfg *internal.ValidationRequest,
-	schemaList []string,
-	flagData, flagCLIOutputFormat, flagPlugins string,
-	flagVarPatterns []validator.RegexPatternRules,
-	flagSearchPaths []validator.SearchPathsDef,
-	flagStrictValidationMode, flagWhitespace *bool,
-) {
-	if len(schemaList) > 0 {
-		cfg.Schemas = schemaList
-	}
-	if flagData != "" {
-		cfg.Data = flagData
-	}
-	if flagCLIOutputFormat != "" {
-		cfg.CLIOutputFormat = string(CLIOutputFormatType(flagCLIOutputFormat))
+func applyOverrides(cfg *internal.ValidationRequest, opts Options) {
+	if len(opts.Schemas) > 0 {
+		cfg.Schemas = opts.Schemas
+	}
+	if len(opts.SchemaRoutes) > 0 {
+		cfg.SchemaRoutes = opts.SchemaRoutes
+	}
+	if len(opts.SchemaMappings) > 0 {
+		cfg.SchemaMappings = opts.SchemaMappings
+	}
+	if opts.SchemaDraft != "" {
+		cfg.SchemaDraft = opts.SchemaDraft
+	}
+	if opts.SchemaCache != nil {
+		if cfg.SchemaCache == nil {
+			cfg.SchemaCache = &validator.SchemaCacheConfig{}
+		}
+		if opts.SchemaCache.Dir != "" {
+			cfg.SchemaCache.Dir = opts.SchemaCache.Dir
+		}
+		if opts.SchemaCache.TTL != "" {
+			cfg.SchemaCache.TTL = opts.SchemaCache.TTL
+		}
+		if opts.SchemaCache.Offline {
+			cfg.SchemaCache.Offline = true
+		}
+		if len(opts.SchemaCache.Pins) > 0 {
+			cfg.SchemaCache.Pins = opts.SchemaCache.Pins
+		}
+	}
+	if opts.Data != "" {
+		cfg.Data = opts.Data
+	}
+	if opts.CLIOutputFormat != "" {
+		cfg.CLIOutputFormat = string(CLIOutputFormatType(opts.CLIOutputFormat))
 	}
 	if cfg.CLIOutputFormat == "" {
 		cfg.CLIOutputFormat = string(CLIOutputFormatTypePretty)
 	}
-	if flagPlugins != "" {
-		cfg.Plugins = flagPlugins
+	if opts.Plugins != "" {
+		cfg.Plugins = opts.Plugins
+	}
+	if opts.YAMLLint != nil {
+		cfg.YAMLLint = opts.YAMLLint
+	}
+	if opts.JSONLines != nil {
+		cfg.JSONLines = opts.JSONLines
+	}
+	// .jsonl and .ndjson files are validated record by record
+	if cfg.JSONLines == nil && (strings.HasSuffix(cfg.Data, ".jsonl") || strings.HasSuffix(cfg.Data, ".ndjson")) {
+		cfg.JSONLines = &validator.JSONLinesConfig{}
+	}
+	if opts.MaxAliasExpansion != 0 {
+		cfg.MaxAliasExpansion = opts.MaxAliasExpansion
 	}
-	if len(flagVarPatterns) > 0 {
-		cfg.RegexPatternRules = flagVarPatterns
+	if len(opts.RegexPatternRules) > 0 {
+		cfg.RegexPatternRules = opts.RegexPatternRules
 	}
-	if len(flagSearchPaths) > 0 {
-		cfg.SearchPaths = flagSearchPaths
+	if len(opts.SearchPaths) > 0 {
+		cfg.SearchPaths = opts.SearchPaths
 	}
 
 	// Default StrictValidation = true
@@ -102,8 +207,8 @@ func applyOverrides(
 		def := true
 		cfg.StrictValidation = &def
 	}
-	if flagStrictValidationMode != nil {
-		cfg.StrictValidation = flagStrictValidationMode
+	if opts.StrictValidation != nil {
+		cfg.StrictValidation = opts.StrictValidation
 	}
 
 	// Default CheckTrailingWhitespace = true
@@ -111,8 +216,26 @@ func applyOverrides(
 		def := true
 		cfg.CheckTrailingWhitespace = &def
 	}
-	if flagWhitespace != nil {
-		cfg.CheckTrailingWhitespace = flagWhitespace
+	if opts.CheckTrailingWhitespace != nil {
+		cfg.CheckTrailingWhitespace = opts.CheckTrailingWhitespace
+	}
+
+	// Default CheckDuplicateKeys = false
+	if cfg.CheckDuplicateKeys == nil {
+		def := false
+		cfg.CheckDuplicateKeys = &def
+	}
+	if opts.CheckDuplicateKeys != nil {
+		cfg.CheckDuplicateKeys = opts.CheckDuplicateKeys
+	}
+
+	// Default EditorConfig = true
+	if cfg.EditorConfig == nil {
+		def := true
+		cfg.EditorConfig = &def
+	}
+	if opts.EditorConfig != nil {
+		cfg.EditorConfig = opts.EditorConfig
 	}
 }
 
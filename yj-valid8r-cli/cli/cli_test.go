@@ -0,0 +1,22 @@
+package cli
+
+import (
+	"testing"
+
+	internal "github.com/sassoftware/yj-valid8r/yj-valid8r-common"
+)
+
+func TestApplyOverrides_CheckDuplicateKeysDefault(t *testing.T) {
+	cfg := &internal.ValidationRequest{}
+	applyOverrides(cfg, Options{Data: "data.yaml"})
+	if cfg.CheckDuplicateKeys == nil || *cfg.CheckDuplicateKeys {
+		t.Errorf("Expected checkDuplicateKeys to default to false, got %v", cfg.CheckDuplicateKeys)
+	}
+
+	enabled := true
+	cfg = &internal.ValidationRequest{}
+	applyOverrides(cfg, Options{Data: "data.yaml", CheckDuplicateKeys: &enabled})
+	if cfg.CheckDuplicateKeys == nil || !*cfg.CheckDuplicateKeys {
+		t.Errorf("Expected --checkDuplicateKeys to enable the check, got %v", cfg.CheckDuplicateKeys)
+	}
+}
@@ -0,0 +1,39 @@
+package cli
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	validator "github.com/sassoftware/yj-valid8r/yj-valid8r-lib"
+)
+
+// fixWhitespace rewrites the data file with its whitespace issues fixed and returns the fixed data,
+// which is then validated. In dry-run mode it prints a unified diff of the fixes instead and exits.
+func fixWhitespace(dataPath string, dataBytes []byte, dryRun bool, indentWidth int, rules validator.FormattingRules) []byte {
+	result := validator.FixWhitespace(dataBytes, validator.WhitespaceFixOptions{IndentWidth: indentWidth, Rules: rules})
+
+	if dryRun {
+		fmt.Print(validator.UnifiedDiff(dataPath+".orig", dataPath, dataBytes, result.Data))
+		log.Printf("Dry run: %d whitespace fixes not written to %s", len(result.Messages), dataPath)
+		os.Exit(0)
+	}
+
+	if !result.Changed {
+		log.Printf("No whitespace issues to fix in %s", dataPath)
+		return dataBytes
+	}
+
+	info, err := os.Stat(dataPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(dataPath, result.Data, info.Mode().Perm()); err != nil {
+		log.Fatalf("Failed to write fixed data: %v", err)
+	}
+	for _, message := range result.Messages {
+		log.Println(message)
+	}
+	log.Printf("Fixed %d whitespace issues in %s", len(result.Messages), dataPath)
+	return result.Data
+}
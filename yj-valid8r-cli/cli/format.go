@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	validator "github.com/sassoftware/yj-valid8r/yj-valid8r-lib"
+)
+
+// StartFormat rewrites the data files in the canonical style of validator.FormatData.
+// In check mode the files are left alone: a unified diff is printed for every file that is not
+// formatted, and the command fails if there is any.
+func StartFormat(dataPaths []string, opts validator.FormatOptions, check bool) {
+	if len(dataPaths) == 0 {
+		log.Fatalf("At least one data file must be specified, e.g. format config.yaml")
+	}
+	if err := opts.Validate(); err != nil {
+		log.Fatalf("Invalid format options: %v", err)
+	}
+
+	failed, unformatted := 0, 0
+	for _, dataPath := range dataPaths {
+		dataBytes, err := os.ReadFile(dataPath)
+		if err != nil {
+			log.Printf("Failed to read %s: %v", dataPath, err)
+			failed++
+			continue
+		}
+		result, err := validator.FormatData(dataBytes, opts)
+		if err != nil {
+			log.Printf("Failed to format %s: %v", dataPath, err)
+			failed++
+			continue
+		}
+		if !result.Changed {
+			continue
+		}
+
+		if check {
+			fmt.Print(validator.UnifiedDiff(dataPath+".orig", dataPath, dataBytes, result.Data))
+			unformatted++
+			continue
+		}
+
+		info, err := os.Stat(dataPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := os.WriteFile(dataPath, result.Data, info.Mode().Perm()); err != nil {
+			log.Fatalf("Failed to write formatted data: %v", err)
+		}
+		log.Printf("Formatted %s", dataPath)
+	}
+
+	if check && unformatted > 0 {
+		log.Printf("%d of %d files are not formatted", unformatted, len(dataPaths))
+	}
+	if failed > 0 || unformatted > 0 {
+		os.Exit(1)
+	}
+}
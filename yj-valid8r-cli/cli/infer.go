@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+
+	validator "github.com/sassoftware/yj-valid8r/yj-valid8r-lib"
+
+	"gopkg.in/yaml.v3"
+)
+
+// StartInfer infers a JSON schema from the sample data files and writes it as JSON or YAML
+// to outPath, or to stdout if outPath is empty.
+func StartInfer(samplePaths []string, flagSchemaDraft, flagOutputFormat string, flagMaxEnumValues int, outPath string) {
+	if len(samplePaths) == 0 {
+		log.Fatalf("At least one sample data file must be specified, e.g. infer config1.yaml config2.yaml")
+	}
+
+	schemaDraft, err := validator.ParseSchemaDraft(flagSchemaDraft)
+	if err != nil {
+		log.Fatalf("Invalid schemaDraft: %v", err)
+	}
+
+	inferrer, err := validator.NewSchemaInferrer(validator.SchemaInferenceOptions{
+		Draft:         schemaDraft,
+		MaxEnumValues: flagMaxEnumValues,
+	})
+	if err != nil {
+		log.Fatalf("Invalid schemaDraft: %v", err)
+	}
+
+	for _, samplePath := range samplePaths {
+		dataBytes, err := os.ReadFile(samplePath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := inferrer.Add(dataBytes); err != nil {
+			log.Fatalf("Failed to read sample %s: %v", samplePath, err)
+		}
+	}
+
+	var out []byte
+	switch CLIOutputFormatType(flagOutputFormat) {
+	case CLIOutputFormatTypeJSON:
+		out, err = json.MarshalIndent(inferrer.Schema(), "", "  ")
+		out = append(out, '\n')
+	case CLIOutputFormatTypeYAML, CLIOutputFormatTypeYML:
+		out, err = yaml.Marshal(inferrer.Schema())
+	default:
+		log.Fatalf("outputFormat should be json | yaml, got %q", flagOutputFormat)
+	}
+	if err != nil {
+		log.Fatalf("Failed to marshal schema: %v", err)
+	}
+
+	if outPath == "" {
+		fmt.Print(string(out))
+		return
+	}
+	if err := os.WriteFile(outPath, out, 0644); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Schema inferred from %d sample files written to %s", len(samplePaths), outPath)
+}
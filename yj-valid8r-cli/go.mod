@@ -7,8 +7,15 @@ require (
 	gopkg.in/yaml.v3 v3.0.1
 )
 
-// replace github.com/sassoftware/yj-valid8r/yj-valid8r-lib => ../yj-valid8r-lib // Local replace for development purposes
-// replace github.com/sassoftware/yj-valid8r/yj-valid8r-common => ../yj-valid8r-common // Local replace for development purposes
+require (
+	github.com/pelletier/go-toml/v2 v2.2.2 // indirect
+	github.com/santhosh-tekuri/jsonschema/v6 v6.0.3 // indirect
+	golang.org/x/text v0.14.0 // indirect
+)
+
+replace github.com/sassoftware/yj-valid8r/yj-valid8r-lib => ../yj-valid8r-lib // Local replace for development purposes
+
+replace github.com/sassoftware/yj-valid8r/yj-valid8r-common => ../yj-valid8r-common // Local replace for development purposes
 
 require (
 	github.com/mattn/go-colorable v0.1.13 // indirect
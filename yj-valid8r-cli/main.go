@@ -3,6 +3,7 @@ package main
 import (
 	"encoding/json"
 	"flag"
+	"fmt"
 	"log"
 	"os"
 	"strings"
@@ -12,33 +13,104 @@ import (
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "infer" {
+		inferCommand(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "format" {
+		formatCommand(os.Args[2:])
+		return
+	}
+
 	configPathFlag := flag.String("config", "", "Path to YAML config file")
 	schemaPathsFlag := flag.String("schemas", "", "Comma-separated JSON schema files or urls")
-	dataPathFlag := flag.String("data", "", "Path to YAML or JSON data file")
+	schemaRoutesFlag := flag.String("schemaRoutes", "", "JSON array of schema route objects selecting a schema per document")
+	dataPathFlag := flag.String("data", "", "Path to YAML, JSON, TOML, JSON5 or JSON Lines data file")
 	cliOutputFormatFlag := flag.String("cliOutputFormat", "", "CLI output type: \"json\", \"yaml\", \"legacy\", \"pretty\"")
 	strictValidationFlag := flag.Bool("strictValidation", true, "Fail if validation fails")
 	checkTrailingWhitespaceFlag := flag.Bool("checkTrailingWhitespace", true, "Fail if whitespace errors")
+	checkDuplicateKeysFlag := flag.Bool("checkDuplicateKeys", false, "Fail if a key is defined more than once in the same object")
 	regexPatternRulesFlag := flag.String("regexPatternRules", "", "JSON array of regex pattern rule objects")
 	searchPathsFlag := flag.String("searchPaths", "", "JSON array of path search objects")
 	pluginsFlag := flag.String("plugins", "", "plugin file paths as a comma-separated or newline-separated")
+	schemaCacheDirFlag := flag.String("schemaCacheDir", "", "Directory used to cache remote schemas (enables the schema cache)")
+	schemaCacheTTLFlag := flag.String("schemaCacheTTL", "", "How long cached schemas are used before refetching, e.g. \"24h\" (enables the schema cache)")
+	offlineFlag := flag.Bool("offline", false, "Never touch the network; only use cached or local schemas (enables the schema cache)")
+	schemaPinsFlag := flag.String("schemaPins", "", "JSON object mapping schema URLs to sha256 digests (enables the schema cache)")
+	schemaMappingsFlag := flag.String("schemaMappings", "", "JSON array of {\"prefix\", \"dir\"} objects serving schema URLs from local directories")
+	schemaDraftFlag := flag.String("schemaDraft", "", "JSON Schema draft: \"auto\" (from $schema), \"draft-04\", \"draft-06\", \"draft-07\", \"2019-09\", \"2020-12\"")
+	fixFlag := flag.Bool("fix", false, "Rewrite the data file with whitespace issues fixed before validating it")
+	dryRunFlag := flag.Bool("dryRun", false, "With --fix, print a unified diff of the fixes instead of writing them")
+	indentWidthFlag := flag.Int("indentWidth", 0, "With --fix, number of spaces replacing each tab in the indentation (default: formatting.indentWidth or .editorconfig indent_size, else 2)")
+	editorConfigFlag := flag.Bool("editorConfig", true, "Apply the .editorconfig of the data file to the formatting checks")
+	yamlLintFlag := flag.String("yamlLint", "", "JSON object of YAML lint rules, e.g. {\"lineLength\": {\"max\": 120}}; \"{}\" enables the defaults")
+	maxAliasExpansionFlag := flag.Int("maxAliasExpansion", 0, fmt.Sprintf("Number of nodes YAML aliases may add to a document before it is rejected (default %d); -1 allows any", validator.DefaultMaxAliasExpansion))
+	jsonLinesFlag := flag.String("jsonLines", "", "JSON object enabling JSON Lines mode, every line a record, e.g. {\"maxErrors\": 50}; \"{}\" uses the defaults (on by default for .jsonl and .ndjson files)")
 
 	flag.Parse()
 
 	schemaList := parseCommaList(*schemaPathsFlag)
+	schemaRoutesList := parseJSON[[]validator.SchemaRoute](*schemaRoutesFlag, "schemaRoutes")
 	regexPatternRulesList := parseJSON[[]validator.RegexPatternRules](*regexPatternRulesFlag, "regexPatternRules")
 	searchPathsRulesList := parseJSON[[]validator.SearchPathsDef](*searchPathsFlag, "searchPaths")
+	schemaMappingsList := parseJSON[[]validator.SchemaMapping](*schemaMappingsFlag, "schemaMappings")
+	yamlLintConfig := parseJSON[*validator.YAMLLintConfig](*yamlLintFlag, "yamlLint")
+	jsonLinesConfig := parseJSON[*validator.JSONLinesConfig](*jsonLinesFlag, "jsonLines")
+	schemaCacheConfig := schemaCacheFlags(*schemaCacheDirFlag, *schemaCacheTTLFlag, boolFlag(offlineFlag, "offline"), parseJSON[map[string]string](*schemaPinsFlag, "schemaPins"))
+
+	cli.StartCLI(cli.Options{
+		ConfigPath:              *configPathFlag,
+		Data:                    *dataPathFlag,
+		CLIOutputFormat:         *cliOutputFormatFlag,
+		Plugins:                 *pluginsFlag,
+		Schemas:                 schemaList,
+		SchemaRoutes:            schemaRoutesList,
+		SchemaCache:             schemaCacheConfig,
+		SchemaMappings:          schemaMappingsList,
+		SchemaDraft:             *schemaDraftFlag,
+		StrictValidation:        boolFlag(strictValidationFlag, "strictValidation"),
+		CheckTrailingWhitespace: boolFlag(checkTrailingWhitespaceFlag, "checkTrailingWhitespace"),
+		CheckDuplicateKeys:      boolFlag(checkDuplicateKeysFlag, "checkDuplicateKeys"),
+		EditorConfig:            boolFlag(editorConfigFlag, "editorConfig"),
+		Fix:                     *fixFlag,
+		DryRun:                  *dryRunFlag,
+		IndentWidth:             *indentWidthFlag,
+		YAMLLint:                yamlLintConfig,
+		JSONLines:               jsonLinesConfig,
+		MaxAliasExpansion:       *maxAliasExpansionFlag,
+		RegexPatternRules:       regexPatternRulesList,
+		SearchPaths:             searchPathsRulesList,
+	})
+}
+
+// inferCommand runs `infer [flags] sample...`, which prints a schema inferred from the sample files
+func inferCommand(args []string) {
+	inferFlags := flag.NewFlagSet("infer", flag.ExitOnError)
+	schemaDraftFlag := inferFlags.String("schemaDraft", "draft-07", "JSON Schema draft of the inferred schema: \"draft-07\", \"2020-12\"")
+	outputFormatFlag := inferFlags.String("outputFormat", "json", "Schema output type: \"json\", \"yaml\"")
+	maxEnumValuesFlag := inferFlags.Int("maxEnumValues", validator.DefaultMaxEnumValues, "Strings with at most this many distinct values become an enum; -1 disables enums")
+	outFlag := inferFlags.String("out", "", "Write the schema to this file instead of stdout")
+
+	inferFlags.Parse(args)
+
+	cli.StartInfer(inferFlags.Args(), *schemaDraftFlag, *outputFormatFlag, *maxEnumValuesFlag, *outFlag)
+}
+
+// formatCommand runs `format [flags] file...`, which rewrites the files in a canonical style
+func formatCommand(args []string) {
+	formatFlags := flag.NewFlagSet("format", flag.ExitOnError)
+	indentWidthFlag := formatFlags.Int("indentWidth", validator.DefaultIndentWidth, "Number of spaces per indentation level")
+	sortKeysFlag := formatFlags.Bool("sortKeys", false, "Sort mapping keys instead of keeping their order")
+	quoteStyleFlag := formatFlags.String("quoteStyle", validator.QuoteStyleDouble, "Quotes for YAML strings that need them: \"double\", \"single\"")
+	checkFlag := formatFlags.Bool("check", false, "Do not write the files; print a diff and fail if a file is not formatted")
 
-	cli.StartCLI(
-		*configPathFlag,
-		*dataPathFlag,
-		*cliOutputFormatFlag,
-		*pluginsFlag,
-		schemaList,
-		boolFlag(strictValidationFlag, "strictValidation"),
-		boolFlag(checkTrailingWhitespaceFlag, "checkTrailingWhitespace"),
-		regexPatternRulesList,
-		searchPathsRulesList,
-	)
+	formatFlags.Parse(args)
+
+	cli.StartFormat(formatFlags.Args(), validator.FormatOptions{
+		IndentWidth: *indentWidthFlag,
+		SortKeys:    *sortKeysFlag,
+		QuoteStyle:  *quoteStyleFlag,
+	}, *checkFlag)
 }
 
 func parseCommaList(input string) []string {
@@ -61,6 +133,18 @@ func parseJSON[T any](input string, flagName string) T {
 	return result
 }
 
+// schemaCacheFlags returns the schema cache settings passed via flags, or nil if none were passed
+func schemaCacheFlags(dir, ttl string, offline *bool, pins map[string]string) *validator.SchemaCacheConfig {
+	if dir == "" && ttl == "" && offline == nil && len(pins) == 0 {
+		return nil
+	}
+	cfg := &validator.SchemaCacheConfig{Dir: dir, TTL: ttl, Pins: pins}
+	if offline != nil {
+		cfg.Offline = *offline
+	}
+	return cfg
+}
+
 func boolFlag(ptr *bool, name string) *bool {
 	if isFlagPassed(name) {
 		return ptr
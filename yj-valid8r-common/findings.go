@@ -0,0 +1,96 @@
+package internal
+
+import (
+	"regexp"
+	"strconv"
+
+	validator "github.com/sassoftware/yj-valid8r/yj-valid8r-lib"
+)
+
+// linePrefix matches the "Line 5: " prefix plugins conventionally put on their messages
+var linePrefix = regexp.MustCompile(`^Line (\d+): `)
+
+// collectFindings gathers the findings of every check into one list: whitespace and duplicate keys, YAML lint,
+// regex patterns, search path expectations, schemas, plugins
+func collectFindings(dataFindings []validator.Finding, resp ValidationResponse) []validator.Finding {
+	findings := append([]validator.Finding(nil), dataFindings...)
+	if resp.YAMLLint != nil {
+		findings = append(findings, resp.YAMLLint.Findings...)
+	}
+	for _, output := range resp.RegexPatterns {
+		findings = append(findings, output.Findings...)
+	}
+	for _, output := range resp.PathSearchOutput {
+		findings = append(findings, output.Findings...)
+	}
+	for _, result := range resp.SchemaResults {
+		findings = append(findings, result.Findings...)
+	}
+	for _, result := range resp.PluginResults {
+		findings = append(findings, result.Findings...)
+	}
+	return findings
+}
+
+// pluginFindings turns the message strings returned by a plugin into findings
+func pluginFindings(result PluginResult) []validator.Finding {
+	ruleID := "plugin/" + result.Name
+	var findings []validator.Finding
+	if result.LoadError != "" {
+		findings = append(findings, pluginFinding(validator.MessageTypeError, ruleID, result.LoadError))
+	}
+	for _, msg := range result.Errors {
+		findings = append(findings, pluginFinding(validator.MessageTypeError, ruleID, msg))
+	}
+	for _, msg := range result.Warnings {
+		findings = append(findings, pluginFinding(validator.MessageTypeWarning, ruleID, msg))
+	}
+	for _, msg := range result.Messages {
+		findings = append(findings, pluginFinding(validator.MessageTypeInfo, ruleID, msg))
+	}
+	return findings
+}
+
+// pluginFinding builds a finding, taking the line from a "Line N: " message prefix if present
+func pluginFinding(severity validator.ValidationMessageType, ruleID, msg string) validator.Finding {
+	finding := validator.Finding{Severity: severity, RuleID: ruleID, Message: msg}
+	if match := linePrefix.FindStringSubmatch(msg); match != nil {
+		finding.Line, _ = strconv.Atoi(match[1])
+		finding.Message = msg[len(match[0]):]
+	}
+	return finding
+}
+
+// SetFile records the data file on every finding of the response
+func (r *ValidationResponse) SetFile(file string) {
+	setFile(r.Findings, file)
+	for i := range r.SchemaResults {
+		setFile(r.SchemaResults[i].Findings, file)
+	}
+	for i := range r.RegexPatterns {
+		setFile(r.RegexPatterns[i].Findings, file)
+	}
+	for i := range r.PathSearchOutput {
+		setFile(r.PathSearchOutput[i].Findings, file)
+	}
+	for i := range r.Documents {
+		for j := range r.Documents[i].SchemaResults {
+			setFile(r.Documents[i].SchemaResults[j].Findings, file)
+		}
+		for j := range r.Documents[i].PathSearchOutput {
+			setFile(r.Documents[i].PathSearchOutput[j].Findings, file)
+		}
+	}
+	for i := range r.PluginResults {
+		setFile(r.PluginResults[i].Findings, file)
+	}
+	if r.YAMLLint != nil {
+		setFile(r.YAMLLint.Findings, file)
+	}
+}
+
+func setFile(findings []validator.Finding, file string) {
+	for i := range findings {
+		findings[i].File = file
+	}
+}
@@ -0,0 +1,108 @@
+package internal
+
+import (
+	"fmt"
+	"sort"
+
+	validator "github.com/sassoftware/yj-valid8r/yj-valid8r-lib"
+)
+
+// validateRecords validates every JSON Lines record against the schema. A record that cannot be decoded,
+// e.g. because of a duplicate key, is reported on its own instead of failing the other records.
+func validateRecords(schema *validator.CompiledSchema, docs []validator.Document) ([]validator.SchemaValidationMessage, error) {
+	var messages []validator.SchemaValidationMessage
+	for _, doc := range docs {
+		docMessages, err := schema.ValidateDocument(doc)
+		if err != nil {
+			finding := validator.Finding{
+				Severity: validator.MessageTypeError,
+				RuleID:   validator.RuleSchemaDecode,
+				Message:  fmt.Sprintf("Record not validated: %v", err),
+				Document: doc.Index,
+				Line:     doc.StartLine(),
+			}
+			docMessages = []validator.SchemaValidationMessage{{
+				Type:     validator.MessageTypeError,
+				Message:  fmt.Sprintf("Line %d: %s", finding.Line, finding.Message),
+				Document: doc.Index,
+				Finding:  finding,
+			}}
+		}
+		messages = append(messages, docMessages...)
+	}
+	return messages, nil
+}
+
+// finishJSONLines completes the response of a JSON Lines validation: records that are not valid JSON join the
+// per-record results, which then only list invalid records; the records are counted; the combined findings are
+// put in line order; and every list of errors is cut to its first maxErrors.
+func finishJSONLines(resp *ValidationResponse, invalidRecords []validator.Finding, maxErrors int) {
+	for _, finding := range invalidRecords {
+		resp.Documents = append(resp.Documents, DocumentResult{
+			Index:     finding.Document,
+			StartLine: finding.Line,
+			Errors:    []string{finding.Message},
+		})
+	}
+	sort.SliceStable(resp.Documents, func(i, j int) bool { return resp.Documents[i].Index < resp.Documents[j].Index })
+
+	counts := &JSONLinesSummary{Records: len(resp.Documents)}
+	var invalid []DocumentResult
+	for _, document := range resp.Documents {
+		if document.Valid {
+			counts.ValidRecords++
+		} else {
+			invalid = append(invalid, document)
+		}
+	}
+	counts.InvalidRecords = len(invalid)
+
+	sort.SliceStable(resp.Findings, func(i, j int) bool {
+		a, b := resp.Findings[i], resp.Findings[j]
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	var omitted int
+	resp.Findings, counts.Errors, omitted = limitErrorFindings(resp.Findings, maxErrors)
+	counts.OmittedErrors = omitted
+
+	resp.Documents, _ = limitList(invalid, maxErrors)
+	resp.ValidationSummary.Errors, _ = limitList(resp.ValidationSummary.Errors, maxErrors)
+	for i := range resp.SchemaResults {
+		resp.SchemaResults[i].Errors, _ = limitList(resp.SchemaResults[i].Errors, maxErrors)
+		resp.SchemaResults[i].Findings, _, _ = limitErrorFindings(resp.SchemaResults[i].Findings, maxErrors)
+	}
+
+	if omitted > 0 {
+		resp.ValidationSummary.Messages = append(resp.ValidationSummary.Messages,
+			fmt.Sprintf("%d of %d errors not reported (jsonLines.maxErrors is %d).", omitted, counts.Errors, maxErrors))
+	}
+	resp.ValidationSummary.JSONLines = counts
+}
+
+// limitList keeps the first limit items, returning how many were dropped
+func limitList[T any](items []T, limit int) ([]T, int) {
+	if len(items) <= limit {
+		return items, 0
+	}
+	return items[:limit], len(items) - limit
+}
+
+// limitErrorFindings keeps every warning and info finding but only the first limit errors,
+// returning the number of errors found and dropped
+func limitErrorFindings(findings []validator.Finding, limit int) ([]validator.Finding, int, int) {
+	var kept []validator.Finding
+	errors := 0
+	for _, finding := range findings {
+		if finding.Severity == validator.MessageTypeError {
+			errors++
+			if errors > limit {
+				continue
+			}
+		}
+		kept = append(kept, finding)
+	}
+	return kept, errors, max(errors-limit, 0)
+}
@@ -35,6 +35,7 @@ func UsePlugin(pluginPaths string, dataBytes []byte) []PluginResult {
 	for _, p := range plugins {
 		// If plugin loading failed, report it and skip execution
 		if p.Result.LoadError != "" {
+			p.Result.Findings = pluginFindings(p.Result)
 			pluginResults = append(pluginResults, p.Result)
 			continue
 		}
@@ -47,6 +48,7 @@ func UsePlugin(pluginPaths string, dataBytes []byte) []PluginResult {
 		p.Result.Warnings = warns
 		p.Result.Errors = errs
 		p.Result.ExecutionTime = elapsed
+		p.Result.Findings = pluginFindings(p.Result)
 
 		pluginResults = append(pluginResults, p.Result)
 	}
@@ -10,37 +10,87 @@ import (
 type ValidationRequest struct {
 	CLIOutputFormat         string                        `json:"-" yaml:"cliOutputFormat"` // omit from JSON
 	Schemas                 []string                      `json:"schemas" yaml:"schemas"`
+	SchemaRoutes            []validator.SchemaRoute       `json:"schemaRoutes" yaml:"schemaRoutes"`
+	SchemaCache             *validator.SchemaCacheConfig  `json:"-" yaml:"schemaCache"`    // omit from JSON
+	SchemaMappings          []validator.SchemaMapping     `json:"-" yaml:"schemaMappings"` // omit from JSON
+	SchemaDraft             string                        `json:"-" yaml:"schemaDraft"`    // omit from JSON
 	Data                    string                        `json:"data" yaml:"data"`
 	CheckTrailingWhitespace *bool                         `json:"checkTrailingWhitespace" yaml:"checkTrailingWhitespace"`
-	StrictValidation        *bool                         `json:"-" yaml:"strictValidation"` // omit from JSON
+	Formatting              validator.FormattingRules     `json:"formatting" yaml:"formatting"` // Formatting rules checked with checkTrailingWhitespace
+	EditorConfig            *bool                         `json:"-" yaml:"editorConfig"`        // Apply the data file's .editorconfig; omit from JSON
+	CheckDuplicateKeys      *bool                         `json:"checkDuplicateKeys" yaml:"checkDuplicateKeys"`
+	YAMLLint                *validator.YAMLLintConfig     `json:"yamlLint" yaml:"yamlLint"`   // YAML style rules; lint is skipped when nil
+	JSONLines               *validator.JSONLinesConfig    `json:"jsonLines" yaml:"jsonLines"` // Validate every line as a record (JSON Lines / NDJSON); off when nil
+	MaxAliasExpansion       int                           `json:"-" yaml:"maxAliasExpansion"` // Nodes YAML aliases may add to a document; omit from JSON
+	StrictValidation        *bool                         `json:"-" yaml:"strictValidation"`  // omit from JSON
 	RegexPatternRules       []validator.RegexPatternRules `json:"regexPatternRules" yaml:"regexPatternRules"`
 	SearchPaths             []validator.SearchPathsDef    `json:"searchPaths" yaml:"searchPaths"`
 	Plugins                 string                        `json:"plugins" yaml:"plugins"`
 }
 
+// ValidationOptions: checks InitValidation runs, resolved from a ValidationRequest by cli and web
+type ValidationOptions struct {
+	Detection               *validator.DataFormatDetection // Format of the data, e.g. as reported to the user; detected when nil
+	Schemas                 []string
+	SchemaRoutes            []validator.SchemaRoute
+	SchemaRegistry          *validator.SchemaRegistry // Shared compiled schemas; a registry without cache is used when nil
+	JSONLines               *validator.JSONLinesConfig
+	MaxAliasExpansion       int
+	CheckTrailingWhitespace bool // Check the formatting rules
+	Formatting              validator.FormattingRules
+	CheckDuplicateKeys      bool
+	YAMLLint                *validator.YAMLLintConfig
+	RegexPatternRules       []validator.RegexPatternRules
+	SearchPaths             []validator.SearchPathsDef
+	Plugins                 string
+}
+
 // ValidationResponse: output from cli and web
 type SchemaResult struct {
-	Schema   string   `json:"schema"`
-	Valid    bool     `json:"valid"`
-	Errors   []string `json:"errors,omitempty"`
-	Warnings []string `json:"warnings,omitempty"`
+	Schema   string              `json:"schema"`
+	Valid    bool                `json:"valid"`
+	Errors   []string            `json:"errors,omitempty"`
+	Warnings []string            `json:"warnings,omitempty"`
+	Findings []validator.Finding `json:"findings,omitempty"`
 }
 
 type ValidationSummary struct {
-	ValidationDataType string   `json:"validationDataType"`
-	Valid              bool     `json:"valid"`
-	Errors             []string `json:"errors,omitempty"`
-	Warnings           []string `json:"warnings,omitempty"`
-	Messages           []string `json:"messages,omitempty"`
+	ValidationDataType string            `json:"validationDataType"`
+	DocumentCount      int               `json:"documentCount"`
+	Valid              bool              `json:"valid"`
+	Errors             []string          `json:"errors,omitempty"`
+	Warnings           []string          `json:"warnings,omitempty"`
+	Messages           []string          `json:"messages,omitempty"`
+	JSONLines          *JSONLinesSummary `json:"jsonLines,omitempty"` // Record counts in JSON Lines mode
+}
+
+// JSONLinesSummary: aggregate counts of a JSON Lines validation
+type JSONLinesSummary struct {
+	Records        int `json:"records"`
+	ValidRecords   int `json:"validRecords"`
+	InvalidRecords int `json:"invalidRecords"`
+	Errors         int `json:"errors"`        // Errors found, including those not reported
+	OmittedErrors  int `json:"omittedErrors"` // Errors left out of the output by jsonLines.maxErrors
+}
+
+// DocumentResult: per-document output for multi-document YAML streams and JSON Lines records
+type DocumentResult struct {
+	Index            int                           `json:"index"`
+	StartLine        int                           `json:"startLine"`
+	Valid            bool                          `json:"valid"`
+	Errors           []string                      `json:"errors,omitempty"` // Errors of the record itself, e.g. invalid JSON in JSON Lines mode
+	SchemaResults    []SchemaResult                `json:"schemaResults,omitempty"`
+	PathSearchOutput []validator.SearchPathsOutput `json:"pathSearchOutput,omitempty"`
 }
 
 type PluginResult struct {
-	Name          string        `json:"name"`
-	Messages      []string      `json:"messages,omitempty"`
-	Warnings      []string      `json:"warnings,omitempty"`
-	Errors        []string      `json:"errors,omitempty"`
-	LoadError     string        `json:"load_error,omitempty"` // Load/init error
-	ExecutionTime time.Duration `json:"execution_time"`
+	Name          string              `json:"name"`
+	Messages      []string            `json:"messages,omitempty"`
+	Warnings      []string            `json:"warnings,omitempty"`
+	Errors        []string            `json:"errors,omitempty"`
+	LoadError     string              `json:"load_error,omitempty"` // Load/init error
+	ExecutionTime time.Duration       `json:"execution_time"`
+	Findings      []validator.Finding `json:"findings,omitempty"`
 }
 
 type ValidationResponse struct {
@@ -48,5 +98,8 @@ type ValidationResponse struct {
 	SchemaResults     []SchemaResult                      `json:"schemaResults,omitempty"`
 	RegexPatterns     []validator.RegexPatternRulesOutput `json:"regexPatterns,omitempty"`
 	PathSearchOutput  []validator.SearchPathsOutput       `json:"pathSearchOutput,omitempty"`
+	Documents         []DocumentResult                    `json:"documents,omitempty"`
 	PluginResults     []PluginResult                      `json:"pluginResults,omitempty"`
+	YAMLLint          *validator.YAMLLintResult           `json:"yamlLint,omitempty"`
+	Findings          []validator.Finding                 `json:"findings,omitempty"` // All findings of every check, in one list
 }
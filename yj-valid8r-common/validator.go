@@ -7,103 +7,381 @@ import (
 	validator "github.com/sassoftware/yj-valid8r/yj-valid8r-lib"
 )
 
-func InitValidation(
-	schemas []string,
-	dataBytes []byte,
-	whitespace bool,
-	regexPatterns []validator.RegexPatternRules,
-	pathSearch []validator.SearchPathsDef,
-	pluginPaths string,
-) ValidationResponse {
+// InitValidation runs the checks enabled in opts on the data and collects their results.
+func InitValidation(dataBytes []byte, opts ValidationOptions) ValidationResponse {
 	summary := ValidationSummary{}
 	var regexFindings []validator.RegexPatternRulesOutput
 	var pathSearchFindings []validator.SearchPathsOutput
-	results := make([]SchemaResult, 0, len(schemas))
+	var dataFindings []validator.Finding // findings of the checks on the raw data: whitespace, duplicate keys
+	var yamlLintResult *validator.YAMLLintResult
+	results := make([]SchemaResult, 0, len(opts.Schemas))
 	hasError := false
 
-	if whitespace {
-		wsResult := validator.CheckTabsAndWhitespacesFinder(dataBytes)
-		if len(wsResult.Errors) > 0 {
-			summary.Valid = false
+	// Schemas are compiled once per registry; callers share one across runs to avoid refetching.
+	if opts.SchemaRegistry == nil {
+		opts.SchemaRegistry = validator.NewSchemaRegistry(validator.SchemaOptions{})
+	}
+
+	// The format is detected once; every check below works on the detected format or the decoded documents.
+	// In JSON Lines mode every line is a record; lines that are not valid JSON are reported on their own.
+	detection := opts.Detection
+	if detection == nil {
+		detected := validator.DetectDataFormat(dataBytes)
+		detection = &detected
+	}
+	dataType := detection.Format
+	maxErrors := validator.DefaultJSONLinesMaxErrors
+	var docs []validator.Document
+	var docsErr error
+	var invalidRecords []validator.Finding
+	if opts.JSONLines != nil {
+		dataType = validator.DataTypeNDJSON
+		if err := opts.JSONLines.Validate(); err != nil {
+			hasError = true
+			summary.Errors = append(summary.Errors, err.Error())
+		} else {
+			maxErrors = opts.JSONLines.Limit()
+		}
+		docs, invalidRecords = validator.DecodeJSONLines(dataBytes)
+		for _, finding := range invalidRecords {
+			hasError = true
+			summary.Errors = append(summary.Errors, fmt.Sprintf("Line %d: %s", finding.Line, finding.Message))
+		}
+	} else {
+		docs, docsErr = validator.DecodeDocumentsAs(dataBytes, dataType)
+	}
+
+	// Nothing below runs on data whose aliases expand beyond the limit: decoding it could exhaust memory.
+	if err := validator.CheckAliasExpansion(docs, opts.MaxAliasExpansion); err != nil {
+		summary.Errors = append(summary.Errors, err.Error())
+		summary.ValidationDataType = strings.ToUpper(dataType)
+		summary.DocumentCount = len(docs)
+		return ValidationResponse{
+			ValidationSummary: summary,
+			Findings:          []validator.Finding{{Severity: validator.MessageTypeError, RuleID: validator.RuleAliasExpansion, Message: err.Error()}},
+		}
+	}
+
+	// Per-document results are only reported for multi-document YAML streams and JSON Lines records,
+	// whose messages already name their line.
+	multiDoc := len(docs) > 1 || opts.JSONLines != nil
+	prefixDocument := multiDoc && opts.JSONLines == nil
+	var documents []DocumentResult
+	if multiDoc {
+		for _, doc := range docs {
+			documents = append(documents, DocumentResult{Index: doc.Index, StartLine: doc.StartLine(), Valid: true})
 		}
-		summary.Errors = append(summary.Errors, wsResult.Errors...)
-		summary.Warnings = append(summary.Warnings, wsResult.Warnings...)
-		summary.Messages = append(summary.Messages, wsResult.Messages...)
 	}
 
-	if len(regexPatterns) > 0 {
-		regexFindings, hasError = validator.RegexPatternRulesFinder(regexPatterns, dataBytes)
-		if hasError {
+	if opts.CheckTrailingWhitespace {
+		if err := opts.Formatting.Validate(); err != nil {
+			hasError = true
+			summary.Errors = append(summary.Errors, err.Error())
+		} else {
+			wsResult := validator.CheckFormatting(dataBytes, opts.Formatting)
+			if len(wsResult.Errors) > 0 {
+				hasError = true
+			}
+			summary.Errors = append(summary.Errors, wsResult.Errors...)
+			summary.Warnings = append(summary.Warnings, wsResult.Warnings...)
+			summary.Messages = append(summary.Messages, wsResult.Messages...)
+			dataFindings = wsResult.Findings
+		}
+	}
+
+	if opts.CheckDuplicateKeys {
+		dupResult := validator.CheckDuplicateKeysFinder(dataBytes)
+		if opts.JSONLines != nil {
+			dupResult = validator.CheckJSONLinesDuplicateKeysFinder(dataBytes)
+		}
+		if !dupResult.Valid {
+			hasError = true
+		}
+		summary.Errors = append(summary.Errors, dupResult.Errors...)
+		summary.Messages = append(summary.Messages, dupResult.Messages...)
+		dataFindings = append(dataFindings, dupResult.Findings...)
+		for _, finding := range dupResult.Findings {
+			for i := range documents {
+				if documents[i].Index == finding.Document {
+					documents[i].Valid = false
+				}
+			}
+		}
+	}
+
+	if opts.YAMLLint != nil {
+		if dataType != validator.DataTypeYAML {
+			summary.Messages = append(summary.Messages, fmt.Sprintf("YAML lint skipped for %s data.", strings.ToUpper(dataType)))
+		} else if err := opts.YAMLLint.Validate(); err != nil {
+			hasError = true
+			summary.Errors = append(summary.Errors, err.Error())
+		} else {
+			lintConfig := *opts.YAMLLint
+			if opts.CheckDuplicateKeys {
+				// already reported by the duplicate key check
+				disabled := false
+				lintConfig.KeyDuplicates.Enabled = &disabled
+			}
+			result := validator.LintYAML(dataBytes, lintConfig)
+			if !result.Valid {
+				hasError = true
+			}
+			yamlLintResult = &result
+		}
+	}
+
+	if len(opts.RegexPatternRules) > 0 {
+		var strictError bool
+		regexFindings, strictError = validator.RegexPatternRulesFinder(opts.RegexPatternRules, dataBytes)
+		if strictError {
+			hasError = true
 			summary.Errors = append(summary.Errors, "Environment variable(s) not set. Strict mode is true.")
 		}
 	}
 
-	if len(pathSearch) > 0 {
+	if len(opts.SearchPaths) > 0 {
 		var err error
-		pathSearchFindings, err = validator.SearchPathsFinder(dataBytes, pathSearch)
+		if docsErr != nil {
+			err = fmt.Errorf("parse yaml/json into map: %w", docsErr)
+		} else {
+			// With the limit the aliases were checked against above
+			pathSearchFindings, err = validator.SearchPathsInDocuments(docs, opts.SearchPaths, opts.MaxAliasExpansion)
+		}
 		if err != nil {
 			hasError = true
 			summary.Messages = append(summary.Messages, fmt.Sprintf("parse yaml/json into node: %v", err))
 		}
+		for _, output := range pathSearchFindings {
+			if len(output.Errors) > 0 {
+				hasError = true // an invalid path, or a value that fails an expectation
+			}
+		}
+		for i := range documents {
+			documents[i].PathSearchOutput = pathSearchForDocument(pathSearchFindings, documents[i].Index)
+			for _, output := range documents[i].PathSearchOutput {
+				if len(output.Errors) > 0 {
+					documents[i].Valid = false
+				}
+			}
+		}
 	}
 
-	if len(schemas) > 0 {
-		for _, schemaPath := range schemas {
-			messages, err := validator.ValidateAgainstSchemaFinder(schemaPath, dataBytes)
+	if len(opts.Schemas) > 0 {
+		for _, schemaPath := range opts.Schemas {
+			var messages []validator.SchemaValidationMessage
+			schema, err := opts.SchemaRegistry.Get(schemaPath)
+			if err == nil && opts.JSONLines != nil {
+				messages, err = validateRecords(schema, docs)
+			} else if err == nil && docsErr != nil {
+				err = fmt.Errorf("parse yaml/json into node: %w", docsErr)
+			} else if err == nil {
+				messages, err = schema.ValidateDocuments(docs)
+			}
 			if err != nil {
-				results = append(results, SchemaResult{
-					Schema: schemaPath,
-					Valid:  false,
-					Errors: []string{err.Error()},
-				})
+				loadFindings := []validator.Finding{schemaLoadFinding(err)}
+				results = append(results, newSchemaResult(schemaPath, []string{err.Error()}, nil, loadFindings))
+				for i := range documents {
+					documents[i].Valid = false
+					documents[i].SchemaResults = append(documents[i].SchemaResults, newSchemaResult(schemaPath, []string{err.Error()}, nil, loadFindings))
+				}
 				hasError = true
 				continue
 			}
 
 			var errors []string
 			var warnings []string
+			var findings []validator.Finding
+			docErrors := make(map[int][]string)
+			docWarnings := make(map[int][]string)
+			docFindings := make(map[int][]validator.Finding)
 
 			for _, msg := range messages {
+				findings = append(findings, msg.Finding)
+				docFindings[msg.Document] = append(docFindings[msg.Document], msg.Finding)
+				text := msg.Message
+				if prefixDocument {
+					text = fmt.Sprintf("Document %d: %s", msg.Document, msg.Message)
+				}
 				switch msg.Type {
 				case validator.MessageTypeError:
-					errors = append(errors, msg.Message)
+					errors = append(errors, text)
+					docErrors[msg.Document] = append(docErrors[msg.Document], msg.Message)
 				case validator.MessageTypeWarning:
-					warnings = append(warnings, msg.Message)
+					warnings = append(warnings, text)
+					docWarnings[msg.Document] = append(docWarnings[msg.Document], msg.Message)
 				}
 			}
 
 			if len(errors) > 0 {
 				hasError = true
-				results = append(results, SchemaResult{
-					Schema:   schemaPath,
-					Valid:    false,
-					Errors:   errors,
-					Warnings: warnings,
-				})
-			} else {
-				results = append(results, SchemaResult{
-					Schema:   schemaPath,
-					Valid:    true,
-					Warnings: warnings,
-				})
+			}
+			results = append(results, newSchemaResult(schemaPath, errors, warnings, findings))
+
+			for i := range documents {
+				index := documents[i].Index
+				if len(docErrors[index]) > 0 {
+					documents[i].Valid = false
+				}
+				documents[i].SchemaResults = append(documents[i].SchemaResults, newSchemaResult(schemaPath, docErrors[index], docWarnings[index], docFindings[index]))
 			}
 		}
-	} else {
+	}
+
+	if len(opts.SchemaRoutes) > 0 {
+		routedResults, routedError := validateRoutedSchemas(opts.SchemaRoutes, opts.SchemaRegistry, docs, documents, prefixDocument, &summary)
+		results = append(results, routedResults...)
+		if routedError {
+			hasError = true
+		}
+	}
+
+	if len(opts.Schemas) == 0 && len(opts.SchemaRoutes) == 0 {
 		summary.Messages = append(summary.Messages, "No schema(s) provided.")
 	}
 
 	summary.Valid = !hasError
-	summary.ValidationDataType = strings.ToUpper(validator.DetectDataType(dataBytes))
+	summary.ValidationDataType = strings.ToUpper(dataType)
+	if opts.JSONLines == nil && detection.Confidence == validator.ConfidenceMedium {
+		summary.Messages = append(summary.Messages, "Data detected as flow-style YAML; if it is meant to be JSON, check its syntax.")
+	}
+	summary.DocumentCount = len(docs) + len(invalidRecords)
 
-	pluginResults := UsePlugin(pluginPaths, dataBytes)
+	pluginResults := UsePlugin(opts.Plugins, dataBytes)
 
 	resp := ValidationResponse{
 		SchemaResults:     results,
 		ValidationSummary: summary,
 		RegexPatterns:     regexFindings,
 		PathSearchOutput:  pathSearchFindings,
+		Documents:         documents,
 		PluginResults:     pluginResults,
+		YAMLLint:          yamlLintResult,
+	}
+	resp.Findings = collectFindings(append(dataFindings, invalidRecords...), resp)
+	if opts.JSONLines != nil {
+		finishJSONLines(&resp, invalidRecords, maxErrors)
 	}
 
 	return resp
 }
+
+// validateRoutedSchemas validates every document against the schema selected by the first matching route.
+// Results are grouped per selected schema location.
+func validateRoutedSchemas(
+	routes []validator.SchemaRoute,
+	schemaRegistry *validator.SchemaRegistry,
+	docs []validator.Document,
+	documents []DocumentResult,
+	prefixDocument bool,
+	summary *ValidationSummary,
+) ([]SchemaResult, bool) {
+	var results []SchemaResult
+	positions := make(map[string]int) // schema location -> index in results
+	hasError := false
+
+	for i, doc := range docs {
+		location, matched, err := validator.SelectSchema(routes, doc)
+		if err != nil {
+			hasError = true
+			summary.Errors = append(summary.Errors, fmt.Sprintf("Document %d: %v", doc.Index, err))
+			continue
+		}
+		if !matched {
+			summary.Warnings = append(summary.Warnings, fmt.Sprintf("Document %d: no schema route matched.", doc.Index))
+			continue
+		}
+
+		pos, ok := positions[location]
+		if !ok {
+			pos = len(results)
+			positions[location] = pos
+			results = append(results, newSchemaResult(location, nil, nil, nil))
+		}
+
+		var messages []validator.SchemaValidationMessage
+		schema, err := schemaRegistry.Get(location)
+		if err == nil {
+			messages, err = schema.ValidateDocument(doc)
+		}
+		if err != nil {
+			finding := schemaLoadFinding(err)
+			finding.Document = doc.Index
+			messages = []validator.SchemaValidationMessage{{Type: validator.MessageTypeError, Message: err.Error(), Document: doc.Index, Finding: finding}}
+		}
+
+		docResult := newSchemaResult(location, nil, nil, nil)
+		for _, msg := range messages {
+			results[pos].Findings = append(results[pos].Findings, msg.Finding)
+			docResult.Findings = append(docResult.Findings, msg.Finding)
+			text := msg.Message
+			if prefixDocument {
+				text = fmt.Sprintf("Document %d: %s", msg.Document, msg.Message)
+			}
+			switch msg.Type {
+			case validator.MessageTypeError:
+				results[pos].Errors = append(results[pos].Errors, text)
+				docResult.Errors = append(docResult.Errors, msg.Message)
+			case validator.MessageTypeWarning:
+				results[pos].Warnings = append(results[pos].Warnings, text)
+				docResult.Warnings = append(docResult.Warnings, msg.Message)
+			}
+		}
+
+		if len(docResult.Errors) > 0 {
+			hasError = true
+			docResult.Valid = false
+			results[pos].Valid = false
+		}
+		if documents != nil {
+			documents[i].SchemaResults = append(documents[i].SchemaResults, docResult)
+			if !docResult.Valid {
+				documents[i].Valid = false
+			}
+		}
+	}
+
+	return results, hasError
+}
+
+// newSchemaResult builds a SchemaResult, valid when no errors were reported
+func newSchemaResult(schemaPath string, errors, warnings []string, findings []validator.Finding) SchemaResult {
+	return SchemaResult{
+		Schema:   schemaPath,
+		Valid:    len(errors) == 0,
+		Errors:   errors,
+		Warnings: warnings,
+		Findings: findings,
+	}
+}
+
+// schemaLoadFinding reports a schema that could not be fetched or compiled
+func schemaLoadFinding(err error) validator.Finding {
+	return validator.Finding{Severity: validator.MessageTypeError, RuleID: validator.RuleSchemaLoad, Message: err.Error()}
+}
+
+// pathSearchForDocument keeps only the path search results and failed expectations of the given document
+func pathSearchForDocument(outputs []validator.SearchPathsOutput, index int) []validator.SearchPathsOutput {
+	var filtered []validator.SearchPathsOutput
+	for _, output := range outputs {
+		docOutput := validator.SearchPathsOutput{PathName: output.PathName, PathKey: output.PathKey}
+		for _, item := range output.Results {
+			if item.Document == index {
+				docOutput.Results = append(docOutput.Results, item)
+			}
+		}
+		for _, finding := range output.Findings {
+			if finding.Document != index || finding.RuleID == validator.RuleSearchPathInvalid {
+				continue
+			}
+			docOutput.Findings = append(docOutput.Findings, finding)
+			text := fmt.Sprintf("Line %d: %s", finding.Line, finding.Message)
+			if finding.Severity == validator.MessageTypeError {
+				docOutput.Errors = append(docOutput.Errors, text)
+			} else {
+				docOutput.Warnings = append(docOutput.Warnings, text)
+			}
+		}
+		filtered = append(filtered, docOutput)
+	}
+	return filtered
+}
@@ -0,0 +1,266 @@
+package yjvalid8r_lib
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CheckDuplicateKeysFinder reports keys defined more than once in the same object or mapping.
+// Decoders silently keep only one of the values, so a duplicate usually hides a mistake.
+// JSON data is checked on its raw token stream (every line of JSON Lines data), other data on its node tree
+// (every document of a YAML stream). Each finding is placed at the repeated key and names the line of the first definition.
+func CheckDuplicateKeysFinder(dataBytes []byte) DuplicateKeysCheckResult {
+	report := newFindingReport()
+
+	var err error
+	switch dataType := DetectDataType(dataBytes); dataType {
+	case DataTypeJSON:
+		err = checkJSONDuplicateKeys(dataBytes, 0, len(dataBytes), 0, report)
+	case DataTypeNDJSON:
+		checkJSONLinesDuplicateKeys(dataBytes, report)
+	default:
+		err = checkYAMLDuplicateKeys(dataBytes, dataType, report)
+	}
+	if err != nil {
+		report.messages = append(report.messages, fmt.Sprintf("Duplicate key check skipped: %v", err))
+	}
+	return duplicateKeysResult(report)
+}
+
+// CheckJSONLinesDuplicateKeysFinder reports the duplicate keys of every record of JSON Lines data, like
+// CheckDuplicateKeysFinder. Lines that are not valid JSON are skipped; DecodeJSONLines reports them.
+func CheckJSONLinesDuplicateKeysFinder(dataBytes []byte) DuplicateKeysCheckResult {
+	report := newFindingReport()
+	checkJSONLinesDuplicateKeys(dataBytes, report)
+	return duplicateKeysResult(report)
+}
+
+// checkJSONLinesDuplicateKeys checks every line of JSON Lines data that is valid JSON on its own.
+func checkJSONLinesDuplicateKeys(dataBytes []byte, report *findingReport) {
+	for _, line := range splitJSONLines(dataBytes) {
+		if json.Valid(dataBytes[line.start:line.end]) {
+			_ = checkJSONDuplicateKeys(dataBytes, line.start, line.end, line.index, report)
+		}
+	}
+}
+
+func duplicateKeysResult(report *findingReport) DuplicateKeysCheckResult {
+	return DuplicateKeysCheckResult{
+		Valid:    len(report.errors) == 0,
+		Errors:   report.errors,
+		Messages: report.messages,
+		Findings: report.sortedFindings(),
+	}
+}
+
+// duplicateKeyMessage describes a repeated key and where it was first defined.
+func duplicateKeyMessage(key string, firstLine int) string {
+	return fmt.Sprintf("Duplicate key %q, first defined at line %d.", key, firstLine)
+}
+
+// checkYAMLDuplicateKeys reports the duplicate keys of every mapping of data decoded as the detected dataType,
+// e.g. every document of a YAML stream.
+func checkYAMLDuplicateKeys(dataBytes []byte, dataType string, report *findingReport) error {
+	docs, err := DecodeDocumentsAs(dataBytes, dataType)
+	if err != nil {
+		return err
+	}
+	for _, doc := range docs {
+		walkYAMLDuplicateKeys(doc.Root(), nil, doc.Index, report)
+	}
+	return nil
+}
+
+func walkYAMLDuplicateKeys(node *yaml.Node, path []string, document int, report *findingReport) {
+	if node == nil {
+		return
+	}
+	switch node.Kind {
+	case yaml.SequenceNode:
+		for i, child := range node.Content {
+			walkYAMLDuplicateKeys(child, appendPath(path, strconv.Itoa(i)), document, report)
+		}
+	case yaml.MappingNode:
+		firsts := make(map[*yaml.Node]*yaml.Node) // repeated key -> first definition
+		for _, duplicate := range duplicateMappingKeys(node) {
+			firsts[duplicate.key] = duplicate.first
+		}
+		// report in document order, each duplicate before the keys nested in its value
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i]
+			keyPath := appendPath(path, key.Value)
+			if first, ok := firsts[key]; ok {
+				finding := nodeFinding(MessageTypeError, RuleDuplicateKey, duplicateKeyMessage(key.Value, first.Line), document, key)
+				finding.Pointer = jsonPointer(keyPath)
+				report.add(finding)
+			}
+			walkYAMLDuplicateKeys(node.Content[i+1], keyPath, document, report)
+		}
+	}
+}
+
+// duplicateKey is a mapping key repeating an earlier key of the same mapping.
+type duplicateKey struct {
+	first *yaml.Node // first definition of the key
+	key   *yaml.Node // repeated definition
+}
+
+// duplicateMappingKeys returns the repeated scalar keys of a mapping, in document order.
+// Merge keys (<<) may legitimately appear more than once and are ignored.
+func duplicateMappingKeys(mapping *yaml.Node) []duplicateKey {
+	var duplicates []duplicateKey
+	seen := make(map[string]*yaml.Node)
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		key := mapping.Content[i]
+		if key.Kind != yaml.ScalarNode || key.Value == "<<" {
+			continue
+		}
+		if first, ok := seen[key.Value]; ok {
+			duplicates = append(duplicates, duplicateKey{first: first, key: key})
+		} else {
+			seen[key.Value] = key
+		}
+	}
+	return duplicates
+}
+
+// appendPath returns a copy of path with segment appended, so sibling paths never share storage.
+func appendPath(path []string, segment string) []string {
+	return append(append([]string(nil), path...), segment)
+}
+
+// jsonObjectFrame tracks an open JSON object or array while walking the token stream.
+type jsonObjectFrame struct {
+	object    bool
+	expectKey bool           // an object member name comes next
+	keys      map[string]int // member name -> line of its first definition
+	key       string         // name of the current object member
+	index     int            // index of the current array element
+	path      []string       // path of the object or array itself
+}
+
+// childPath returns the path of the value at the current position of the frame.
+func (f *jsonObjectFrame) childPath() []string {
+	if f.object {
+		return appendPath(f.path, f.key)
+	}
+	return appendPath(f.path, strconv.Itoa(f.index))
+}
+
+// valueDone moves the frame past the value just read.
+func (f *jsonObjectFrame) valueDone() {
+	if f.object {
+		f.expectKey = true
+	} else {
+		f.index++
+	}
+}
+
+// checkJSONDuplicateKeys reports duplicate member names of the JSON objects in dataBytes[from:to], the value of
+// the given document. Unlike decoding into a map, the token stream keeps every member, with its byte offset.
+func checkJSONDuplicateKeys(dataBytes []byte, from, to, document int, report *findingReport) error {
+	decoder := json.NewDecoder(bytes.NewReader(dataBytes[from:to]))
+	var stack []*jsonObjectFrame
+
+	for {
+		start := tokenStart(dataBytes, int64(from)+decoder.InputOffset())
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var top *jsonObjectFrame
+		if len(stack) > 0 {
+			top = stack[len(stack)-1]
+		}
+
+		if top != nil && top.object && top.expectKey {
+			if token == json.Delim('}') {
+				stack = stack[:len(stack)-1]
+				if len(stack) > 0 {
+					stack[len(stack)-1].valueDone()
+				}
+				continue
+			}
+			key, _ := token.(string)
+			line, column := offsetPosition(dataBytes, start)
+			if firstLine, ok := top.keys[key]; ok {
+				endLine, endColumn := offsetPosition(dataBytes, from+int(decoder.InputOffset()))
+				report.add(Finding{
+					Severity:  MessageTypeError,
+					RuleID:    RuleDuplicateKey,
+					Message:   duplicateKeyMessage(key, firstLine),
+					Document:  document,
+					Line:      line,
+					Column:    column,
+					EndLine:   endLine,
+					EndColumn: endColumn,
+					Pointer:   jsonPointer(appendPath(top.path, key)),
+				})
+			} else {
+				top.keys[key] = line
+			}
+			top.key = key
+			top.expectKey = false
+			continue
+		}
+
+		switch token {
+		case json.Delim('{'), json.Delim('['):
+			var path []string
+			if top != nil {
+				path = top.childPath()
+			}
+			frame := &jsonObjectFrame{object: token == json.Delim('{'), path: path}
+			if frame.object {
+				frame.expectKey = true
+				frame.keys = make(map[string]int)
+			}
+			stack = append(stack, frame)
+		case json.Delim(']'):
+			stack = stack[:len(stack)-1]
+			if len(stack) > 0 {
+				stack[len(stack)-1].valueDone()
+			}
+		default:
+			if top != nil {
+				top.valueDone()
+			}
+		}
+	}
+}
+
+// tokenStart skips the whitespace and separators the decoder has not consumed yet,
+// returning the offset at which the next token starts.
+func tokenStart(data []byte, offset int64) int {
+	i := int(offset)
+	for i < len(data) {
+		switch data[i] {
+		case ' ', '\t', '\r', '\n', ',', ':':
+			i++
+		default:
+			return i
+		}
+	}
+	return i
+}
+
+// offsetPosition converts a byte offset into a 1-based line and (rune-based) column.
+func offsetPosition(data []byte, offset int) (int, int) {
+	if offset > len(data) {
+		offset = len(data)
+	}
+	before := data[:offset]
+	lineStart := bytes.LastIndexByte(before, '\n') + 1
+	return bytes.Count(before, []byte{'\n'}) + 1, utf8.RuneCount(before[lineStart:]) + 1
+}
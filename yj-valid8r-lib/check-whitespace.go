@@ -3,35 +3,209 @@ package yjvalid8r_lib
 import (
 	"fmt"
 	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Line endings accepted by FormattingRules.EndOfLine.
+const (
+	EndOfLineLF   = "lf"
+	EndOfLineCRLF = "crlf"
 )
 
+const utf8BOM = "\uFEFF"
+
 // CheckTabsAndWhitespacesFinder checks the input data for unwanted tabs or whitespace characters.
 // It returns a WhitespaceCheckResult with validation status and messages.
+// Use CheckFormatting for the other formatting checks.
 func CheckTabsAndWhitespacesFinder(dataByte []byte) WhitespaceCheckResult {
-	var result WhitespaceCheckResult
-	lines := strings.Split(string(dataByte), "\n")
+	return CheckFormatting(dataByte, FormattingRules{})
+}
+
+// Validate checks the severities and the end of line style of the rules.
+func (r FormattingRules) Validate() error {
+	rules := []struct {
+		name string
+		rule FormattingRule
+	}{
+		{"tabs", r.Tabs},
+		{"trailingWhitespace", r.TrailingWhitespace},
+		{"lineEndings", r.LineEndings},
+		{"bom", r.BOM},
+		{"finalNewline", r.FinalNewline},
+		{"indentation", r.Indentation},
+	}
+	for _, named := range rules {
+		switch named.rule.Severity {
+		case "", MessageTypeError, MessageTypeWarning, MessageTypeInfo:
+		default:
+			return fmt.Errorf("formatting rule %s: unknown severity %q", named.name, named.rule.Severity)
+		}
+	}
+	if r.EndOfLine != "" && r.EndOfLine != EndOfLineLF && r.EndOfLine != EndOfLineCRLF {
+		return fmt.Errorf("formatting: unknown endOfLine %q, expected lf or crlf", r.EndOfLine)
+	}
+	if r.IndentWidth < 0 {
+		return fmt.Errorf("formatting: indentWidth must not be negative")
+	}
+	return nil
+}
+
+// resolve returns whether the rule is enabled and its severity. A rule without Enabled is enabled
+// by default or when it has a severity.
+func (r FormattingRule) resolve(defaultEnabled bool, defaultSeverity ValidationMessageType) (bool, ValidationMessageType) {
+	enabled := defaultEnabled || r.Severity != ""
+	if r.Enabled != nil {
+		enabled = *r.Enabled
+	}
+	severity := r.Severity
+	if severity == "" {
+		severity = defaultSeverity
+	}
+	return enabled, severity
+}
+
+// CheckFormatting checks the formatting of the input data: tabs in the indentation, trailing whitespace,
+// line endings, a UTF-8 byte order mark, a missing final newline and the indentation width of nested
+// YAML block collections. Only tabs and trailing whitespace are checked by default, like
+// CheckTabsAndWhitespacesFinder; the other rules are opt-in. See FormattingRules.
+func CheckFormatting(dataByte []byte, rules FormattingRules) WhitespaceCheckResult {
+	report := newFindingReport()
+	data := string(dataByte)
+
+	tabsEnabled, tabsSeverity := rules.Tabs.resolve(true, MessageTypeError)
+	trailingEnabled, trailingSeverity := rules.TrailingWhitespace.resolve(true, MessageTypeWarning)
+	lineEndingsEnabled, lineEndingsSeverity := rules.LineEndings.resolve(rules.EndOfLine != "", MessageTypeWarning)
+	bomEnabled, bomSeverity := rules.BOM.resolve(false, MessageTypeWarning)
+	finalNewlineEnabled, finalNewlineSeverity := rules.FinalNewline.resolve(false, MessageTypeWarning)
+	indentationEnabled, indentationSeverity := rules.Indentation.resolve(rules.IndentWidth > 0, MessageTypeWarning)
+
+	if strings.HasPrefix(data, utf8BOM) {
+		data = strings.TrimPrefix(data, utf8BOM)
+		if bomEnabled {
+			report.add(lineFinding(bomSeverity, RuleBOM, "UTF-8 byte order mark found.", 1, 1, 2))
+		}
+	}
+
+	wantCRLF := rules.EndOfLine == EndOfLineCRLF
+	var wrongEndings, firstWrongLine, firstWrongColumn int
+
+	lines := strings.Split(data, "\n")
 	for i, line := range lines {
-		for _, ch := range line {
-			if ch == '\t' {
-				result.Errors = append(result.Errors, fmt.Sprintf("Line %d: Tab character found.", i+1))
-				break
-			} else if ch != ' ' {
-				break
+		terminated := i < len(lines)-1
+		body := strings.TrimSuffix(line, "\r")
+		if terminated && (body != line) != wantCRLF {
+			wrongEndings++
+			if firstWrongLine == 0 {
+				firstWrongLine, firstWrongColumn = i+1, utf8.RuneCountInString(body)+1
+			}
+		}
+
+		if tabsEnabled {
+			for col, ch := range body {
+				if ch == '\t' {
+					report.add(lineFinding(tabsSeverity, RuleWhitespaceTab, "Tab character found.", i+1, col+1, col+2))
+					break
+				} else if ch != ' ' {
+					break
+				}
 			}
 		}
-		if len(line) > 0 && (strings.HasSuffix(line, " ") || strings.HasSuffix(line, "\t")) {
-			result.Warnings = append(result.Warnings, fmt.Sprintf("Line %d: Trailing whitespace found.", i+1))
+		if trailingEnabled && len(body) > 0 && (strings.HasSuffix(body, " ") || strings.HasSuffix(body, "\t")) {
+			start := utf8.RuneCountInString(strings.TrimRight(body, " \t")) + 1
+			report.add(lineFinding(trailingSeverity, RuleWhitespaceTrailing, "Trailing whitespace found.", i+1, start, utf8.RuneCountInString(body)+1))
 		}
 	}
 
-	if len(result.Errors) != 0 {
+	if lineEndingsEnabled && wrongEndings > 0 {
+		found, expected := "CRLF", "LF"
+		if wantCRLF {
+			found, expected = expected, found
+		}
+		lineCount := fmt.Sprintf("%d lines", wrongEndings)
+		if wrongEndings == 1 {
+			lineCount = "1 line"
+		}
+		message := fmt.Sprintf("%s line ending found, expected %s (%s).", found, expected, lineCount)
+		report.add(lineFinding(lineEndingsSeverity, RuleLineEnding, message, firstWrongLine, firstWrongColumn, firstWrongColumn+1))
+	}
+
+	if finalNewlineEnabled && data != "" && !strings.HasSuffix(data, "\n") {
+		column := utf8.RuneCountInString(lines[len(lines)-1]) + 1
+		report.add(lineFinding(finalNewlineSeverity, RuleFinalNewline, "No newline at end of file.", len(lines), column, column))
+	}
+
+	// TOML and JSON5 nodes are placed at their keys, not their indentation
+	if dataType := DetectDataType([]byte(data)); indentationEnabled && (dataType == DataTypeJSON || dataType == DataTypeYAML) {
+		if docs, err := DecodeDocumentsAs([]byte(data), dataType); err == nil {
+			checker := indentationChecker{width: rules.IndentWidth, severity: indentationSeverity, report: report}
+			for _, doc := range docs {
+				checker.walk(doc.Node)
+			}
+		}
+	}
+
+	result := WhitespaceCheckResult{
+		Errors:   report.errors,
+		Warnings: report.warnings,
+		Messages: report.messages,
+		Findings: report.sortedFindings(),
+	}
+
+	switch {
+	case report.reported[RuleWhitespaceTab]:
 		result.Messages = append(result.Messages, "Tab issues found in document. Note: Tab characters are not allowed for indentation in either JSON or YAML. They are only valid within string values, not for structuring or formatting the document.")
-	} else if len(result.Warnings) != 0 {
+	case report.reported[RuleWhitespaceTrailing]:
 		result.Messages = append(result.Messages, "Whitespace issues found in document.")
+	case len(report.reported) > 0:
+		result.Messages = append(result.Messages, "Formatting issues found in document.")
 	}
-	// else {
-	// 	result.Messages = append(result.Messages, "No whitespace or tab issues found in data.")
-	// }
 
 	return result
 }
+
+// indentationChecker reports nested YAML block mappings and sequences that are not indented by width
+// spaces relative to their key. Sequences may also start at the column of their key ("indentless").
+// A width of 0 is taken from the first nested collection.
+type indentationChecker struct {
+	width    int
+	severity ValidationMessageType
+	report   *findingReport
+}
+
+func (c *indentationChecker) walk(node *yaml.Node) {
+	switch node.Kind {
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, child := range node.Content {
+			c.walk(child)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			c.check(node.Content[i], node.Content[i+1])
+			c.walk(node.Content[i+1])
+		}
+	}
+}
+
+func (c *indentationChecker) check(key, value *yaml.Node) {
+	if value.Kind != yaml.MappingNode && value.Kind != yaml.SequenceNode {
+		return
+	}
+	if value.Style&yaml.FlowStyle != 0 || value.Line <= key.Line {
+		return
+	}
+
+	indent := value.Column - key.Column
+	if value.Kind == yaml.SequenceNode && indent == 0 {
+		return
+	}
+	if c.width == 0 {
+		c.width = indent
+		return
+	}
+	if indent != c.width {
+		message := fmt.Sprintf("Indentation of %d spaces found, expected %d.", value.Column-1, key.Column-1+c.width)
+		c.report.add(lineFinding(c.severity, RuleIndentation, message, value.Line, 1, value.Column))
+	}
+}
@@ -0,0 +1,227 @@
+package yjvalid8r_lib
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CompiledSchema is a JSON schema that has been fetched and compiled once.
+// It is safe for concurrent use and can be reused across data files and web requests.
+type CompiledSchema struct {
+	location string
+	schema   interface{}
+	draft    SchemaDraft
+	engine   schemaEngine
+}
+
+// CompileSchema fetches the schema at schemaURL (a local path, file:// URL or remote URL) and every
+// schema it references, then compiles it. Relative $refs resolve against the schema's own location,
+// independent of the working directory. The draft is taken from opts.Draft or, by default, from the
+// schema's "$schema"; 2019-09 and 2020-12 schemas are validated by a dedicated engine.
+// A JSON pointer fragment selects a sub-schema, e.g. "openapi.yaml#/components/schemas/Service";
+// OpenAPI 3 documents have their nullable and discriminator keywords translated to JSON Schema.
+func CompileSchema(schemaURL string, opts SchemaOptions) (*CompiledSchema, error) {
+	fetcher := &schemaFetcher{cache: opts.Cache, resolver: opts.Resolver}
+
+	normalizedURL := normalizeSchemaLocation(schemaURL)
+	documentURL, fragment := splitFragment(normalizedURL)
+	schemaBytes, err := fetcher.fetch(documentURL)
+	if err != nil {
+		return nil, fmt.Errorf("schema does not exist or is unreachable: %s: %w", documentURL, err)
+	}
+
+	// Schemas may be written in JSON or YAML
+	document, err := decodeSchema(schemaBytes)
+	if err != nil {
+		return nil, fmt.Errorf("schema load failed: %w", err)
+	}
+	document = translateOpenAPI(document)
+
+	subSchema := document
+	if fragment != "" {
+		var found bool
+		subSchema, found, err = valueAtPointer(document, fragment)
+		if err != nil {
+			return nil, fmt.Errorf("schema load failed: %w", err)
+		}
+		if !found {
+			return nil, fmt.Errorf("schema load failed: %s: no schema at #%s", documentURL, fragment)
+		}
+	}
+
+	// Load every referenced schema up front so failures report the $ref chain.
+	refDocs := make(map[string]interface{})
+	if err := fetcher.collectRefs(documentURL, document, []string{normalizedURL}, refDocs, make(map[string]bool)); err != nil {
+		return nil, fmt.Errorf("schema load failed: %w", err)
+	}
+
+	engine, draft, err := compileSchemaEngine(documentURL, fragment, document, refDocs, fetcher, opts.Draft)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CompiledSchema{
+		location: normalizedURL,
+		schema:   subSchema,
+		draft:    draft,
+		engine:   engine,
+	}, nil
+}
+
+// Location returns the normalized URL the schema was loaded from, including its fragment.
+func (s *CompiledSchema) Location() string {
+	return s.location
+}
+
+// Draft returns the JSON Schema draft the schema was compiled for;
+// SchemaDraftAuto means it declared no known draft and gojsonschema's default was used.
+func (s *CompiledSchema) Draft() SchemaDraft {
+	return s.draft
+}
+
+// Properties returns the top-level "properties" of the schema.
+func (s *CompiledSchema) Properties() map[string]interface{} {
+	return extractTopLevelSchemaProperties(s.schema)
+}
+
+// Validate validates every document of the input data against the schema.
+// Each message carries the index of the document it belongs to.
+func (s *CompiledSchema) Validate(dataBytes []byte) ([]SchemaValidationMessage, error) {
+	// Parse YAML into yaml.Node documents to preserve line info
+	docs, err := DecodeDocuments(dataBytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse yaml/json into node: %w", err)
+	}
+	return s.ValidateDocuments(docs)
+}
+
+// ValidateDocuments validates documents already decoded, e.g. by DecodeDocuments, against the schema.
+func (s *CompiledSchema) ValidateDocuments(docs []Document) ([]SchemaValidationMessage, error) {
+	var messages []SchemaValidationMessage
+	for _, doc := range docs {
+		docMessages, err := s.ValidateDocument(doc)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, docMessages...)
+	}
+
+	return messages, nil
+}
+
+// violationLocation is where a schema error is reported.
+type violationLocation struct {
+	node    *yaml.Node // node the error is reported at, nil if not found
+	alias   *yaml.Node // alias through which the offending value is used, nil if none
+	defined *yaml.Node // node the offending value is defined at, within the anchored value of alias
+	pointer string     // JSON pointer of the offending value
+}
+
+// locateViolation returns the node a schema error is reported at and the JSON pointer of the offending value.
+// Missing properties are reported at the key of the mapping that lacks them (or the document root),
+// properties that are not allowed at their own key; everything else at the value itself.
+// Values shared through an alias or merge key are reported at the alias, together with their definition.
+func locateViolation(doc Document, violation schemaViolation) violationLocation {
+	found := findNodeByPath(doc.Node, violation.Path)
+	location := violationLocation{node: found.value, pointer: jsonPointer(violation.Path)}
+
+	switch {
+	case violation.Missing && found.key != nil:
+		location.node = found.key
+		if found.keyAlias != nil {
+			location.alias, location.defined = found.keyAlias, found.key
+			location.node = found.keyAlias
+		} else if found.alias != nil {
+			location.alias, location.defined = found.alias, found.value
+		}
+		return location
+	case violation.Property != "" && found.value != nil && found.value.Kind == yaml.MappingNode:
+		if propertyKey, _, via := mappingEntry(found.value, violation.Property, make(map[*yaml.Node]bool)); propertyKey != nil {
+			location.node = propertyKey
+			location.pointer = jsonPointer(append(append([]string(nil), violation.Path...), violation.Property))
+			if found.alias != nil {
+				via = found.alias
+			}
+			if via != nil {
+				location.node, location.alias, location.defined = via, via, propertyKey
+			}
+			return location
+		}
+	}
+	if found.alias != nil {
+		location.node, location.alias, location.defined = found.alias, found.alias, found.value
+	}
+	return location
+}
+
+// describe adds where a value used through an alias is defined to a schema error message.
+func (l violationLocation) describe(message string) string {
+	if l.alias == nil || l.defined == nil {
+		return message
+	}
+	return fmt.Sprintf("%s (value from anchor &%s at line %d)", message, l.alias.Value, l.defined.Line)
+}
+
+// ValidateDocument validates a single document of a data stream against the schema.
+// The document root may be of any type: a mapping, a sequence or a scalar.
+func (s *CompiledSchema) ValidateDocument(doc Document) ([]SchemaValidationMessage, error) {
+	// Also decode into plain values for JSON schema validation
+	var data interface{}
+	if err := doc.Node.Decode(&data); err != nil {
+		return nil, fmt.Errorf("parse yaml/json into value: document %d: %w", doc.Index, err)
+	}
+	data = jsonCompatible(data)
+
+	var messages []SchemaValidationMessage
+
+	if topLevelFieldMismatch(s.schema, data) {
+		message := "schema appears irrelevant: no overlapping top-level fields between schema and data."
+		messages = append(messages, SchemaValidationMessage{
+			Type:     MessageTypeWarning,
+			Message:  message,
+			Document: doc.Index,
+			Finding:  nodeFinding(MessageTypeWarning, RuleSchemaIrrelevant, message, doc.Index, doc.Root()),
+		})
+	}
+
+	jsonDataBytes, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("to json: %w", err)
+	}
+
+	violations, err := s.engine.validate(jsonDataBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, violation := range violations {
+		location := locateViolation(doc, violation)
+		node := location.node
+		description := location.describe(violation.Description)
+		finding := nodeFinding(MessageTypeError, ruleSchemaPrefix+violation.Keyword, description, doc.Index, node)
+		finding.Pointer = location.pointer
+		if location.defined != nil {
+			finding.AnchorLine, finding.AnchorColumn = location.defined.Line, location.defined.Column
+		}
+
+		if node != nil {
+			messages = append(messages, SchemaValidationMessage{
+				Type:     MessageTypeError,
+				Message:  fmt.Sprintf("Line %d: %s: %s", node.Line, violation.Field, description),
+				Document: doc.Index,
+				Finding:  finding,
+			})
+		} else {
+			messages = append(messages, SchemaValidationMessage{
+				Type:     MessageTypeError,
+				Message:  fmt.Sprintf("Line unknown: %s: %s", violation.Field, description),
+				Document: doc.Index,
+				Finding:  finding,
+			})
+		}
+	}
+
+	return messages, nil
+}
@@ -3,54 +3,326 @@ package yjvalid8r_lib
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
 
 	"gopkg.in/yaml.v3"
 )
 
-// DetectDataType returns "json", "yaml", or "unknown".
+// dataFormats is the format registry, in detection order. Formats added by RegisterDataFormat
+// are tried after the built-in ones.
+var (
+	dataFormatsMu sync.RWMutex
+	dataFormats   = []DataFormat{
+		{Name: DataTypeJSON, Detect: isJSONData, Decode: decodeYAMLDocuments},
+		{Name: DataTypeYAML, Detect: isYAMLData, Decode: decodeYAMLDocuments, detect: (*formatProbe).isYAML},
+		{Name: DataTypeTOML, Detect: isTOMLData, Decode: decodeTOMLDocuments},
+		{Name: DataTypeJSON5, Detect: isJSON5Data, Decode: decodeJSON5Documents, detect: (*formatProbe).isJSON5},
+		{Name: DataTypeNDJSON, Detect: isJSONLinesData, Decode: decodeJSONLinesDocuments},
+	}
+	builtinDataFormats = len(dataFormats)
+)
+
+// RegisterDataFormat adds a data format, e.g. for HCL configs, to the registry. It is detected after the
+// formats registered before it, and its documents are checked like those of the built-in formats.
+func RegisterDataFormat(format DataFormat) error {
+	if format.Name == "" || format.Name == DataTypeUNKNOWN || format.Detect == nil || format.Decode == nil {
+		return fmt.Errorf("data format needs a name other than %q, a Detect and a Decode function", DataTypeUNKNOWN)
+	}
+
+	dataFormatsMu.Lock()
+	defer dataFormatsMu.Unlock()
+	for _, registered := range dataFormats {
+		if registered.Name == format.Name {
+			return fmt.Errorf("data format %q is already registered", format.Name)
+		}
+	}
+	dataFormats = append(dataFormats, format)
+	return nil
+}
+
+// UnregisterDataFormat removes a data format added by RegisterDataFormat, e.g. at the end of a test.
+// The built-in formats cannot be removed.
+func UnregisterDataFormat(name string) error {
+	dataFormatsMu.Lock()
+	defer dataFormatsMu.Unlock()
+	for i, registered := range dataFormats {
+		if registered.Name != name {
+			continue
+		}
+		if i < builtinDataFormats {
+			return fmt.Errorf("data format %q is built in", name)
+		}
+		dataFormats = append(dataFormats[:i:i], dataFormats[i+1:]...)
+		return nil
+	}
+	return fmt.Errorf("data format %q is not registered", name)
+}
+
+// DataFormats returns the registered data formats in detection order.
+func DataFormats() []DataFormat {
+	dataFormatsMu.RLock()
+	defer dataFormatsMu.RUnlock()
+	return append([]DataFormat(nil), dataFormats...)
+}
+
+// flowYAMLFormat recognizes YAML whose documents are flow-style collections, e.g. "{name: web}".
+// Flow style overlaps with JSON and JSON5, so it is only tried when no registered format matched.
+var flowYAMLFormat = DataFormat{Name: DataTypeYAML, Detect: isFlowYAMLData, Decode: decodeYAMLDocuments, detect: (*formatProbe).isFlowYAML}
+
+// formatProbe is the data formats are detected on. The YAML, JSON5 and flow-style YAML detections all look at
+// its YAML node tree, which is decoded at most once per detection.
+type formatProbe struct {
+	data     []byte
+	decoded  bool
+	yamlDocs []Document
+	yamlErr  error
+}
+
+// yamlDocuments returns the data decoded as a YAML stream.
+func (p *formatProbe) yamlDocuments() ([]Document, error) {
+	if !p.decoded {
+		p.yamlDocs, p.yamlErr = decodeYAMLDocuments(p.data)
+		p.decoded = true
+	}
+	return p.yamlDocs, p.yamlErr
+}
+
+// detect reports whether the data is in the format, sharing the probe with the built-in formats.
+func (p *formatProbe) detect(format DataFormat) bool {
+	if format.detect != nil {
+		return format.detect(p)
+	}
+	return format.Detect(p.data)
+}
+
+// matchDataFormat returns the first registered format the data is written in, falling back to
+// flow-style YAML, and the confidence of the match.
+func matchDataFormat(data []byte) (DataFormat, string, bool) {
+	probe := &formatProbe{data: bytes.TrimSpace(data)}
+	for _, format := range DataFormats() {
+		if probe.detect(format) {
+			return format, ConfidenceHigh, true
+		}
+	}
+	if probe.detect(flowYAMLFormat) {
+		return flowYAMLFormat, ConfidenceMedium, true
+	}
+	return DataFormat{}, ConfidenceNone, false
+}
+
+// DetectDataType returns the name of the registered format the data is written in:
+// "json", "yaml", "toml", "json5", "ndjson" or the name of a registered format; "unknown" otherwise.
 func DetectDataType(data []byte) string {
-	trimmed := bytes.TrimSpace(data)
+	if format, _, ok := matchDataFormat(data); ok {
+		return format.Name
+	}
+	return DataTypeUNKNOWN
+}
 
-	// 1) If it's valid JSON, we're done.
-	if json.Valid(trimmed) {
-		return DataTypeJSON
+// DetectDataFormat detects the format of the data like DetectDataType, with the confidence of the match.
+// Unknown data comes with the error explaining why it was rejected, see explainUnknownData.
+func DetectDataFormat(data []byte) DataFormatDetection {
+	if format, confidence, ok := matchDataFormat(data); ok {
+		return DataFormatDetection{Format: format.Name, Confidence: confidence}
 	}
 
-	// 2) Otherwise, parse it into a YAML node.
-	var doc yaml.Node
-	if err := yaml.Unmarshal(trimmed, &doc); err != nil {
-		return DataTypeUNKNOWN
+	err := explainUnknownData(data)
+	line, column := errorPosition(err)
+	return DataFormatDetection{
+		Format:     DataTypeUNKNOWN,
+		Confidence: ConfidenceNone,
+		Error:      err.Error(),
+		Line:       line,
+		Column:     column,
 	}
+}
 
-	// The actual root node is the first child of the document node.
-	if len(doc.Content) == 0 {
-		return DataTypeUNKNOWN
+// explainUnknownData returns why data in no known format was rejected, with the error of the format the data
+// was most likely written in: TOML if its first line is a TOML table header or key/value pair; YAML if it parses
+// but has a document that is not a collection; JSON if it parses as YAML written like JSON; otherwise the
+// format whose parser got the furthest.
+func explainUnknownData(data []byte) error {
+	if looksLikeTOML(data) {
+		if _, err := decodeTOMLDocuments(data); err != nil {
+			return err
+		}
 	}
-	root := doc.Content[0]
 
-	// 3) Accept only block-style mappings (objects) or sequences (arrays).
-	switch root.Kind {
-	case yaml.MappingNode:
-		// reject if it’s flow-style (i.e. JSON-like `{ ... }`)
-		if root.Style == yaml.FlowStyle {
-			return DataTypeUNKNOWN
+	docs, yamlErr := decodeYAMLDocuments(data)
+	if yamlErr == nil {
+		for _, doc := range docs {
+			root := doc.Root()
+			if root.Kind != yaml.MappingNode && root.Kind != yaml.SequenceNode {
+				return fmt.Errorf("yaml: line %d, column %d: document %d is a scalar, expected a mapping or sequence", root.Line, root.Column, doc.Index)
+			}
+		}
+		if len(docs) == 0 {
+			return fmt.Errorf("no documents in data")
+		}
+		return jsonSyntaxError(data)
+	}
+
+	candidates := []error{jsonSyntaxError(data), yamlErr}
+	if _, err := parseJSON5(data); err != nil {
+		candidates = append(candidates, err)
+	}
+	furthest := candidates[0]
+	for _, candidate := range candidates[1:] {
+		if errorPositionAfter(candidate, furthest) {
+			furthest = candidate
 		}
-		return DataTypeYAML
+	}
+	return furthest
+}
+
+// tomlFirstLinePattern matches a TOML table header, e.g. "[server]" or "[[servers]]", or a key/value pair.
+var tomlFirstLinePattern = regexp.MustCompile(`^(\[\[?\s*[\w."' -]+\s*\]\]?\s*(#.*)?|[\w."' -]+=.*)$`)
+
+// looksLikeTOML reports whether the first line of the data that is not blank or a comment looks like TOML.
+func looksLikeTOML(data []byte) bool {
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		return tomlFirstLinePattern.MatchString(line)
+	}
+	return false
+}
+
+// jsonSyntaxError returns the syntax error of JSON data, with its line and column.
+func jsonSyntaxError(data []byte) error {
+	var value interface{}
+	err := json.Unmarshal(data, &value)
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		// Offset counts the bytes read, including the offending one
+		line, column := offsetPosition(data, max(int(syntaxErr.Offset)-1, 0))
+		return fmt.Errorf("json: line %d, column %d: %v", line, column, syntaxErr)
+	}
+	if err != nil {
+		return fmt.Errorf("json: %w", err)
+	}
+	return nil
+}
+
+// errorLinePattern finds the position in the parse errors of the built-in formats, e.g. "line 3, column 5"
+// or "yaml: line 3:".
+var errorLinePattern = regexp.MustCompile(`line (\d+)(?:, column (\d+))?`)
+
+// errorPositionAfter reports whether a parse error points further into the data than another. An error
+// without a column (YAML) counts as being at the end of its line.
+func errorPositionAfter(err, other error) bool {
+	line, column := errorPosition(err)
+	otherLine, otherColumn := errorPosition(other)
+	if column == 0 {
+		column = math.MaxInt
+	}
+	if otherColumn == 0 {
+		otherColumn = math.MaxInt
+	}
+	return line > otherLine || (line == otherLine && column > otherColumn)
+}
+
+// errorPosition returns the line and column a parse error points at; 0 when unknown.
+func errorPosition(err error) (int, int) {
+	match := errorLinePattern.FindStringSubmatch(err.Error())
+	if match == nil {
+		return 0, 0
+	}
+	line, _ := strconv.Atoi(match[1])
+	column, _ := strconv.Atoi(match[2])
+	return line, column
+}
+
+// isJSONData reports whether the data is a single JSON value.
+func isJSONData(data []byte) bool {
+	return json.Valid(data)
+}
 
+// isYAMLData reports whether every document of a YAML stream is a block-style mapping or sequence.
+func isYAMLData(data []byte) bool {
+	return (&formatProbe{data: data}).isYAML()
+}
+
+func (p *formatProbe) isYAML() bool {
+	docs, err := p.yamlDocuments()
+	if err != nil || len(docs) == 0 {
+		return false
+	}
+	for _, doc := range docs {
+		if !isBlockCollection(doc.Root()) {
+			return false
+		}
+	}
+	return true
+}
+
+// isFlowYAMLData reports whether every document of a YAML stream is a mapping or sequence, at least one of
+// them in flow style. A single document written like JSON is rejected: it is malformed JSON, not YAML.
+func isFlowYAMLData(data []byte) bool {
+	return (&formatProbe{data: data}).isFlowYAML()
+}
+
+func (p *formatProbe) isFlowYAML() bool {
+	docs, err := p.yamlDocuments()
+	if err != nil || len(docs) == 0 {
+		return false
+	}
+	for _, doc := range docs {
+		if root := doc.Root(); root.Kind != yaml.MappingNode && root.Kind != yaml.SequenceNode {
+			return false
+		}
+	}
+	return len(docs) > 1 || !isJSONStyle(docs[0].Root())
+}
+
+// isJSONStyle reports whether a node is written like JSON: keys and strings in double quotes, other
+// scalars plain numbers, booleans or nulls (including missing values, as in {"a":}).
+func isJSONStyle(node *yaml.Node) bool {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Style != yaml.DoubleQuotedStyle || !isJSONStyle(node.Content[i+1]) {
+				return false
+			}
+		}
+		return true
 	case yaml.SequenceNode:
-		// reject if it’s flow-style (i.e. JSON-like `[ ... ]`)
-		if root.Style == yaml.FlowStyle {
-			return DataTypeUNKNOWN
+		for _, child := range node.Content {
+			if !isJSONStyle(child) {
+				return false
+			}
+		}
+		return true
+	case yaml.ScalarNode:
+		if node.Style == yaml.DoubleQuotedStyle {
+			return true
 		}
-		return DataTypeYAML
+		return node.Style == 0 && (node.Value == "" || node.Tag == "!!int" || node.Tag == "!!float" || node.Tag == "!!bool" || node.Tag == "!!null")
+	}
+	return false
+}
 
+// isBlockCollection reports whether a document root is a block-style mapping or sequence.
+func isBlockCollection(root *yaml.Node) bool {
+	switch root.Kind {
+	case yaml.MappingNode, yaml.SequenceNode:
+		// reject if it’s flow-style (i.e. JSON-like `{ ... }` or `[ ... ]`)
+		return root.Style != yaml.FlowStyle
 	default:
 		// scalars, etc. → unknown
-		return DataTypeUNKNOWN
+		return false
 	}
 }
 
-// IsUnknownDataType checks if the given data has an unrecognized format (neither JSON nor YAML).
+// IsUnknownDataType checks if the given data has an unrecognized format (none of the registered formats).
 func IsUnknownDataType(data []byte) bool {
 	return DetectDataType(data) == DataTypeUNKNOWN
 }
@@ -0,0 +1,87 @@
+package yjvalid8r_lib
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Document is a single YAML document (or the single JSON value) taken from a data stream.
+type Document struct {
+	Index int        // Zero-based position of the document in the stream, counting empty documents.
+	Node  *yaml.Node // Document node; line numbers are absolute within the whole stream.
+}
+
+// Root returns the top-level content node of the document.
+func (d Document) Root() *yaml.Node {
+	if d.Node == nil || len(d.Node.Content) == 0 {
+		return nil
+	}
+	return d.Node.Content[0]
+}
+
+// StartLine returns the line on which the document content starts.
+func (d Document) StartLine() int {
+	if root := d.Root(); root != nil {
+		return root.Line
+	}
+	return 0
+}
+
+// DecodeDocuments parses every document of a YAML stream separated by `---`.
+// JSON input yields a single document. Empty documents (e.g. a trailing `---`) are skipped,
+// but still counted in the Index of the documents that follow.
+// Data in another registered format (TOML, JSON5, ...) is decoded by that format into the same node tree;
+// data in no known format is parsed as YAML, to report its syntax error.
+func DecodeDocuments(data []byte) ([]Document, error) {
+	if format, _, ok := matchDataFormat(data); ok {
+		return format.Decode(data)
+	}
+	return decodeYAMLDocuments(data)
+}
+
+// DecodeDocumentsAs decodes data whose format was already detected, e.g. by DetectDataFormat, without
+// detecting it again. dataType is the name of a registered format; data of another type is parsed as YAML.
+func DecodeDocumentsAs(data []byte, dataType string) ([]Document, error) {
+	for _, format := range DataFormats() {
+		if format.Name == dataType {
+			return format.Decode(data)
+		}
+	}
+	return decodeYAMLDocuments(data)
+}
+
+// decodeYAMLDocuments parses every document of a YAML (or JSON) stream.
+func decodeYAMLDocuments(data []byte) ([]Document, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+
+	var docs []Document
+	for index := 0; ; index++ {
+		var node yaml.Node
+		err := decoder.Decode(&node)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("document %d: %w", index, err)
+		}
+		if isEmptyDocument(&node) {
+			continue
+		}
+		docs = append(docs, Document{Index: index, Node: &node})
+	}
+
+	return docs, nil
+}
+
+// isEmptyDocument reports whether a document node holds nothing but an implicit null.
+func isEmptyDocument(node *yaml.Node) bool {
+	if len(node.Content) == 0 {
+		return true
+	}
+	root := node.Content[0]
+	return root.Kind == yaml.ScalarNode && root.Tag == "!!null" && root.Value == ""
+}
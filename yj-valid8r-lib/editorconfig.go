@@ -0,0 +1,327 @@
+package yjvalid8r_lib
+
+import (
+	"bufio"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// editorConfigFile is a parsed .editorconfig file.
+type editorConfigFile struct {
+	dir      string
+	root     bool
+	sections []editorConfigSection
+}
+
+// editorConfigSection holds the properties of one [glob] section.
+type editorConfigSection struct {
+	pattern    *regexp.Regexp
+	properties map[string]string
+}
+
+var editorConfigRange = regexp.MustCompile(`^(-?\d+)\.\.(-?\d+)$`)
+
+// ApplyEditorConfig fills the settings left unset in rules from the .editorconfig files that apply to
+// dataPath: the nearest file wins, up to the first one declaring "root = true". It honors indent_style
+// (tab allows tabs in the indentation, except in YAML files), indent_size, end_of_line (lf or crlf), insert_final_newline and
+// trim_trailing_whitespace. Explicitly set rules are kept. Without a .editorconfig the rules are returned unchanged.
+func ApplyEditorConfig(dataPath string, rules FormattingRules) (FormattingRules, error) {
+	properties, err := editorConfigProperties(dataPath)
+	if err != nil {
+		return rules, err
+	}
+
+	// YAML forbids tabs in the indentation, so indent_style = tab, e.g. from a section shared with Go files
+	// or Makefiles, does not apply to .yaml and .yml files
+	disabled, enabled := false, true
+	if properties["indent_style"] == "tab" && !isYAMLPath(dataPath) {
+		if rules.Tabs.Enabled == nil {
+			rules.Tabs.Enabled = &disabled
+		}
+		if rules.Indentation.Enabled == nil {
+			rules.Indentation.Enabled = &disabled
+		}
+	}
+
+	indentSize := properties["indent_size"]
+	if indentSize == "tab" {
+		indentSize = properties["tab_width"]
+	}
+	if size, err := strconv.Atoi(indentSize); err == nil && size > 0 && rules.IndentWidth == 0 {
+		rules.IndentWidth = size
+	}
+
+	if endOfLine := properties["end_of_line"]; (endOfLine == EndOfLineLF || endOfLine == EndOfLineCRLF) && rules.EndOfLine == "" {
+		rules.EndOfLine = endOfLine
+	}
+
+	flags := []struct {
+		property string
+		rule     *FormattingRule
+	}{
+		{"insert_final_newline", &rules.FinalNewline},
+		{"trim_trailing_whitespace", &rules.TrailingWhitespace},
+	}
+	for _, flag := range flags {
+		if flag.rule.Enabled != nil {
+			continue
+		}
+		switch properties[flag.property] {
+		case "true":
+			flag.rule.Enabled = &enabled
+		case "false":
+			flag.rule.Enabled = &disabled
+		}
+	}
+
+	return rules, nil
+}
+
+// isYAMLPath reports whether a file name has a YAML extension.
+func isYAMLPath(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// editorConfigProperties returns the properties of every .editorconfig section matching path,
+// later sections and nearer files overriding earlier ones; "unset" removes a property.
+func editorConfigProperties(path string) (map[string]string, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Collect files from the data file's directory up to the root
+	var files []*editorConfigFile
+	for dir := filepath.Dir(absPath); ; dir = filepath.Dir(dir) {
+		file, err := readEditorConfig(dir)
+		if err != nil {
+			return nil, err
+		}
+		if file != nil {
+			files = append(files, file)
+			if file.root {
+				break
+			}
+		}
+		if filepath.Dir(dir) == dir {
+			break
+		}
+	}
+
+	properties := make(map[string]string)
+	for i := len(files) - 1; i >= 0; i-- {
+		relPath, err := filepath.Rel(files[i].dir, absPath)
+		if err != nil {
+			continue
+		}
+		relPath = filepath.ToSlash(relPath)
+		for _, section := range files[i].sections {
+			if section.pattern == nil || !section.pattern.MatchString(relPath) {
+				continue
+			}
+			for key, value := range section.properties {
+				if value == "unset" {
+					delete(properties, key)
+				} else {
+					properties[key] = value
+				}
+			}
+		}
+	}
+	return properties, nil
+}
+
+// readEditorConfig parses dir/.editorconfig, or returns nil if there is none. Unparseable lines are skipped.
+func readEditorConfig(dir string) (*editorConfigFile, error) {
+	path := filepath.Join(dir, ".editorconfig")
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	file := &editorConfigFile{dir: dir}
+	var section *editorConfigSection
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			// a glob that does not compile gives a section matching no file
+			pattern, _ := editorConfigPattern(line[1 : len(line)-1])
+			file.sections = append(file.sections, editorConfigSection{pattern: pattern, properties: make(map[string]string)})
+			section = &file.sections[len(file.sections)-1]
+			continue
+		}
+
+		// lines that are neither a section nor a key = value pair are ignored, as the EditorConfig spec asks
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.ToLower(strings.TrimSpace(value))
+		if section == nil {
+			// preamble: only "root" is meaningful before the first section
+			if key == "root" {
+				file.root = value == "true"
+			}
+			continue
+		}
+		section.properties[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+// editorConfigPattern compiles a section glob into a regexp matching slash-separated paths relative to the
+// .editorconfig directory. Globs without a slash match file names in any subdirectory.
+func editorConfigPattern(glob string) (*regexp.Regexp, error) {
+	if strings.Contains(glob, "/") {
+		glob = strings.TrimPrefix(glob, "/")
+	} else {
+		glob = "**/" + glob
+	}
+	return regexp.Compile("^" + editorConfigGlobToRegexp(glob) + "$")
+}
+
+// editorConfigGlobToRegexp translates *, **, ?, [...], [!...], {a,b} (which may nest) and {1..3} to regexp syntax.
+func editorConfigGlobToRegexp(glob string) string {
+	var sb strings.Builder
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch c {
+		case '*':
+			switch {
+			case strings.HasPrefix(glob[i:], "**/"):
+				sb.WriteString("(?:.*/)?") // zero or more directories
+				i += 2
+			case strings.HasPrefix(glob[i:], "**"):
+				sb.WriteString(".*")
+				i++
+			default:
+				sb.WriteString("[^/]*")
+			}
+		case '?':
+			sb.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				sb.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			negate := strings.HasPrefix(class, "!")
+			class = strings.TrimPrefix(class, "!")
+			sb.WriteByte('[')
+			if negate {
+				sb.WriteByte('^')
+			}
+			sb.WriteString(strings.NewReplacer(`\`, `\\`, `[`, `\[`).Replace(class))
+			sb.WriteByte(']')
+			i += end + 1
+		case '{':
+			end := matchingBrace(glob[i:])
+			if end < 0 {
+				sb.WriteString(`\{`)
+				continue
+			}
+			inner := glob[i+1 : i+end]
+			i += end
+			if m := editorConfigRange.FindStringSubmatch(inner); m != nil {
+				sb.WriteString(numericRangeRegexp(m[1], m[2]))
+				continue
+			}
+			alternatives := splitBraceAlternatives(inner)
+			if len(alternatives) == 1 {
+				sb.WriteString(regexp.QuoteMeta("{" + inner + "}"))
+				continue
+			}
+			for j, alternative := range alternatives {
+				alternatives[j] = editorConfigGlobToRegexp(alternative)
+			}
+			sb.WriteString("(?:" + strings.Join(alternatives, "|") + ")")
+		case '\\':
+			if i+1 < len(glob) {
+				i++
+				sb.WriteString(regexp.QuoteMeta(string(glob[i])))
+			}
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return sb.String()
+}
+
+// matchingBrace returns the index of the '}' closing the '{' glob starts with, counting nested braces
+// and skipping escaped characters, or -1 if it is not closed.
+func matchingBrace(glob string) int {
+	depth := 0
+	for i := 0; i < len(glob); i++ {
+		switch glob[i] {
+		case '\\':
+			i++
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// splitBraceAlternatives splits the inside of a {...} group at its commas, but not at those of nested groups.
+func splitBraceAlternatives(inner string) []string {
+	var alternatives []string
+	depth, start := 0, 0
+	for i := 0; i < len(inner); i++ {
+		switch inner[i] {
+		case '\\':
+			i++
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				alternatives = append(alternatives, inner[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(alternatives, inner[start:])
+}
+
+// numericRangeRegexp matches the integers from..to; very large ranges match any integer.
+func numericRangeRegexp(from, to string) string {
+	low, _ := strconv.Atoi(from)
+	high, _ := strconv.Atoi(to)
+	if low > high {
+		low, high = high, low
+	}
+	if high-low > 1000 {
+		return `-?\d+`
+	}
+	numbers := make([]string, 0, high-low+1)
+	for n := low; n <= high; n++ {
+		numbers = append(numbers, strconv.Itoa(n))
+	}
+	return "(?:" + strings.Join(numbers, "|") + ")"
+}
@@ -0,0 +1,146 @@
+package yjvalid8r_lib
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Rule IDs of the findings produced by the checks in this package.
+// Schema findings use "schema/" followed by the failed keyword or error type, e.g. "schema/required".
+const (
+	RuleWhitespaceTab       = "whitespace/tab"
+	RuleWhitespaceTrailing  = "whitespace/trailing"
+	RuleLineEnding          = "format/line-ending"
+	RuleBOM                 = "format/bom"
+	RuleFinalNewline        = "format/final-newline"
+	RuleIndentation         = "format/indentation"
+	RuleDuplicateKey        = "data/duplicate-key"
+	RuleJSONLineSyntax      = "data/json-line-syntax"
+	RuleAliasExpansion      = "data/alias-expansion"
+	RuleYAMLKeyDuplicates   = "yaml/key-duplicates"
+	RuleYAMLKeyOrdering     = "yaml/key-ordering"
+	RuleYAMLTruthy          = "yaml/truthy"
+	RuleYAMLDocumentStart   = "yaml/document-start"
+	RuleYAMLLineLength      = "yaml/line-length"
+	RuleYAMLComments        = "yaml/comments"
+	RuleYAMLQuotedStrings   = "yaml/quoted-strings"
+	RuleYAMLBraces          = "yaml/braces"
+	RuleRegexMatch          = "regex/match"
+	RuleRegexEnvMissing     = "regex/env-missing"
+	RuleRegexInvalid        = "regex/invalid"
+	RuleSearchPathInvalid   = "search-path/invalid"
+	RuleSearchPathRequired  = "search-path/required"
+	RuleSearchPathForbidden = "search-path/forbidden"
+	RuleSearchPathCount     = "search-path/count"
+	RuleSearchPathValue     = "search-path/value"
+	RuleSearchPathPattern   = "search-path/pattern"
+	RuleSearchPathRange     = "search-path/range"
+	RuleSearchPathType      = "search-path/type"
+	RuleSchemaIrrelevant    = "schema/irrelevant"
+	RuleSchemaLoad          = "schema/load"
+	RuleSchemaDecode        = "schema/decode"
+	ruleSchemaPrefix        = "schema/"
+)
+
+// jsonPointer builds an RFC 6901 JSON pointer from path segments, e.g. ["a", "0"] -> "/a/0".
+func jsonPointer(path []string) string {
+	var sb strings.Builder
+	for _, segment := range path {
+		sb.WriteByte('/')
+		segment = strings.ReplaceAll(segment, "~", "~0")
+		sb.WriteString(strings.ReplaceAll(segment, "/", "~1"))
+	}
+	return sb.String()
+}
+
+// nodeFinding returns a finding spanning a YAML node.
+func nodeFinding(severity ValidationMessageType, ruleID, message string, document int, node *yaml.Node) Finding {
+	finding := Finding{Severity: severity, RuleID: ruleID, Message: message, Document: document}
+	if node != nil {
+		finding.Line, finding.Column = node.Line, node.Column
+		finding.EndLine, finding.EndColumn = nodeEnd(node)
+	}
+	return finding
+}
+
+// nodeEnd returns the position just past a node: the end of a single-line scalar, or the end of
+// the last scalar of a collection. The end column of block scalars (| and >) is unknown.
+func nodeEnd(node *yaml.Node) (int, int) {
+	for (node.Kind == yaml.MappingNode || node.Kind == yaml.SequenceNode || node.Kind == yaml.DocumentNode) && len(node.Content) > 0 {
+		node = node.Content[len(node.Content)-1]
+	}
+	if node.Kind == yaml.AliasNode {
+		return node.Line, node.Column + 1 + utf8.RuneCountInString(node.Value)
+	}
+
+	if node.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 {
+		// content starts on the line after the indicator
+		return node.Line + strings.Count(strings.TrimRight(node.Value, "\n"), "\n") + 1, 0
+	}
+	if lines := strings.Split(node.Value, "\n"); len(lines) > 1 {
+		return node.Line + len(lines) - 1, utf8.RuneCountInString(lines[len(lines)-1]) + 1
+	}
+
+	width := utf8.RuneCountInString(node.Value)
+	if node.Style&(yaml.DoubleQuotedStyle|yaml.SingleQuotedStyle) != 0 {
+		width += 2
+	}
+	if node.Kind == yaml.MappingNode || node.Kind == yaml.SequenceNode {
+		width = 2 // empty flow collection: {} or []
+	}
+	return node.Line, node.Column + width
+}
+
+// lineFinding returns a finding spanning columns [column, endColumn) of a single line.
+func lineFinding(severity ValidationMessageType, ruleID, message string, line, column, endColumn int) Finding {
+	return Finding{
+		Severity:  severity,
+		RuleID:    ruleID,
+		Message:   message,
+		Line:      line,
+		Column:    column,
+		EndLine:   line,
+		EndColumn: endColumn,
+	}
+}
+
+// findingReport collects findings of line-based checks together with their "Line N: message" texts,
+// sorted into errors, warnings and (info) messages by severity.
+type findingReport struct {
+	errors   []string
+	warnings []string
+	messages []string
+	findings []Finding
+	reported map[string]bool // rule IDs with findings
+}
+
+func newFindingReport() *findingReport {
+	return &findingReport{reported: make(map[string]bool)}
+}
+
+func (r *findingReport) add(finding Finding) {
+	text := fmt.Sprintf("Line %d: %s", finding.Line, finding.Message)
+	switch finding.Severity {
+	case MessageTypeError:
+		r.errors = append(r.errors, text)
+	case MessageTypeWarning:
+		r.warnings = append(r.warnings, text)
+	default:
+		r.messages = append(r.messages, text)
+	}
+	r.findings = append(r.findings, finding)
+	r.reported[finding.RuleID] = true
+}
+
+// sortedFindings returns the findings ordered by position.
+func (r *findingReport) sortedFindings() []Finding {
+	sort.SliceStable(r.findings, func(i, j int) bool {
+		a, b := r.findings[i], r.findings[j]
+		return a.Line < b.Line || (a.Line == b.Line && a.Column < b.Column)
+	})
+	return r.findings
+}
@@ -0,0 +1,114 @@
+package yjvalid8r_lib
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultIndentWidth is the number of spaces a tab in the indentation is replaced by.
+const DefaultIndentWidth = 2
+
+// FixWhitespace fixes the issues reported by CheckTabsAndWhitespacesFinder: it strips trailing whitespace,
+// replaces every tab in the indentation of a line by opts.IndentWidth spaces and makes sure non-empty data
+// ends with a newline. Line endings (LF or CRLF) are kept; tabs after the indentation are left alone.
+// Rules disabled in opts.Rules are not fixed, so the fixes agree with CheckFormatting and ApplyEditorConfig;
+// the final newline is only left out when the FinalNewline rule is explicitly disabled.
+// The content of literal (|) and folded (>) block scalars is data and is left untouched, as long as the
+// data parses as YAML to find them.
+func FixWhitespace(dataBytes []byte, opts WhitespaceFixOptions) WhitespaceFixResult {
+	fixTabs, _ := opts.Rules.Tabs.resolve(true, MessageTypeError)
+	fixTrailing, _ := opts.Rules.TrailingWhitespace.resolve(true, MessageTypeWarning)
+	fixFinalNewline := opts.Rules.FinalNewline.Enabled == nil || *opts.Rules.FinalNewline.Enabled
+
+	indentWidth := opts.IndentWidth
+	if indentWidth <= 0 {
+		indentWidth = DefaultIndentWidth
+	}
+	indent := strings.Repeat(" ", indentWidth)
+
+	var result WhitespaceFixResult
+	data := string(dataBytes)
+	lines := strings.Split(data, "\n")
+	blockLines := blockScalarLines(dataBytes, lines)
+	for i, line := range lines {
+		if blockLines[i] {
+			continue
+		}
+		body := strings.TrimSuffix(line, "\r")
+		ending := line[len(body):]
+
+		fixed := body
+		if fixTrailing {
+			fixed = strings.TrimRight(body, " \t")
+			if len(fixed) < len(body) {
+				result.Messages = append(result.Messages, fmt.Sprintf("Line %d: Removed trailing whitespace.", i+1))
+			}
+		}
+
+		indentEnd := len(fixed) - len(strings.TrimLeft(fixed, " \t"))
+		if fixTabs && strings.Contains(fixed[:indentEnd], "\t") {
+			fixed = strings.ReplaceAll(fixed[:indentEnd], "\t", indent) + fixed[indentEnd:]
+			result.Messages = append(result.Messages, fmt.Sprintf("Line %d: Replaced tab indentation with spaces.", i+1))
+		}
+
+		lines[i] = fixed + ending
+	}
+
+	fixed := strings.Join(lines, "\n")
+	if fixFinalNewline && fixed != "" && !strings.HasSuffix(fixed, "\n") {
+		newline := "\n"
+		if strings.Contains(fixed, "\r\n") {
+			newline = "\r\n"
+		}
+		fixed += newline
+		result.Messages = append(result.Messages, fmt.Sprintf("Line %d: Added final newline.", len(lines)))
+	}
+
+	result.Data = []byte(fixed)
+	result.Changed = fixed != data
+	return result
+}
+
+// blockScalarLines returns the (zero-based) indexes of the lines holding the content of a literal or folded
+// block scalar, whose tabs and trailing whitespace are part of the value. Data that is not valid YAML has none.
+func blockScalarLines(data []byte, lines []string) map[int]bool {
+	docs, err := decodeYAMLDocuments(data)
+	if err != nil {
+		return nil
+	}
+	blockLines := make(map[int]bool)
+	for _, doc := range docs {
+		walkNodes(doc.Node, func(node *yaml.Node) {
+			if node.Kind == yaml.ScalarNode && (node.Style == yaml.LiteralStyle || node.Style == yaml.FoldedStyle) {
+				markBlockScalarContent(lines, node.Line, blockLines)
+			}
+		})
+	}
+	return blockLines
+}
+
+// markBlockScalarContent marks the content lines of the block scalar whose indicator is on the given (1-based)
+// line. The content is indented by at least the indentation of its first non-blank line; it ends before the
+// first non-blank line indented less. Blank lines with no more than that indentation carry no data.
+func markBlockScalarContent(lines []string, indicatorLine int, blockLines map[int]bool) {
+	indent := -1
+	for i := indicatorLine; i < len(lines); i++ {
+		line := strings.TrimSuffix(lines[i], "\r")
+		spaces := len(line) - len(strings.TrimLeft(line, " "))
+		blank := strings.TrimLeft(line, " \t") == ""
+		if indent < 0 {
+			if blank {
+				continue
+			}
+			indent = spaces
+		}
+		if !blank && spaces < indent {
+			return
+		}
+		if len(line) > indent {
+			blockLines[i] = true
+		}
+	}
+}
@@ -0,0 +1,287 @@
+package yjvalid8r_lib
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Quote styles accepted by FormatOptions.QuoteStyle.
+const (
+	QuoteStyleDouble = "double"
+	QuoteStyleSingle = "single"
+)
+
+// Validate checks the indentation width and the quote style of the options.
+func (o FormatOptions) Validate() error {
+	if o.IndentWidth < 0 {
+		return fmt.Errorf("format: indentWidth must not be negative")
+	}
+	if o.QuoteStyle != "" && o.QuoteStyle != QuoteStyleDouble && o.QuoteStyle != QuoteStyleSingle {
+		return fmt.Errorf("format: unknown quoteStyle %q, expected double or single", o.QuoteStyle)
+	}
+	return nil
+}
+
+// FormatData re-emits JSON or YAML data in a canonical style, keeping its format.
+// JSON is indented by opts.IndentWidth spaces (DefaultIndentWidth when 0), keeping the key order unless
+// opts.SortKeys is set. YAML is re-encoded from its node tree, so comments, anchors and the key order are kept:
+// collections are written in block style, strings are quoted only when a plain scalar would be read differently
+// (with opts.QuoteStyle quotes, double by default), literal and folded blocks stay as they are, and every
+// non-empty document of a stream is kept. With opts.SortKeys, a mapping whose keys cannot be sorted without moving
+// an alias in front of its anchor keeps its order. The output always ends with a single newline, and is parsed
+// again before it is returned.
+func FormatData(dataBytes []byte, opts FormatOptions) (FormatResult, error) {
+	if err := opts.Validate(); err != nil {
+		return FormatResult{}, err
+	}
+	if opts.IndentWidth == 0 {
+		opts.IndentWidth = DefaultIndentWidth
+	}
+
+	data := bytes.TrimPrefix(dataBytes, []byte(utf8BOM))
+	dataType := DetectDataType(data)
+
+	var formatted []byte
+	var err error
+	switch dataType {
+	case DataTypeJSON:
+		formatted, err = formatJSON(data, opts)
+	case DataTypeYAML:
+		formatted, err = formatYAML(data, opts)
+	case DataTypeUNKNOWN:
+		err = fmt.Errorf("data is neither valid JSON nor YAML")
+	default:
+		err = fmt.Errorf("formatting %s data is not supported", dataType)
+	}
+	if err != nil {
+		return FormatResult{}, err
+	}
+	// The data is rewritten in place, so never hand back something that no longer parses
+	if err := checkFormatted(formatted, dataType); err != nil {
+		return FormatResult{}, fmt.Errorf("formatted data is invalid, data left unchanged: %w", err)
+	}
+
+	return FormatResult{
+		Data:     formatted,
+		Changed:  !bytes.Equal(formatted, dataBytes),
+		DataType: dataType,
+	}, nil
+}
+
+// formatJSON indents JSON data. Sorting keys goes through a decoded value, which would silently drop
+// all but one value of a duplicate key, so data with duplicate keys is refused.
+func formatJSON(data []byte, opts FormatOptions) ([]byte, error) {
+	indent := strings.Repeat(" ", opts.IndentWidth)
+
+	if !opts.SortKeys {
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, bytes.TrimSpace(data), "", indent); err != nil {
+			return nil, err
+		}
+		buf.WriteByte('\n')
+		return buf.Bytes(), nil
+	}
+
+	if duplicates := CheckDuplicateKeysFinder(data); !duplicates.Valid {
+		return nil, fmt.Errorf("cannot sort keys: %s", duplicates.Errors[0])
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", indent)
+	if err := encoder.Encode(value); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// formatYAML re-encodes every document of a YAML stream. Empty documents without comments are dropped.
+// The encoder separates documents by "---"; a marker before the first document is kept if the data has one.
+func formatYAML(data []byte, opts FormatOptions) ([]byte, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	var buf bytes.Buffer
+	if startsWithDocumentMarker(data) {
+		buf.WriteString("---\n")
+	}
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(opts.IndentWidth)
+
+	for index := 0; ; index++ {
+		var node yaml.Node
+		err := decoder.Decode(&node)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("document %d: %w", index, err)
+		}
+		if isEmptyDocument(&node) && node.HeadComment == "" && node.FootComment == "" {
+			continue
+		}
+		canonicalizeNode(&node, opts)
+		if err := encoder.Encode(&node); err != nil {
+			return nil, fmt.Errorf("document %d: %w", index, err)
+		}
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// checkFormatted parses formatted data again, returning the first error.
+func checkFormatted(formatted []byte, dataType string) error {
+	if dataType == DataTypeJSON {
+		var value interface{}
+		return json.Unmarshal(formatted, &value)
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(formatted))
+	for index := 0; ; index++ {
+		var node yaml.Node
+		err := decoder.Decode(&node)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("document %d: %w", index, err)
+		}
+	}
+}
+
+// startsWithDocumentMarker reports whether the first line of YAML data that is not blank, a comment or
+// a directive is a "---" document start marker.
+func startsWithDocumentMarker(data []byte) bool {
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(line, "%") {
+			continue
+		}
+		return line == "---" || strings.HasPrefix(line, "--- ") || strings.HasPrefix(line, "---\t")
+	}
+	return false
+}
+
+// canonicalizeNode rewrites the style of a node and its descendants, sorting mapping keys if requested.
+func canonicalizeNode(node *yaml.Node, opts FormatOptions) {
+	switch node.Kind {
+	case yaml.MappingNode, yaml.SequenceNode:
+		if len(node.Content) > 0 {
+			node.Style &^= yaml.FlowStyle
+		}
+		if node.Kind == yaml.MappingNode && opts.SortKeys {
+			sortMappingKeys(node)
+		}
+	case yaml.ScalarNode:
+		if node.Tag == "!!merge" {
+			// left implicit, the encoder would write "!!merge <<"
+			node.Tag = ""
+		}
+		node.Style = canonicalScalarStyle(node, opts.QuoteStyle)
+	}
+	for _, child := range node.Content {
+		canonicalizeNode(child, opts)
+	}
+}
+
+// canonicalScalarStyle returns the style a scalar is written in: strings are plain unless a plain scalar would
+// be read differently, in which case they are quoted with quoteStyle. Block scalars, multi-line strings,
+// explicitly tagged and non-string scalars keep their style.
+func canonicalScalarStyle(node *yaml.Node, quoteStyle string) yaml.Style {
+	if node.Tag != "!!str" || node.Style&(yaml.LiteralStyle|yaml.FoldedStyle|yaml.TaggedStyle) != 0 || strings.Contains(node.Value, "\n") {
+		return node.Style
+	}
+	if !yamlNeedsQuotes(node.Value) {
+		return 0
+	}
+	// single quotes cannot escape control characters
+	if quoteStyle == QuoteStyleSingle && strings.IndexFunc(node.Value, func(r rune) bool { return !unicode.IsPrint(r) }) < 0 {
+		return yaml.SingleQuotedStyle
+	}
+	return yaml.DoubleQuotedStyle
+}
+
+// sortMappingKeys orders the key/value pairs of a mapping by key. Merge keys (<<) stay in front, so the keys
+// they merge are still overridden by the explicit keys. Comments move along with their keys.
+// A mapping is left as it is if sorting would move an alias in front of the anchor it refers to.
+func sortMappingKeys(mapping *yaml.Node) {
+	type pair struct {
+		key, value *yaml.Node
+		index      int // position before sorting
+	}
+	pairs := make([]pair, 0, len(mapping.Content)/2)
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		pairs = append(pairs, pair{mapping.Content[i], mapping.Content[i+1], i / 2})
+	}
+	sort.SliceStable(pairs, func(i, j int) bool {
+		mergeI, mergeJ := pairs[i].key.Value == "<<", pairs[j].key.Value == "<<"
+		if mergeI || mergeJ {
+			return mergeI && !mergeJ
+		}
+		return pairs[i].key.Value < pairs[j].key.Value
+	})
+
+	// definedIn maps each anchored node of the mapping to the original position of its pair
+	definedIn := make(map[*yaml.Node]int)
+	for _, p := range pairs {
+		walkNodes(p.key, func(n *yaml.Node) {
+			if n.Anchor != "" {
+				definedIn[n] = p.index
+			}
+		})
+		walkNodes(p.value, func(n *yaml.Node) {
+			if n.Anchor != "" {
+				definedIn[n] = p.index
+			}
+		})
+	}
+	if len(definedIn) > 0 {
+		position := make(map[int]int, len(pairs)) // original position -> sorted position
+		for i, p := range pairs {
+			position[p.index] = i
+		}
+		for i, p := range pairs {
+			usedTooEarly := false
+			check := func(n *yaml.Node) {
+				if n.Kind != yaml.AliasNode {
+					return
+				}
+				if index, ok := definedIn[n.Alias]; ok && index != p.index && position[index] > i {
+					usedTooEarly = true
+				}
+			}
+			walkNodes(p.key, check)
+			walkNodes(p.value, check)
+			if usedTooEarly {
+				return
+			}
+		}
+	}
+
+	for i, p := range pairs {
+		mapping.Content[2*i], mapping.Content[2*i+1] = p.key, p.value
+	}
+}
+
+// walkNodes calls visit for node and its descendants in document order, without following aliases.
+func walkNodes(node *yaml.Node, visit func(*yaml.Node)) {
+	visit(node)
+	for _, child := range node.Content {
+		walkNodes(child, visit)
+	}
+}
@@ -0,0 +1,143 @@
+package yjvalid8r_lib
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultJSONLinesMaxErrors is the number of errors reported in JSON Lines mode when JSONLinesConfig.MaxErrors is 0.
+const DefaultJSONLinesMaxErrors = 100
+
+// Validate checks the error limit of the JSON Lines mode.
+func (c JSONLinesConfig) Validate() error {
+	if c.MaxErrors < 0 {
+		return fmt.Errorf("jsonLines: maxErrors must not be negative")
+	}
+	return nil
+}
+
+// Limit returns the number of errors reported: MaxErrors, or DefaultJSONLinesMaxErrors when 0.
+func (c JSONLinesConfig) Limit() int {
+	if c.MaxErrors == 0 {
+		return DefaultJSONLinesMaxErrors
+	}
+	return c.MaxErrors
+}
+
+// jsonLine is a non-blank line of JSON Lines data.
+type jsonLine struct {
+	index      int // zero-based line index, the Document index of the record
+	start, end int // byte offsets of the line in the data, without the line break
+}
+
+// splitJSONLines returns the non-blank lines of the data. A "\r" before the line break is not part of the line.
+func splitJSONLines(data []byte) []jsonLine {
+	var lines []jsonLine
+	for index, start := 0, 0; start < len(data); index++ {
+		end := bytes.IndexByte(data[start:], '\n')
+		next := start + end + 1
+		if end < 0 {
+			end, next = len(data)-start, len(data)
+		}
+		line := jsonLine{index: index, start: start, end: start + end}
+		if line.end > line.start && data[line.end-1] == '\r' {
+			line.end--
+		}
+		if len(bytes.TrimSpace(data[line.start:line.end])) > 0 {
+			lines = append(lines, line)
+		}
+		start = next
+	}
+	return lines
+}
+
+// DecodeJSONLines decodes JSON Lines (NDJSON) data: every non-blank line is a record holding one JSON value.
+// Each record becomes a document whose Index is its zero-based line index, with node lines pointing into data.
+// Lines that are not valid JSON are reported as findings instead, so the other records can still be validated.
+func DecodeJSONLines(data []byte) ([]Document, []Finding) {
+	var docs []Document
+	var findings []Finding
+	for _, line := range splitJSONLines(data) {
+		record := data[line.start:line.end]
+		if finding, ok := jsonLineSyntaxFinding(data, line); !ok {
+			findings = append(findings, finding)
+			continue
+		}
+		recordDocs, err := decodeYAMLDocuments(record)
+		if err != nil || len(recordDocs) != 1 {
+			lineNumber, column := offsetPosition(data, line.start)
+			findings = append(findings, Finding{
+				Severity: MessageTypeError,
+				RuleID:   RuleJSONLineSyntax,
+				Message:  fmt.Sprintf("Invalid JSON record: %v", err),
+				Document: line.index,
+				Line:     lineNumber,
+				Column:   column,
+			})
+			continue
+		}
+		node := recordDocs[0].Node
+		shiftNodeLines(node, line.index)
+		docs = append(docs, Document{Index: line.index, Node: node})
+	}
+	return docs, findings
+}
+
+// jsonLineSyntaxFinding reports a line that is not a single JSON value, at the position of the syntax error.
+func jsonLineSyntaxFinding(data []byte, line jsonLine) (Finding, bool) {
+	var value interface{}
+	err := json.Unmarshal(data[line.start:line.end], &value)
+	if err == nil {
+		return Finding{}, true
+	}
+	offset := line.end
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		// Offset counts the bytes read, including the offending one
+		offset = line.start + max(int(syntaxErr.Offset)-1, 0)
+	}
+	lineNumber, column := offsetPosition(data, offset)
+	return Finding{
+		Severity: MessageTypeError,
+		RuleID:   RuleJSONLineSyntax,
+		Message:  fmt.Sprintf("Invalid JSON record: %v", err),
+		Document: line.index,
+		Line:     lineNumber,
+		Column:   column,
+	}, false
+}
+
+// shiftNodeLines moves a node tree decoded from a single line down by delta lines.
+func shiftNodeLines(node *yaml.Node, delta int) {
+	node.Line += delta
+	for _, child := range node.Content {
+		shiftNodeLines(child, delta)
+	}
+}
+
+// isJSONLinesData reports whether the data has at least two lines and every non-blank line is a JSON value.
+func isJSONLinesData(data []byte) bool {
+	lines := splitJSONLines(data)
+	if len(lines) < 2 {
+		return false
+	}
+	for _, line := range lines {
+		if !json.Valid(data[line.start:line.end]) {
+			return false
+		}
+	}
+	return true
+}
+
+// decodeJSONLinesDocuments decodes JSON Lines data detected by isJSONLinesData.
+func decodeJSONLinesDocuments(data []byte) ([]Document, error) {
+	docs, findings := DecodeJSONLines(data)
+	if len(findings) > 0 {
+		return nil, fmt.Errorf("ndjson: line %d, column %d: %s", findings[0].Line, findings[0].Column, findings[0].Message)
+	}
+	return docs, nil
+}
@@ -0,0 +1,96 @@
+package yjvalid8r_lib
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// splitJSONPointer splits an RFC 6901 JSON pointer (e.g. "/metadata/labels/app") into unescaped tokens.
+func splitJSONPointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q: must start with '/'", pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		token = strings.ReplaceAll(token, "~1", "/")
+		tokens[i] = strings.ReplaceAll(token, "~0", "~")
+	}
+	return tokens, nil
+}
+
+// nodeAtPointer returns the YAML node addressed by a JSON pointer, or nil if it does not exist.
+func nodeAtPointer(root *yaml.Node, pointer string) (*yaml.Node, error) {
+	tokens, err := splitJSONPointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+
+	node := root
+	if node != nil && node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+
+	for _, token := range tokens {
+		if node == nil {
+			return nil, nil
+		}
+		switch node.Kind {
+		case yaml.MappingNode:
+			var next *yaml.Node
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if node.Content[i].Value == token {
+					next = node.Content[i+1]
+					break
+				}
+			}
+			node = next
+		case yaml.SequenceNode:
+			idx, err := strconv.Atoi(token)
+			if err != nil || idx < 0 || idx >= len(node.Content) {
+				return nil, nil
+			}
+			node = node.Content[idx]
+		default:
+			return nil, nil
+		}
+	}
+
+	return node, nil
+}
+
+// valueAtPointer returns the decoded JSON value addressed by a JSON pointer; false if it does not exist.
+func valueAtPointer(document interface{}, pointer string) (interface{}, bool, error) {
+	tokens, err := splitJSONPointer(pointer)
+	if err != nil {
+		return nil, false, err
+	}
+
+	value := document
+	for _, token := range tokens {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			next, ok := v[token]
+			if !ok {
+				return nil, false, nil
+			}
+			value = next
+		case []interface{}:
+			idx, err := strconv.Atoi(token)
+			if err != nil || idx < 0 || idx >= len(v) {
+				return nil, false, nil
+			}
+			value = v[idx]
+		default:
+			return nil, false, nil
+		}
+	}
+
+	return value, true, nil
+}
@@ -0,0 +1,539 @@
+package yjvalid8r_lib
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// isJSON5Data reports whether the data is a JSON5 value, e.g. JSON with comments and trailing commas (JSONC).
+// A value that is also flow-style YAML, e.g. {a: 1}, is left to YAML unless it uses syntax only JSON5 has.
+func isJSON5Data(data []byte) bool {
+	return (&formatProbe{data: data}).isJSON5()
+}
+
+func (p *formatProbe) isJSON5() bool {
+	parser := &json5Parser{data: p.data, line: 1}
+	if _, err := parser.parse(); err != nil {
+		return false
+	}
+	return parser.json5Only || !p.isFlowYAML()
+}
+
+// decodeJSON5Documents decodes a JSON5 value into a single document node. Duplicate keys are kept,
+// like the YAML decoder does, so they can be reported.
+func decodeJSON5Documents(data []byte) ([]Document, error) {
+	root, err := parseJSON5(data)
+	if err != nil {
+		return nil, err
+	}
+	document := &yaml.Node{Kind: yaml.DocumentNode, Line: root.Line, Column: root.Column, Content: []*yaml.Node{root}}
+	return []Document{{Index: 0, Node: document}}, nil
+}
+
+// json5Parser is a recursive descent parser for JSON5 (https://spec.json5.org) producing YAML nodes.
+type json5Parser struct {
+	data      []byte
+	pos       int  // byte offset of the next rune
+	line      int  // line of pos
+	lineStart int  // byte offset of the start of that line
+	json5Only bool // whether a comment, trailing comma, single-quoted string, hex number, Infinity or NaN was parsed
+}
+
+// parseJSON5 parses a single JSON5 value surrounded by optional whitespace and comments.
+func parseJSON5(data []byte) (*yaml.Node, error) {
+	p := &json5Parser{data: data, line: 1}
+	return p.parse()
+}
+
+// parse parses the data of the parser as a single JSON5 value, see parseJSON5.
+func (p *json5Parser) parse() (*yaml.Node, error) {
+	if err := p.skipSpace(); err != nil {
+		return nil, err
+	}
+	node, err := p.value()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.skipSpace(); err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.data) {
+		return nil, p.errorf("unexpected %s after top-level value", p.describe())
+	}
+	return node, nil
+}
+
+func (p *json5Parser) errorf(format string, args ...interface{}) error {
+	line, column := p.position()
+	return fmt.Errorf("json5: line %d, column %d: %s", line, column, fmt.Sprintf(format, args...))
+}
+
+// position returns the line and (rune-based) column of the next rune.
+func (p *json5Parser) position() (int, int) {
+	return p.line, utf8.RuneCount(p.data[p.lineStart:p.pos]) + 1
+}
+
+// peek returns the next rune without consuming it; -1 at the end of the data.
+func (p *json5Parser) peek() rune {
+	if p.pos >= len(p.data) {
+		return -1
+	}
+	r, _ := utf8.DecodeRune(p.data[p.pos:])
+	return r
+}
+
+// next consumes the next rune, keeping track of lines. CRLF counts as one line break.
+func (p *json5Parser) next() rune {
+	r, size := utf8.DecodeRune(p.data[p.pos:])
+	p.pos += size
+	if isJSON5LineTerminator(r) && !(r == '\r' && p.peek() == '\n') {
+		p.line++
+		p.lineStart = p.pos
+	}
+	return r
+}
+
+// describe names the next rune for error messages.
+func (p *json5Parser) describe() string {
+	if r := p.peek(); r >= 0 {
+		return strconv.QuoteRune(r)
+	}
+	return "end of data"
+}
+
+func isJSON5LineTerminator(r rune) bool {
+	return r == '\n' || r == '\r' || r == '\u2028' || r == '\u2029'
+}
+
+// skipSpace skips whitespace and comments.
+func (p *json5Parser) skipSpace() error {
+	for p.pos < len(p.data) {
+		r := p.peek()
+		switch {
+		case r == '\uFEFF' || unicode.IsSpace(r) || unicode.Is(unicode.Zs, r):
+			p.next()
+		case bytes.HasPrefix(p.data[p.pos:], []byte("//")):
+			p.json5Only = true
+			for p.pos < len(p.data) && !isJSON5LineTerminator(p.peek()) {
+				p.next()
+			}
+		case bytes.HasPrefix(p.data[p.pos:], []byte("/*")):
+			p.json5Only = true
+			end := bytes.Index(p.data[p.pos+2:], []byte("*/"))
+			if end < 0 {
+				return p.errorf("unterminated comment")
+			}
+			for stop := p.pos + 2 + end + 2; p.pos < stop; {
+				p.next()
+			}
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// value parses an object, array, string, number or literal.
+func (p *json5Parser) value() (*yaml.Node, error) {
+	line, column := p.position()
+	switch r := p.peek(); {
+	case r == '{':
+		return p.object(line, column)
+	case r == '[':
+		return p.array(line, column)
+	case r == '"' || r == '\'':
+		value, err := p.string()
+		if err != nil {
+			return nil, err
+		}
+		style := yaml.DoubleQuotedStyle
+		if r == '\'' {
+			style = yaml.SingleQuotedStyle
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value, Style: style, Line: line, Column: column}, nil
+	case r == '-' || r == '+' || r == '.' || (r >= '0' && r <= '9'):
+		tag, value, err := p.number()
+		if err != nil {
+			return nil, err
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: value, Line: line, Column: column}, nil
+	case r >= 0 && isJSON5IdentifierStart(r):
+		start := *p
+		word := p.identifier()
+		switch word {
+		case "true", "false":
+			return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: word, Line: line, Column: column}, nil
+		case "null":
+			return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: word, Line: line, Column: column}, nil
+		case "Infinity":
+			p.json5Only = true
+			return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!float", Value: ".inf", Line: line, Column: column}, nil
+		case "NaN":
+			p.json5Only = true
+			return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!float", Value: ".nan", Line: line, Column: column}, nil
+		}
+		*p = start
+		return nil, p.errorf("unexpected identifier %q", word)
+	}
+	return nil, p.errorf("unexpected %s, expected a value", p.describe())
+}
+
+// object parses the members of an object; a trailing comma is allowed.
+func (p *json5Parser) object(line, column int) (*yaml.Node, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Style: yaml.FlowStyle, Line: line, Column: column}
+	p.next() // {
+	trailingComma := false
+	for {
+		if err := p.skipSpace(); err != nil {
+			return nil, err
+		}
+		if p.peek() == '}' {
+			p.next()
+			p.json5Only = p.json5Only || trailingComma
+			return node, nil
+		}
+
+		key, err := p.key()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.skipSpace(); err != nil {
+			return nil, err
+		}
+		if p.peek() != ':' {
+			return nil, p.errorf("unexpected %s, expected ':' after object key", p.describe())
+		}
+		p.next()
+		if err := p.skipSpace(); err != nil {
+			return nil, err
+		}
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		node.Content = append(node.Content, key, value)
+
+		if err := p.skipSpace(); err != nil {
+			return nil, err
+		}
+		trailingComma = p.peek() == ','
+		switch p.peek() {
+		case ',':
+			p.next()
+		case '}':
+		default:
+			return nil, p.errorf("unexpected %s, expected ',' or '}'", p.describe())
+		}
+	}
+}
+
+// key parses an object key: a string or an identifier.
+func (p *json5Parser) key() (*yaml.Node, error) {
+	line, column := p.position()
+	node := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Line: line, Column: column}
+	switch r := p.peek(); {
+	case r == '"' || r == '\'':
+		value, err := p.string()
+		if err != nil {
+			return nil, err
+		}
+		node.Value = value
+	case r >= 0 && (isJSON5IdentifierStart(r) || r == '\\'):
+		value, err := p.identifierName()
+		if err != nil {
+			return nil, err
+		}
+		node.Value = value
+	default:
+		return nil, p.errorf("unexpected %s, expected an object key", p.describe())
+	}
+	return node, nil
+}
+
+// array parses the elements of an array; a trailing comma is allowed.
+func (p *json5Parser) array(line, column int) (*yaml.Node, error) {
+	node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Style: yaml.FlowStyle, Line: line, Column: column}
+	p.next() // [
+	trailingComma := false
+	for {
+		if err := p.skipSpace(); err != nil {
+			return nil, err
+		}
+		if p.peek() == ']' {
+			p.next()
+			p.json5Only = p.json5Only || trailingComma
+			return node, nil
+		}
+
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		node.Content = append(node.Content, value)
+
+		if err := p.skipSpace(); err != nil {
+			return nil, err
+		}
+		trailingComma = p.peek() == ','
+		switch p.peek() {
+		case ',':
+			p.next()
+		case ']':
+		default:
+			return nil, p.errorf("unexpected %s, expected ',' or ']'", p.describe())
+		}
+	}
+}
+
+// string parses a single- or double-quoted string with JSON5 escapes and line continuations.
+func (p *json5Parser) string() (string, error) {
+	quote := p.next()
+	p.json5Only = p.json5Only || quote == '\''
+	var sb strings.Builder
+	for {
+		if p.pos >= len(p.data) {
+			return "", p.errorf("unterminated string")
+		}
+		r := p.peek()
+		switch {
+		case r == quote:
+			p.next()
+			return sb.String(), nil
+		case r == '\n' || r == '\r':
+			return "", p.errorf("unescaped line break in string")
+		case r == '\\':
+			p.next()
+			if err := p.escape(&sb); err != nil {
+				return "", err
+			}
+		default:
+			sb.WriteRune(p.next())
+		}
+	}
+}
+
+// escape parses the escape sequence after a backslash.
+func (p *json5Parser) escape(sb *strings.Builder) error {
+	if p.pos >= len(p.data) {
+		return p.errorf("unterminated string")
+	}
+	r := p.next()
+	switch r {
+	case 'b':
+		sb.WriteByte('\b')
+	case 'f':
+		sb.WriteByte('\f')
+	case 'n':
+		sb.WriteByte('\n')
+	case 'r':
+		sb.WriteByte('\r')
+	case 't':
+		sb.WriteByte('\t')
+	case 'v':
+		sb.WriteByte('\v')
+	case '0':
+		if next := p.peek(); next >= '0' && next <= '9' {
+			return p.errorf("octal escapes are not allowed")
+		}
+		sb.WriteByte(0)
+	case 'x':
+		code, err := p.hex(2)
+		if err != nil {
+			return err
+		}
+		sb.WriteRune(rune(code))
+	case 'u':
+		code, err := p.unicodeEscape()
+		if err != nil {
+			return err
+		}
+		sb.WriteRune(code)
+	case '\r':
+		if p.peek() == '\n' {
+			p.next()
+		}
+	case '\n', '\u2028', '\u2029':
+		// line continuation
+	default:
+		if r >= '1' && r <= '9' {
+			return p.errorf("octal escapes are not allowed")
+		}
+		sb.WriteRune(r)
+	}
+	return nil
+}
+
+// unicodeEscape parses the four hex digits after \u, combining a surrogate pair.
+func (p *json5Parser) unicodeEscape() (rune, error) {
+	code, err := p.hex(4)
+	if err != nil {
+		return 0, err
+	}
+	r := rune(code)
+	if utf16.IsSurrogate(r) && bytes.HasPrefix(p.data[p.pos:], []byte("\\u")) {
+		start := *p
+		p.next()
+		p.next()
+		low, err := p.hex(4)
+		if err == nil {
+			if combined := utf16.DecodeRune(r, rune(low)); combined != unicode.ReplacementChar {
+				return combined, nil
+			}
+		}
+		*p = start
+	}
+	return r, nil
+}
+
+// hex parses n hex digits.
+func (p *json5Parser) hex(n int) (uint64, error) {
+	if p.pos+n > len(p.data) {
+		return 0, p.errorf("invalid escape sequence")
+	}
+	code, err := strconv.ParseUint(string(p.data[p.pos:p.pos+n]), 16, 32)
+	if err != nil {
+		return 0, p.errorf("invalid escape sequence")
+	}
+	for i := 0; i < n; i++ {
+		p.next()
+	}
+	return code, nil
+}
+
+// number parses a JSON5 number and returns its YAML tag and a value the YAML decoder reads back exactly:
+// integers in decimal (hexadecimal ones converted), other numbers as floats.
+func (p *json5Parser) number() (string, string, error) {
+	start := p.pos
+	sign := ""
+	if r := p.peek(); r == '+' || r == '-' {
+		p.next()
+		if r == '-' {
+			sign = "-"
+		}
+	}
+
+	if isJSON5IdentifierStart(p.peek()) {
+		p.json5Only = true
+		switch word := p.identifier(); word {
+		case "Infinity":
+			return "!!float", sign + ".inf", nil
+		case "NaN":
+			return "!!float", ".nan", nil
+		default:
+			return "", "", p.errorf("invalid number %q", string(p.data[start:p.pos]))
+		}
+	}
+
+	text := p.scanNumber()
+	literal := string(p.data[start:p.pos])
+	if strings.HasPrefix(text, "0x") || strings.HasPrefix(text, "0X") {
+		p.json5Only = true
+		value, err := strconv.ParseUint(text[2:], 16, 64)
+		if err != nil || len(text) == 2 {
+			return "", "", p.errorf("invalid number %q", literal)
+		}
+		if value <= math.MaxInt64 {
+			return "!!int", sign + strconv.FormatUint(value, 10), nil
+		}
+		return "!!float", sign + strconv.FormatFloat(float64(value), 'g', -1, 64), nil
+	}
+
+	leadingDigits := len(text) - len(strings.TrimLeft(text, "0123456789"))
+	if text == "" || text == "." || strings.HasPrefix(text, ".e") || strings.HasPrefix(text, ".E") ||
+		(text[0] == '0' && leadingDigits > 1) {
+		return "", "", p.errorf("invalid number %q", literal)
+	}
+	if !strings.ContainsAny(text, ".eE") {
+		if _, err := strconv.ParseInt(sign+text, 10, 64); err == nil {
+			return "!!int", sign + text, nil
+		}
+	}
+	value, err := strconv.ParseFloat(sign+text, 64)
+	switch {
+	case err != nil && !errors.Is(err, strconv.ErrRange):
+		return "", "", p.errorf("invalid number %q", literal)
+	case math.IsInf(value, 0):
+		return "!!float", sign + ".inf", nil
+	}
+	return "!!float", strconv.FormatFloat(value, 'g', -1, 64), nil
+}
+
+// scanNumber consumes the characters of an unsigned number literal and returns them.
+func (p *json5Parser) scanNumber() string {
+	start := p.pos
+	if bytes.HasPrefix(p.data[p.pos:], []byte("0x")) || bytes.HasPrefix(p.data[p.pos:], []byte("0X")) {
+		p.next()
+		p.next()
+		for r := p.peek(); (r >= '0' && r <= '9') || (r >= 'a' && r <= 'f') || (r >= 'A' && r <= 'F'); r = p.peek() {
+			p.next()
+		}
+		return string(p.data[start:p.pos])
+	}
+	for r := p.peek(); (r >= '0' && r <= '9') || r == '.'; r = p.peek() {
+		p.next()
+	}
+	if r := p.peek(); r == 'e' || r == 'E' {
+		p.next()
+		if r := p.peek(); r == '+' || r == '-' {
+			p.next()
+		}
+		for r := p.peek(); r >= '0' && r <= '9'; r = p.peek() {
+			p.next()
+		}
+	}
+	return string(p.data[start:p.pos])
+}
+
+// identifier consumes a run of identifier characters.
+func (p *json5Parser) identifier() string {
+	start := p.pos
+	for r := p.peek(); r >= 0 && isJSON5IdentifierPart(r); r = p.peek() {
+		p.next()
+	}
+	return string(p.data[start:p.pos])
+}
+
+// identifierName parses an unquoted object key, which may contain \uXXXX escapes.
+func (p *json5Parser) identifierName() (string, error) {
+	var sb strings.Builder
+	for {
+		r := p.peek()
+		switch {
+		case r == '\\':
+			p.next()
+			if p.peek() != 'u' {
+				return "", p.errorf("invalid escape sequence in identifier")
+			}
+			p.next()
+			code, err := p.unicodeEscape()
+			if err != nil {
+				return "", err
+			}
+			sb.WriteRune(code)
+		case r >= 0 && (isJSON5IdentifierPart(r) && (sb.Len() > 0 || isJSON5IdentifierStart(r))):
+			sb.WriteRune(p.next())
+		default:
+			if sb.Len() == 0 {
+				return "", p.errorf("unexpected %s, expected an object key", p.describe())
+			}
+			return sb.String(), nil
+		}
+	}
+}
+
+func isJSON5IdentifierStart(r rune) bool {
+	return r == '$' || r == '_' || unicode.IsLetter(r) || unicode.Is(unicode.Nl, r)
+}
+
+func isJSON5IdentifierPart(r rune) bool {
+	return isJSON5IdentifierStart(r) || unicode.IsDigit(r) || unicode.In(r, unicode.Mn, unicode.Mc, unicode.Pc) || r == '\u200C' || r == '\u200D'
+}
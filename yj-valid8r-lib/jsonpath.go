@@ -0,0 +1,803 @@
+package yjvalid8r_lib
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// jsonPath is a compiled JSONPath query (RFC 9535): a root `$` followed by child and descendant segments.
+// Supported are member names (`.name`, `['name']`), wildcards (`*`), array indexes and slices (`[0]`, `[-1]`,
+// `[1:3]`, `[::2]`), unions (`[0,2]`) and filter expressions (`[?@.port > 80]`, `[?(@.port > 80)]`) with the
+// comparison operators, `&&`, `||`, `!`, existence tests and the functions length, count, match and search.
+type jsonPath struct {
+	segments []jsonPathSegment
+}
+
+// jsonPathSegment selects nodes from the children of its input nodes, or from all their descendants.
+type jsonPathSegment struct {
+	descendant bool
+	selectors  []jsonPathSelector
+}
+
+type jsonPathSelectorKind int
+
+const (
+	selectName jsonPathSelectorKind = iota
+	selectWildcard
+	selectIndex
+	selectSlice
+	selectFilter
+)
+
+type jsonPathSelector struct {
+	kind   jsonPathSelectorKind
+	name   string
+	index  int
+	slice  [3]*int // start, end and step; nil when omitted
+	filter filterExpr
+}
+
+// jsonPathNode is a node selected by a query, with the normalized path leading to it, e.g. $.ports[0].name.
+type jsonPathNode struct {
+	path string
+	node *yaml.Node // aliases are resolved to the node they refer to
+	at   *yaml.Node // where the node appears in the document: the alias, for a node used through one
+}
+
+// value decodes the node; ok is false if it cannot be decoded.
+func (n jsonPathNode) value() (v interface{}, ok bool) {
+	if err := n.node.Decode(&v); err != nil {
+		return nil, false
+	}
+	return v, true
+}
+
+// compileJSONPath parses a JSONPath query.
+func compileJSONPath(query string) (*jsonPath, error) {
+	p := &jsonPathParser{src: query}
+	if !p.consume("$") {
+		return nil, p.errorf("query must start with $")
+	}
+	segments, err := p.parseSegments()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.pos < len(p.src) {
+		return nil, p.errorf("unexpected %q", p.src[p.pos:])
+	}
+	return &jsonPath{segments: segments}, nil
+}
+
+// evaluate returns the nodes the query selects in the node tree of a document, in document order.
+func (q *jsonPath) evaluate(root *yaml.Node) []jsonPathNode {
+	if root == nil {
+		return nil
+	}
+	return evaluateSegments(q.segments, []jsonPathNode{{path: "$", node: root, at: root}}, root)
+}
+
+func evaluateSegments(segments []jsonPathSegment, nodes []jsonPathNode, root *yaml.Node) []jsonPathNode {
+	for _, segment := range segments {
+		var selected []jsonPathNode
+		for _, node := range nodes {
+			inputs := []jsonPathNode{node}
+			if segment.descendant {
+				inputs = descendants(node, nil)
+			}
+			for _, input := range inputs {
+				for _, selector := range segment.selectors {
+					selected = append(selected, selector.apply(input, root)...)
+				}
+			}
+		}
+		nodes = selected
+	}
+	return nodes
+}
+
+// descendants returns a node and all nodes nested in it, each before its children.
+func descendants(node jsonPathNode, nodes []jsonPathNode) []jsonPathNode {
+	nodes = append(nodes, node)
+	for _, child := range children(node) {
+		nodes = descendants(child, nodes)
+	}
+	return nodes
+}
+
+// children returns the elements of a sequence or the members of a mapping (merged ones included), in document order.
+func children(node jsonPathNode) []jsonPathNode {
+	switch node.node.Kind {
+	case yaml.SequenceNode:
+		nodes := make([]jsonPathNode, len(node.node.Content))
+		for i := range node.node.Content {
+			nodes[i] = elementNode(node, i)
+		}
+		return nodes
+	case yaml.MappingNode:
+		var nodes []jsonPathNode
+		for _, pair := range mappingPairs(node.node) {
+			nodes = append(nodes, jsonPathNode{path: memberPath(node.path, pair.key.Value), node: pair.value, at: pair.at})
+		}
+		return nodes
+	}
+	return nil
+}
+
+// elementNode returns the element at index of a sequence node.
+func elementNode(node jsonPathNode, index int) jsonPathNode {
+	item, at := followAlias(node.node.Content[index])
+	return jsonPathNode{path: indexPath(node.path, index), node: item, at: at}
+}
+
+func (s jsonPathSelector) apply(node jsonPathNode, root *yaml.Node) []jsonPathNode {
+	switch s.kind {
+	case selectName:
+		if value, at := mappingValue(node.node, s.name); value != nil {
+			return []jsonPathNode{{path: memberPath(node.path, s.name), node: value, at: at}}
+		}
+	case selectWildcard:
+		return children(node)
+	case selectIndex:
+		if node.node.Kind == yaml.SequenceNode {
+			index := s.index
+			if index < 0 {
+				index += len(node.node.Content)
+			}
+			if index >= 0 && index < len(node.node.Content) {
+				return []jsonPathNode{elementNode(node, index)}
+			}
+		}
+	case selectSlice:
+		if node.node.Kind == yaml.SequenceNode {
+			var nodes []jsonPathNode
+			for _, index := range sliceIndexes(s.slice, len(node.node.Content)) {
+				nodes = append(nodes, elementNode(node, index))
+			}
+			return nodes
+		}
+	case selectFilter:
+		var nodes []jsonPathNode
+		for _, child := range children(node) {
+			if s.filter.test(filterContext{root: root, current: child.node}) {
+				nodes = append(nodes, child)
+			}
+		}
+		return nodes
+	}
+	return nil
+}
+
+// sliceIndexes returns the indexes an array slice [start:end:step] selects from an array of the given length.
+func sliceIndexes(slice [3]*int, length int) []int {
+	step := 1
+	if slice[2] != nil {
+		step = *slice[2]
+	}
+	if step == 0 {
+		return nil
+	}
+	bound := func(value *int, def int) int {
+		if value == nil {
+			return def
+		}
+		if *value < 0 {
+			return *value + length
+		}
+		return *value
+	}
+	clamp := func(i, lower, upper int) int {
+		return min(max(i, lower), upper)
+	}
+
+	var indexes []int
+	if step > 0 {
+		start := clamp(bound(slice[0], 0), 0, length)
+		end := clamp(bound(slice[1], length), 0, length)
+		for i := start; i < end; i += step {
+			indexes = append(indexes, i)
+			if step >= end-i { // the next index is past the end; stop before i += step overflows
+				break
+			}
+		}
+	} else {
+		start := clamp(bound(slice[0], length-1), -1, length-1)
+		end := clamp(bound(slice[1], -1), -1, length-1)
+		for i := start; i > end; i += step {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+// jsonPathName matches member names that need no brackets in a normalized path.
+var jsonPathName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func memberPath(path, name string) string {
+	if jsonPathName.MatchString(name) {
+		return path + "." + name
+	}
+	return path + "['" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(name) + "']"
+}
+
+func indexPath(path string, index int) string {
+	return fmt.Sprintf("%s[%d]", path, index)
+}
+
+// filterContext holds the document root ($) and the node being tested (@).
+type filterContext struct {
+	root    *yaml.Node
+	current *yaml.Node
+}
+
+// filterExpr is a logical expression of a filter selector.
+type filterExpr interface {
+	test(ctx filterContext) bool
+}
+
+// filterOperand is a value compared in a filter: a literal, a query or a function call.
+// ok is false for "nothing", e.g. a query that selects no node.
+type filterOperand interface {
+	value(ctx filterContext) (v interface{}, ok bool)
+}
+
+type orExpr struct{ left, right filterExpr }
+type andExpr struct{ left, right filterExpr }
+type notExpr struct{ expr filterExpr }
+
+func (e orExpr) test(ctx filterContext) bool  { return e.left.test(ctx) || e.right.test(ctx) }
+func (e andExpr) test(ctx filterContext) bool { return e.left.test(ctx) && e.right.test(ctx) }
+func (e notExpr) test(ctx filterContext) bool { return !e.expr.test(ctx) }
+
+// existenceExpr is true when its query selects at least one node.
+type existenceExpr struct{ query *filterQuery }
+
+func (e existenceExpr) test(ctx filterContext) bool { return len(e.query.nodes(ctx)) > 0 }
+
+// functionExpr is a test by a function returning a logical value: match or search.
+type functionExpr struct{ call *functionCall }
+
+func (e functionExpr) test(ctx filterContext) bool {
+	result, ok := e.call.value(ctx)
+	return ok && result == true
+}
+
+type comparisonExpr struct {
+	op          string
+	left, right filterOperand
+}
+
+func (e comparisonExpr) test(ctx filterContext) bool {
+	left, leftOK := e.left.value(ctx)
+	right, rightOK := e.right.value(ctx)
+	switch e.op {
+	case "==":
+		return compareEqual(left, leftOK, right, rightOK)
+	case "!=":
+		return !compareEqual(left, leftOK, right, rightOK)
+	case "<":
+		return compareLess(left, leftOK, right, rightOK)
+	case ">":
+		return compareLess(right, rightOK, left, leftOK)
+	case "<=":
+		return compareLess(left, leftOK, right, rightOK) || compareEqual(left, leftOK, right, rightOK)
+	case ">=":
+		return compareLess(right, rightOK, left, leftOK) || compareEqual(left, leftOK, right, rightOK)
+	}
+	return false
+}
+
+// compareEqual compares two filter values; numbers are equal by value, arrays and objects by content,
+// and "nothing" only equals "nothing".
+func compareEqual(a interface{}, aOK bool, b interface{}, bOK bool) bool {
+	if !aOK || !bOK {
+		return aOK == bOK
+	}
+	if x, ok := filterNumber(a); ok {
+		y, ok := filterNumber(b)
+		return ok && x == y
+	}
+	switch a.(type) {
+	case []interface{}, map[string]interface{}:
+		return marshalToString(a) == marshalToString(b)
+	}
+	switch b.(type) {
+	case []interface{}, map[string]interface{}:
+		return false
+	}
+	return a == b
+}
+
+// compareLess orders numbers by value and strings by code point; other values are not ordered.
+func compareLess(a interface{}, aOK bool, b interface{}, bOK bool) bool {
+	if !aOK || !bOK {
+		return false
+	}
+	if x, ok := filterNumber(a); ok {
+		y, ok := filterNumber(b)
+		return ok && x < y
+	}
+	x, ok := a.(string)
+	y, ok2 := b.(string)
+	return ok && ok2 && x < y
+}
+
+func filterNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+type literalOperand struct{ v interface{} }
+
+func (l literalOperand) value(filterContext) (interface{}, bool) { return l.v, true }
+
+// filterQuery is a query inside a filter, relative to the current node (@) or the root ($).
+type filterQuery struct {
+	absolute bool
+	segments []jsonPathSegment
+}
+
+func (q *filterQuery) nodes(ctx filterContext) []jsonPathNode {
+	start := ctx.current
+	if q.absolute {
+		start = ctx.root
+	}
+	return evaluateSegments(q.segments, []jsonPathNode{{path: "$", node: start, at: start}}, ctx.root)
+}
+
+// value of a query is the value of the single node it selects; several nodes or none are "nothing".
+func (q *filterQuery) value(ctx filterContext) (interface{}, bool) {
+	nodes := q.nodes(ctx)
+	if len(nodes) != 1 {
+		return nil, false
+	}
+	return nodes[0].value()
+}
+
+type functionCall struct {
+	name    string
+	args    []filterOperand
+	pattern *regexp.Regexp // match and search with a literal pattern, compiled once
+}
+
+// jsonPathFunctions maps the supported function names to their number of arguments.
+var jsonPathFunctions = map[string]int{"length": 1, "count": 1, "match": 2, "search": 2}
+
+func (f *functionCall) value(ctx filterContext) (interface{}, bool) {
+	switch f.name {
+	case "length":
+		switch v, _ := f.args[0].value(ctx); v := v.(type) {
+		case string:
+			return utf8.RuneCountInString(v), true
+		case []interface{}:
+			return len(v), true
+		case map[string]interface{}:
+			return len(v), true
+		}
+		return nil, false
+	case "count":
+		return len(f.args[0].(*filterQuery).nodes(ctx)), true
+	default: // match, search
+		v, _ := f.args[0].value(ctx)
+		s, ok := v.(string)
+		if !ok {
+			return false, true
+		}
+		re := f.pattern
+		if re == nil {
+			p, _ := f.args[1].value(ctx)
+			pattern, ok := p.(string)
+			if !ok {
+				return false, true
+			}
+			var err error
+			if re, err = compileFunctionPattern(f.name, pattern); err != nil {
+				return false, true
+			}
+		}
+		return re.MatchString(s), true
+	}
+}
+
+// compileFunctionPattern compiles the pattern of match, which must match the whole string, or search.
+func compileFunctionPattern(name, pattern string) (*regexp.Regexp, error) {
+	if name == "match" {
+		pattern = `^(?:` + pattern + `)$`
+	}
+	return regexp.Compile(pattern)
+}
+
+// jsonPathParser is a recursive descent parser for JSONPath queries.
+type jsonPathParser struct {
+	src string
+	pos int
+}
+
+func (p *jsonPathParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%s at position %d", fmt.Sprintf(format, args...), p.pos+1)
+}
+
+func (p *jsonPathParser) peek() byte {
+	if p.pos < len(p.src) {
+		return p.src[p.pos]
+	}
+	return 0
+}
+
+func (p *jsonPathParser) consume(s string) bool {
+	if strings.HasPrefix(p.src[p.pos:], s) {
+		p.pos += len(s)
+		return true
+	}
+	return false
+}
+
+func (p *jsonPathParser) skipSpace() {
+	for p.pos < len(p.src) && strings.IndexByte(" \t\r\n", p.src[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+func (p *jsonPathParser) parseSegments() ([]jsonPathSegment, error) {
+	var segments []jsonPathSegment
+	for {
+		start := p.pos
+		p.skipSpace()
+		var segment jsonPathSegment
+		switch {
+		case p.consume(".."):
+			segment.descendant = true
+			if p.peek() == '[' {
+				selectors, err := p.parseBracket()
+				if err != nil {
+					return nil, err
+				}
+				segment.selectors = selectors
+				break
+			}
+			fallthrough
+		case p.consume("."):
+			if p.consume("*") {
+				segment.selectors = []jsonPathSelector{{kind: selectWildcard}}
+				break
+			}
+			name := p.parseName()
+			if name == "" {
+				return nil, p.errorf("expected a member name")
+			}
+			segment.selectors = []jsonPathSelector{{kind: selectName, name: name}}
+		case p.peek() == '[':
+			selectors, err := p.parseBracket()
+			if err != nil {
+				return nil, err
+			}
+			segment.selectors = selectors
+		default:
+			p.pos = start
+			return segments, nil
+		}
+		segments = append(segments, segment)
+	}
+}
+
+// parseName reads a member name in dot notation: letters, digits, _ and -, not starting with a digit or -.
+func (p *jsonPathParser) parseName() string {
+	start := p.pos
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		if c == '_' || c >= 0x80 || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') ||
+			(p.pos > start && (c == '-' || (c >= '0' && c <= '9'))) {
+			p.pos++
+			continue
+		}
+		break
+	}
+	return p.src[start:p.pos]
+}
+
+func (p *jsonPathParser) parseBracket() ([]jsonPathSelector, error) {
+	p.consume("[")
+	var selectors []jsonPathSelector
+	for {
+		p.skipSpace()
+		selector, err := p.parseSelector()
+		if err != nil {
+			return nil, err
+		}
+		selectors = append(selectors, selector)
+		p.skipSpace()
+		if p.consume("]") {
+			return selectors, nil
+		}
+		if !p.consume(",") {
+			return nil, p.errorf("expected , or ]")
+		}
+	}
+}
+
+func (p *jsonPathParser) parseSelector() (jsonPathSelector, error) {
+	switch c := p.peek(); {
+	case c == '\'' || c == '"':
+		name, err := p.parseString()
+		return jsonPathSelector{kind: selectName, name: name}, err
+	case c == '*':
+		p.pos++
+		return jsonPathSelector{kind: selectWildcard}, nil
+	case c == '?':
+		p.pos++
+		filter, err := p.parseOr()
+		return jsonPathSelector{kind: selectFilter, filter: filter}, err
+	}
+
+	var selector jsonPathSelector
+	start, hasStart, err := p.parseInt()
+	if err != nil {
+		return selector, err
+	}
+	p.skipSpace()
+	if !p.consume(":") {
+		if !hasStart {
+			return selector, p.errorf("expected a selector")
+		}
+		return jsonPathSelector{kind: selectIndex, index: start}, nil
+	}
+
+	selector.kind = selectSlice
+	if hasStart {
+		selector.slice[0] = &start
+	}
+	for i := 1; i <= 2; i++ {
+		p.skipSpace()
+		value, ok, err := p.parseInt()
+		if err != nil {
+			return selector, err
+		}
+		if ok {
+			selector.slice[i] = &value
+		}
+		p.skipSpace()
+		if i == 1 && !p.consume(":") {
+			break
+		}
+	}
+	return selector, nil
+}
+
+// parseInt reads an optional integer; ok is false if there is none.
+func (p *jsonPathParser) parseInt() (value int, ok bool, err error) {
+	start := p.pos
+	p.consume("-")
+	for p.pos < len(p.src) && p.src[p.pos] >= '0' && p.src[p.pos] <= '9' {
+		p.pos++
+	}
+	if p.pos == start {
+		return 0, false, nil
+	}
+	value, err = strconv.Atoi(p.src[start:p.pos])
+	if err != nil {
+		p.pos = start
+		return 0, false, p.errorf("invalid integer %q", p.src[start:p.pos])
+	}
+	return value, true, nil
+}
+
+// parseString reads a single- or double-quoted string literal with JSON-style escapes.
+func (p *jsonPathParser) parseString() (string, error) {
+	quote := p.src[p.pos]
+	p.pos++
+	var sb strings.Builder
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		p.pos++
+		switch c {
+		case quote:
+			return sb.String(), nil
+		case '\\':
+			if p.pos >= len(p.src) {
+				return "", p.errorf("unterminated string")
+			}
+			escape := p.src[p.pos]
+			p.pos++
+			switch escape {
+			case 'b':
+				sb.WriteByte('\b')
+			case 'f':
+				sb.WriteByte('\f')
+			case 'n':
+				sb.WriteByte('\n')
+			case 'r':
+				sb.WriteByte('\r')
+			case 't':
+				sb.WriteByte('\t')
+			case 'u':
+				if p.pos+4 > len(p.src) {
+					return "", p.errorf("invalid unicode escape")
+				}
+				r, err := strconv.ParseUint(p.src[p.pos:p.pos+4], 16, 32)
+				if err != nil {
+					return "", p.errorf("invalid unicode escape")
+				}
+				sb.WriteRune(rune(r))
+				p.pos += 4
+			case '\'', '"', '\\', '/':
+				sb.WriteByte(escape)
+			default:
+				return "", p.errorf("invalid escape \\%c", escape)
+			}
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
+
+func (p *jsonPathParser) parseOr() (filterExpr, error) {
+	left, err := p.parseAnd()
+	for err == nil {
+		p.skipSpace()
+		if !p.consume("||") {
+			break
+		}
+		var right filterExpr
+		if right, err = p.parseAnd(); err == nil {
+			left = orExpr{left: left, right: right}
+		}
+	}
+	return left, err
+}
+
+func (p *jsonPathParser) parseAnd() (filterExpr, error) {
+	left, err := p.parseUnary()
+	for err == nil {
+		p.skipSpace()
+		if !p.consume("&&") {
+			break
+		}
+		var right filterExpr
+		if right, err = p.parseUnary(); err == nil {
+			left = andExpr{left: left, right: right}
+		}
+	}
+	return left, err
+}
+
+func (p *jsonPathParser) parseUnary() (filterExpr, error) {
+	p.skipSpace()
+	if p.consume("!") {
+		expr, err := p.parseUnary()
+		return notExpr{expr: expr}, err
+	}
+	if p.consume("(") {
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		if !p.consume(")") {
+			return nil, p.errorf("expected )")
+		}
+		return expr, nil
+	}
+
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if p.consume(op) {
+			right, err := p.parseOperand()
+			return comparisonExpr{op: op, left: left, right: right}, err
+		}
+	}
+
+	switch operand := left.(type) {
+	case *filterQuery:
+		return existenceExpr{query: operand}, nil
+	case *functionCall:
+		if operand.name == "match" || operand.name == "search" {
+			return functionExpr{call: operand}, nil
+		}
+		return nil, p.errorf("result of %s() must be compared", operand.name)
+	}
+	return nil, p.errorf("expected a comparison")
+}
+
+// filterNumberLiteral matches a JSON number.
+var filterNumberLiteral = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][-+]?[0-9]+)?`)
+
+func (p *jsonPathParser) parseOperand() (filterOperand, error) {
+	p.skipSpace()
+	switch c := p.peek(); {
+	case c == '@' || c == '$':
+		p.pos++
+		segments, err := p.parseSegments()
+		return &filterQuery{absolute: c == '$', segments: segments}, err
+	case c == '\'' || c == '"':
+		s, err := p.parseString()
+		return literalOperand{v: s}, err
+	case c == '-' || (c >= '0' && c <= '9'):
+		number := filterNumberLiteral.FindString(p.src[p.pos:])
+		if number == "" {
+			return nil, p.errorf("invalid number")
+		}
+		p.pos += len(number)
+		if i, err := strconv.Atoi(number); err == nil {
+			return literalOperand{v: i}, nil
+		}
+		f, err := strconv.ParseFloat(number, 64)
+		if err != nil {
+			return nil, p.errorf("invalid number %q", number)
+		}
+		return literalOperand{v: f}, nil
+	}
+
+	start := p.pos
+	name := p.parseName()
+	switch name {
+	case "true":
+		return literalOperand{v: true}, nil
+	case "false":
+		return literalOperand{v: false}, nil
+	case "null":
+		return literalOperand{v: nil}, nil
+	case "":
+		return nil, p.errorf("expected a value")
+	}
+	arity, ok := jsonPathFunctions[name]
+	if !ok || !p.consume("(") {
+		p.pos = start
+		return nil, p.errorf("unknown function or value %q", name)
+	}
+	return p.parseFunctionCall(name, arity)
+}
+
+func (p *jsonPathParser) parseFunctionCall(name string, arity int) (filterOperand, error) {
+	call := &functionCall{name: name}
+	for {
+		p.skipSpace()
+		arg, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		call.args = append(call.args, arg)
+		p.skipSpace()
+		if p.consume(")") {
+			break
+		}
+		if !p.consume(",") {
+			return nil, p.errorf("expected , or )")
+		}
+	}
+	if len(call.args) != arity {
+		return nil, p.errorf("%s() takes %d argument(s), got %d", name, arity, len(call.args))
+	}
+	if _, ok := call.args[0].(*filterQuery); name == "count" && !ok {
+		return nil, p.errorf("count() takes a query")
+	}
+	if name == "match" || name == "search" {
+		if literal, ok := call.args[1].(literalOperand); ok {
+			pattern, _ := literal.v.(string)
+			re, err := compileFunctionPattern(name, pattern)
+			if err != nil {
+				return nil, p.errorf("invalid pattern of %s(): %v", name, err)
+			}
+			call.pattern = re
+		}
+	}
+	return call, nil
+}
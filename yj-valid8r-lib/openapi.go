@@ -0,0 +1,184 @@
+package yjvalid8r_lib
+
+import (
+	"path"
+	"sort"
+	"strings"
+)
+
+// openAPIVersion returns the "openapi" version of an OpenAPI 3 document, or false for plain schemas.
+func openAPIVersion(document interface{}) (string, bool) {
+	documentMap, _ := document.(map[string]interface{})
+	version, ok := documentMap["openapi"].(string)
+	return version, ok && strings.HasPrefix(version, "3.")
+}
+
+// openAPIDraft returns the JSON Schema draft used by the schemas of an OpenAPI document:
+// 3.1 documents use 2020-12 (or their jsonSchemaDialect), 3.0 schemas are a draft-04/05 superset.
+func openAPIDraft(document interface{}) SchemaDraft {
+	version, _ := openAPIVersion(document)
+	if !strings.HasPrefix(version, "3.1") {
+		return SchemaDraftAuto
+	}
+	documentMap := document.(map[string]interface{})
+	if dialect, ok := documentMap["jsonSchemaDialect"].(string); ok {
+		return detectSchemaDraft(map[string]interface{}{"$schema": dialect})
+	}
+	return SchemaDraft2020
+}
+
+// translateOpenAPI rewrites the OpenAPI-specific schema keywords of an OpenAPI 3 document into plain
+// JSON Schema, so its components can be validated against. Other documents are returned unchanged.
+//   - nullable: true adds "null" to the type (and enum) of the schema.
+//   - discriminator on a oneOf/anyOf selects the alternative by the value of discriminator.propertyName.
+func translateOpenAPI(document interface{}) interface{} {
+	if _, ok := openAPIVersion(document); !ok {
+		return document
+	}
+	translateOpenAPISchemas(document)
+	return document
+}
+
+// translateOpenAPISchemas walks a decoded document depth-first and translates every schema object in place.
+func translateOpenAPISchemas(node interface{}) {
+	switch v := node.(type) {
+	case []interface{}:
+		for _, item := range v {
+			translateOpenAPISchemas(item)
+		}
+	case map[string]interface{}:
+		for key, value := range v {
+			switch key {
+			case "const", "enum", "examples", "example", "default":
+				continue // literal values, not schemas
+			case "properties", "patternProperties", "definitions", "$defs", "schemas":
+				// names mapped to schemas; a property called "default" is still a schema
+				if named, ok := value.(map[string]interface{}); ok {
+					for _, schema := range named {
+						translateOpenAPISchemas(schema)
+					}
+					continue
+				}
+			}
+			translateOpenAPISchemas(value)
+		}
+		translateNullable(v)
+		translateDiscriminator(v)
+	}
+}
+
+// translateNullable replaces `nullable: true` by a "null" type.
+func translateNullable(schema map[string]interface{}) {
+	nullable, ok := schema["nullable"].(bool)
+	if !ok {
+		return
+	}
+	delete(schema, "nullable")
+	if !nullable {
+		return
+	}
+
+	switch schemaType := schema["type"].(type) {
+	case string:
+		schema["type"] = []interface{}{schemaType, "null"}
+	case []interface{}:
+		if !containsValue(schemaType, "null") {
+			schema["type"] = append(schemaType, "null")
+		}
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok && !containsValue(enum, nil) {
+		schema["enum"] = append(enum, nil)
+	}
+}
+
+// translateDiscriminator replaces a discriminated oneOf/anyOf by one if/then branch per discriminator value,
+// so data is validated against (and errors reported for) the selected alternative only.
+func translateDiscriminator(schema map[string]interface{}) {
+	discriminator, ok := schema["discriminator"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	propertyName, ok := discriminator["propertyName"].(string)
+	if !ok {
+		return
+	}
+	delete(schema, "discriminator")
+
+	choicesKey := "oneOf"
+	choices, ok := schema[choicesKey].([]interface{})
+	if !ok {
+		choicesKey = "anyOf"
+		if choices, ok = schema[choicesKey].([]interface{}); !ok {
+			return // used for inheritance through allOf; nothing to select
+		}
+	}
+
+	// Explicit mapping first, then the implicit mapping by schema name for alternatives not mapped.
+	refsByValue := make(map[string]string)
+	mappedRefs := make(map[string]bool)
+	if mapping, ok := discriminator["mapping"].(map[string]interface{}); ok {
+		for value, ref := range mapping {
+			if refString, ok := ref.(string); ok {
+				refsByValue[value] = refString
+				mappedRefs[refString] = true
+			}
+		}
+	}
+	for _, choice := range choices {
+		choiceMap, _ := choice.(map[string]interface{})
+		ref, ok := choiceMap["$ref"].(string)
+		if !ok {
+			return // inline alternatives have no name to discriminate by; keep the oneOf/anyOf
+		}
+		if !mappedRefs[ref] {
+			refsByValue[discriminatorValue(ref)] = ref
+		}
+	}
+
+	values := make([]string, 0, len(refsByValue))
+	for value := range refsByValue {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+
+	allowed := make([]interface{}, len(values))
+	branches := []interface{}{
+		map[string]interface{}{
+			"required":   []interface{}{propertyName},
+			"properties": map[string]interface{}{propertyName: map[string]interface{}{"enum": allowed}},
+		},
+	}
+	for i, value := range values {
+		allowed[i] = value
+		branches = append(branches, map[string]interface{}{
+			"if": map[string]interface{}{
+				"required":   []interface{}{propertyName},
+				"properties": map[string]interface{}{propertyName: map[string]interface{}{"enum": []interface{}{value}}},
+			},
+			"then": map[string]interface{}{"$ref": refsByValue[value]},
+		})
+	}
+
+	delete(schema, choicesKey)
+	allOf, _ := schema["allOf"].([]interface{})
+	schema["allOf"] = append(allOf, branches...)
+}
+
+// discriminatorValue returns the implicit discriminator value of a $ref: the name of the referenced schema,
+// e.g. "Cat" for "#/components/schemas/Cat" or "cat.yaml".
+func discriminatorValue(ref string) string {
+	if _, fragment, found := strings.Cut(ref, "#"); found && fragment != "" {
+		return path.Base(fragment)
+	}
+	name := path.Base(ref)
+	return strings.TrimSuffix(name, path.Ext(name))
+}
+
+func containsValue(values []interface{}, want interface{}) bool {
+	for _, value := range values {
+		if value == want {
+			return true
+		}
+	}
+	return false
+}
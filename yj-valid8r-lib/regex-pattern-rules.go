@@ -5,6 +5,7 @@ import (
 	"os"
 	"regexp"
 	"strings"
+	"unicode/utf8"
 )
 
 // RegexPatternRulesFinder finds and validates data using regex rules in the data.
@@ -26,32 +27,37 @@ func RegexPatternRulesFinder(data []RegexPatternRules, dataByte []byte) ([]Regex
 		re, err := regexp.Compile(rule.Regex)
 		if err != nil {
 			output.Errors = append(output.Errors, fmt.Sprintf("Invalid regex: %v", err))
+			output.Findings = append(output.Findings, Finding{Severity: MessageTypeError, RuleID: RuleRegexInvalid, Message: fmt.Sprintf("Invalid regex: %v", err)})
 		} else {
 			for lineNum, line := range lines {
-				matches := re.FindAllStringSubmatch(line, -1)
+				matches := re.FindAllStringSubmatchIndex(line, -1)
 
-				for _, match := range matches {
-					fullMatch := match[0]
+				for _, loc := range matches {
+					fullMatch := line[loc[0]:loc[1]]
+					column := utf8.RuneCountInString(line[:loc[0]]) + 1
+					endColumn := column + utf8.RuneCountInString(fullMatch)
 
-					if len(match) > 1 {
-						varName := match[1]
-						fullVarName := match[0]
-						output.Data = append(output.Data, fullVarName)
-						output.Messages = append(output.Messages, fmt.Sprintf("%s found on line %d", fullVarName, lineNum+1))
+					output.Data = append(output.Data, fullMatch)
+					output.Messages = append(output.Messages, fmt.Sprintf("%s found on line %d", fullMatch, lineNum+1))
+					output.Findings = append(output.Findings, lineFinding(MessageTypeInfo, RuleRegexMatch, fmt.Sprintf("%s found", fullMatch), lineNum+1, column, endColumn))
 
-						if rule.CheckEnv != nil && rule.CheckEnv.Enabled {
-							if val, ok := os.LookupEnv(varName); ok {
-								output.EnvValues = append(output.EnvValues, fmt.Sprintf("%s=%s", varName, val))
-							} else {
-								output.Errors = append(output.Errors, fmt.Sprintf("Environment variable not found: %s", varName))
-								if rule.CheckEnv.Strict {
-									hasErrorStrictMode = true
-								}
+					// The first capture group, if any, names the environment variable
+					if len(loc) > 2 && rule.CheckEnv != nil && rule.CheckEnv.Enabled {
+						var varName string
+						if loc[2] >= 0 {
+							varName = line[loc[2]:loc[3]]
+						}
+						if val, ok := os.LookupEnv(varName); ok {
+							output.EnvValues = append(output.EnvValues, fmt.Sprintf("%s=%s", varName, val))
+						} else {
+							output.Errors = append(output.Errors, fmt.Sprintf("Environment variable not found: %s", varName))
+							severity := MessageTypeWarning
+							if rule.CheckEnv.Strict {
+								hasErrorStrictMode = true
+								severity = MessageTypeError
 							}
+							output.Findings = append(output.Findings, lineFinding(severity, RuleRegexEnvMissing, fmt.Sprintf("Environment variable not found: %s", varName), lineNum+1, column, endColumn))
 						}
-					} else {
-						output.Data = append(output.Data, fullMatch)
-						output.Messages = append(output.Messages, fmt.Sprintf("%s found on line %d", fullMatch, lineNum+1))
 					}
 				}
 			}
@@ -0,0 +1,226 @@
+package yjvalid8r_lib
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// schemaHTTPClient downloads remote schemas; the timeout keeps an unresponsive schema host from blocking validation.
+var schemaHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// SchemaCache stores remote schemas on disk so that repeated runs (and air-gapped CI)
+// do not need the network. Local schema files are always read directly.
+// A nil *SchemaCache is valid and fetches every schema without caching.
+type SchemaCache struct {
+	dir     string
+	ttl     time.Duration
+	offline bool
+	pins    map[string]string
+	client  *http.Client
+}
+
+// NewSchemaCache creates a SchemaCache from its configuration, creating the cache directory if needed.
+func NewSchemaCache(cfg SchemaCacheConfig) (*SchemaCache, error) {
+	dir := cfg.Dir
+	if dir == "" {
+		userCacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return nil, fmt.Errorf("resolve default schema cache dir: %w", err)
+		}
+		dir = filepath.Join(userCacheDir, "yj-valid8r", "schemas")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create schema cache dir: %w", err)
+	}
+
+	var ttl time.Duration
+	if cfg.TTL != "" {
+		var err error
+		ttl, err = time.ParseDuration(cfg.TTL)
+		if err != nil {
+			return nil, fmt.Errorf("parse schema cache ttl: %w", err)
+		}
+	}
+
+	pins := make(map[string]string, len(cfg.Pins))
+	for location, digest := range cfg.Pins {
+		pins[location] = strings.ToLower(strings.TrimPrefix(digest, "sha256:"))
+	}
+
+	return &SchemaCache{
+		dir:     dir,
+		ttl:     ttl,
+		offline: cfg.Offline,
+		pins:    pins,
+		client:  schemaHTTPClient,
+	}, nil
+}
+
+// Fetch returns the content of a schema given as a local path, a file:// URL or a remote URL.
+// Remote schemas are served from the cache while fresh; in offline mode the network is never used.
+// When a sha256 pin is configured for the location, the content must match it.
+func (c *SchemaCache) Fetch(location string) ([]byte, error) {
+	if localPath, ok := schemaLocalPath(location); ok {
+		data, err := os.ReadFile(localPath)
+		if err != nil {
+			return nil, fmt.Errorf("read schema file: %w", err)
+		}
+		return data, c.verifyPin(location, data)
+	}
+
+	if c == nil {
+		return downloadSchema(schemaHTTPClient, location)
+	}
+
+	cachePath := c.cachePath(location)
+	cached, modTime, cacheErr := readCachedSchema(cachePath)
+	if cacheErr == nil && c.verifyPin(location, cached) == nil {
+		if c.offline || c.ttl == 0 || time.Since(modTime) < c.ttl {
+			return cached, nil
+		}
+	}
+
+	if c.offline {
+		if cacheErr != nil {
+			return nil, fmt.Errorf("offline mode: schema not in cache: %s", location)
+		}
+		return nil, c.verifyPin(location, cached)
+	}
+
+	data, err := downloadSchema(c.client, location)
+	if err != nil {
+		// Serve a stale (but still pinned) copy when the network is unavailable.
+		if cacheErr == nil && c.verifyPin(location, cached) == nil {
+			return cached, nil
+		}
+		return nil, err
+	}
+	if err := c.verifyPin(location, data); err != nil {
+		return nil, err
+	}
+	if err := writeCachedSchema(cachePath, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// verifyPin checks the sha256 digest of data against the pin configured for location, if any.
+func (c *SchemaCache) verifyPin(location string, data []byte) error {
+	if c == nil {
+		return nil
+	}
+	want, ok := c.pins[location]
+	if !ok {
+		return nil
+	}
+	sum := sha256.Sum256(data)
+	if got := hex.EncodeToString(sum[:]); got != want {
+		return fmt.Errorf("schema digest mismatch for %s: got sha256:%s, want sha256:%s", location, got, want)
+	}
+	return nil
+}
+
+// cachePath returns the cache file used for a remote schema URL.
+func (c *SchemaCache) cachePath(location string) string {
+	sum := sha256.Sum256([]byte(location))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".schema")
+}
+
+func readCachedSchema(path string) ([]byte, time.Time, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return data, info.ModTime(), nil
+}
+
+// writeCachedSchema writes through a temporary file so concurrent readers never see partial content.
+func writeCachedSchema(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".schema-*")
+	if err != nil {
+		return fmt.Errorf("write schema cache: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write schema cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write schema cache: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("write schema cache: %w", err)
+	}
+	return nil
+}
+
+func downloadSchema(client *http.Client, location string) ([]byte, error) {
+	resp, err := client.Get(location)
+	if err != nil {
+		return nil, fmt.Errorf("fetch schema: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch schema: %s: %s", location, resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read schema response: %w", err)
+	}
+	return data, nil
+}
+
+// normalizeSchemaLocation turns local paths into absolute file:// URLs and leaves remote URLs untouched.
+// A fragment (e.g. "#/components/schemas/Service") is kept.
+func normalizeSchemaLocation(pathOrURL string) string {
+	location, fragment := splitFragment(pathOrURL)
+	if localPath, ok := schemaLocalPath(location); ok {
+		if absPath, err := filepath.Abs(localPath); err == nil {
+			localPath = absPath
+		}
+		location = (&url.URL{Scheme: "file", Path: filepath.ToSlash(localPath)}).String()
+	}
+	return withFragment(location, fragment)
+}
+
+// schemaLocalPath returns the file system path of a local path or file:// URL.
+func schemaLocalPath(pathOrURL string) (string, bool) {
+	parsedURL, err := url.Parse(pathOrURL)
+	// If not a valid URL or it's a file URL or missing scheme, treat as local file
+	if err != nil || parsedURL.Scheme == "" || parsedURL.Scheme == "file" {
+		var normalizedPath string
+		if parsedURL != nil && parsedURL.Scheme == "file" {
+			// For file URLs, use .Path (which is always slash-separated)
+			// On Windows, remove leading slash if present (e.g., /C:/path)
+			normalizedPath = parsedURL.Path
+			if runtime.GOOS == "windows" && strings.HasPrefix(normalizedPath, "/") && len(normalizedPath) > 2 && normalizedPath[2] == ':' {
+				normalizedPath = normalizedPath[1:]
+			}
+		} else {
+			// Just a path, not a file URL
+			normalizedPath = pathOrURL
+		}
+		// Convert slashes for Windows if needed
+		if runtime.GOOS == "windows" {
+			normalizedPath = filepath.FromSlash(normalizedPath)
+		}
+		return normalizedPath, true
+	}
+	return "", false
+}
@@ -0,0 +1,294 @@
+package yjvalid8r_lib
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/kind"
+	"github.com/xeipuuv/gojsonschema"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// schemaEngine validates JSON data against a compiled schema.
+// Drafts 4, 6 and 7 are served by gojsonschema, 2019-09 and 2020-12 by santhosh-tekuri/jsonschema.
+type schemaEngine interface {
+	validate(jsonData []byte) ([]schemaViolation, error)
+}
+
+// schemaViolation is a single failed constraint, located by the path of the offending value.
+type schemaViolation struct {
+	Path        []string // Path segments of the value; empty for the root. Segments may contain dots.
+	Field       string   // Dotted form of Path for messages, "(root)" for the root.
+	Description string
+	Keyword     string // Failed keyword or engine error type, e.g. "required"; used in the finding rule ID.
+	Missing     bool   // The failure is about a property missing from the value (required).
+	Property    string // Property of the value that is not allowed (additionalProperties), if any.
+}
+
+// violationPathSeparator splits gojsonschema contexts without breaking keys that contain dots.
+const violationPathSeparator = "\x00"
+
+// metaSchemaURLs lists the "$schema" identifiers of every supported draft.
+var metaSchemaURLs = map[SchemaDraft]string{
+	SchemaDraft4:    "http://json-schema.org/draft-04/schema#",
+	SchemaDraft6:    "http://json-schema.org/draft-06/schema#",
+	SchemaDraft7:    "http://json-schema.org/draft-07/schema#",
+	SchemaDraft2019: "https://json-schema.org/draft/2019-09/schema",
+	SchemaDraft2020: "https://json-schema.org/draft/2020-12/schema",
+}
+
+// ParseSchemaDraft parses a draft name as used in configuration: "auto" (or empty),
+// "draft-04", "draft-06", "draft-07", "2019-09" or "2020-12".
+func ParseSchemaDraft(name string) (SchemaDraft, error) {
+	if name == "" || name == "auto" {
+		return SchemaDraftAuto, nil
+	}
+	draft := SchemaDraft(name)
+	if _, ok := metaSchemaURLs[draft]; !ok {
+		return SchemaDraftAuto, fmt.Errorf("unsupported schema draft %q", name)
+	}
+	return draft, nil
+}
+
+// detectSchemaDraft returns the draft declared by the "$schema" keyword of a schema document (or implied
+// by an OpenAPI 3.1 document), or SchemaDraftAuto if it declares none or an unknown one.
+func detectSchemaDraft(document interface{}) SchemaDraft {
+	schemaMap, _ := document.(map[string]interface{})
+	declared, _ := schemaMap["$schema"].(string)
+	declared = canonicalMetaSchemaURL(declared)
+
+	for draft, metaURL := range metaSchemaURLs {
+		if declared == canonicalMetaSchemaURL(metaURL) {
+			return draft
+		}
+	}
+	return openAPIDraft(document)
+}
+
+// canonicalMetaSchemaURL strips the scheme and trailing "#" or "/" so equivalent spellings of a meta-schema URL compare equal.
+func canonicalMetaSchemaURL(metaURL string) string {
+	metaURL = strings.TrimPrefix(strings.TrimPrefix(metaURL, "https://"), "http://")
+	return strings.TrimRight(metaURL, "#/")
+}
+
+// usesDraft2020Engine reports whether a draft needs the 2019-09/2020-12 capable engine.
+func usesDraft2020Engine(draft SchemaDraft) bool {
+	return draft == SchemaDraft2019 || draft == SchemaDraft2020
+}
+
+// compileSchemaEngine compiles the root schema document and its prefetched references with the engine
+// matching the forced draft, or the draft declared by the root schema. A non-empty fragment selects
+// the sub-schema to validate against, e.g. "/components/schemas/Service".
+func compileSchemaEngine(location, fragment string, document interface{}, refDocs map[string]interface{}, fetcher *schemaFetcher, forced SchemaDraft) (schemaEngine, SchemaDraft, error) {
+	draft := forced
+	if draft == SchemaDraftAuto {
+		draft = detectSchemaDraft(document)
+	}
+
+	if usesDraft2020Engine(draft) {
+		engine, err := compileDraft2020Engine(location, fragment, document, refDocs, fetcher, forced)
+		return engine, draft, err
+	}
+	engine, err := compileGoJSONSchemaEngine(location, fragment, document, refDocs, fetcher, forced)
+	return engine, draft, err
+}
+
+// goJSONSchemaEngine validates drafts 4, 6 and 7.
+type goJSONSchemaEngine struct {
+	schema *gojsonschema.Schema
+}
+
+func compileGoJSONSchemaEngine(location, fragment string, document interface{}, refDocs map[string]interface{}, fetcher *schemaFetcher, forced SchemaDraft) (*goJSONSchemaEngine, error) {
+	schemaLoader := gojsonschema.NewSchemaLoader()
+	switch forced {
+	case SchemaDraft4:
+		schemaLoader.Draft, schemaLoader.AutoDetect = gojsonschema.Draft4, false
+	case SchemaDraft6:
+		schemaLoader.Draft, schemaLoader.AutoDetect = gojsonschema.Draft6, false
+	case SchemaDraft7:
+		schemaLoader.Draft, schemaLoader.AutoDetect = gojsonschema.Draft7, false
+	}
+
+	// All documents are added to the pool already fetched, so gojsonschema never fetches them again.
+	for _, refURL := range sortedKeys(refDocs) {
+		if err := schemaLoader.AddSchema(refURL, &cachedJSONLoader{source: refURL, fetcher: fetcher, document: refDocs[refURL]}); err != nil {
+			return nil, fmt.Errorf("schema load failed: %s: %w", refURL, err)
+		}
+	}
+
+	loader := &cachedJSONLoader{source: location, fetcher: fetcher, document: document}
+	if err := schemaLoader.AddSchema(location, loader); err != nil {
+		return nil, fmt.Errorf("schema load failed: %w", err)
+	}
+	root := loader
+	if fragment != "" {
+		// resolved from the pooled document, like any other $ref into it
+		root = &cachedJSONLoader{source: withFragment(location, fragment), fetcher: fetcher}
+	}
+	schema, err := schemaLoader.Compile(root)
+	if err != nil {
+		return nil, fmt.Errorf("validation error: %w", err)
+	}
+	return &goJSONSchemaEngine{schema: schema}, nil
+}
+
+func (e *goJSONSchemaEngine) validate(jsonData []byte) ([]schemaViolation, error) {
+	result, err := e.schema.Validate(gojsonschema.NewBytesLoader(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("validation error: %w", err)
+	}
+
+	var violations []schemaViolation
+	for _, desc := range result.Errors() {
+		violation := schemaViolation{Field: desc.Field(), Description: desc.Description(), Keyword: desc.Type()}
+		if desc.Context() != nil {
+			// "(root)", then one segment per level
+			violation.Path = strings.Split(desc.Context().String(violationPathSeparator), violationPathSeparator)[1:]
+		}
+		switch desc.Type() {
+		case "required":
+			violation.Missing = true
+		case "additional_property_not_allowed":
+			violation.Property, _ = desc.Details()["property"].(string)
+		}
+		violations = append(violations, violation)
+	}
+	return violations, nil
+}
+
+// draft2020Engine validates drafts 2019-09 and 2020-12.
+type draft2020Engine struct {
+	schema *jsonschema.Schema
+}
+
+// draft2020Printer renders error messages of the 2019-09/2020-12 engine.
+var draft2020Printer = message.NewPrinter(language.English)
+
+func compileDraft2020Engine(location, fragment string, document interface{}, refDocs map[string]interface{}, fetcher *schemaFetcher, forced SchemaDraft) (*draft2020Engine, error) {
+	compiler := jsonschema.NewCompiler()
+	compiler.DefaultDraft(jsonschema.Draft2020)
+	compiler.AssertFormat() // like gojsonschema, reject values not matching "format"
+	compiler.UseLoader(draft2020Loader{fetcher: fetcher})
+
+	if forced != SchemaDraftAuto {
+		document = withMetaSchema(document, metaSchemaURLs[forced])
+	}
+
+	for _, refURL := range sortedKeys(refDocs) {
+		if err := compiler.AddResource(refURL, refDocs[refURL]); err != nil {
+			return nil, fmt.Errorf("schema load failed: %s: %w", refURL, err)
+		}
+	}
+	if err := compiler.AddResource(location, document); err != nil {
+		return nil, fmt.Errorf("schema load failed: %w", err)
+	}
+
+	schema, err := compiler.Compile(withFragment(location, fragment))
+	if err != nil {
+		return nil, fmt.Errorf("validation error: %w", err)
+	}
+	return &draft2020Engine{schema: schema}, nil
+}
+
+func (e *draft2020Engine) validate(jsonData []byte) ([]schemaViolation, error) {
+	instance, err := jsonschema.UnmarshalJSON(bytes.NewReader(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("validation error: %w", err)
+	}
+
+	err = e.schema.Validate(instance)
+	if err == nil {
+		return nil, nil
+	}
+	validationErr, ok := err.(*jsonschema.ValidationError)
+	if !ok {
+		return nil, fmt.Errorf("validation error: %w", err)
+	}
+
+	var violations []schemaViolation
+	collectDraft2020Violations(validationErr, &violations)
+	return violations, nil
+}
+
+// collectDraft2020Violations flattens the error tree into its leaves, the constraints that actually failed.
+func collectDraft2020Violations(validationErr *jsonschema.ValidationError, violations *[]schemaViolation) {
+	if len(validationErr.Causes) > 0 {
+		for _, cause := range validationErr.Causes {
+			collectDraft2020Violations(cause, violations)
+		}
+		return
+	}
+	if _, ok := validationErr.ErrorKind.(*kind.Group); ok {
+		return
+	}
+
+	violation := schemaViolation{Path: validationErr.InstanceLocation, Field: "(root)"}
+	if len(validationErr.InstanceLocation) > 0 {
+		violation.Field = strings.Join(validationErr.InstanceLocation, ".")
+	}
+	description := validationErr.ErrorKind.LocalizedString(draft2020Printer)
+	keyword := "false"
+	if keywordPath := validationErr.ErrorKind.KeywordPath(); len(keywordPath) > 0 {
+		keyword = keywordPath[len(keywordPath)-1]
+	}
+	switch errorKind := validationErr.ErrorKind.(type) {
+	case *kind.FalseSchema:
+		// unevaluatedProperties/items: false and friends report the offending value itself
+		description = "value is not allowed here"
+	case *kind.Required:
+		violation.Missing = true
+	case *kind.AdditionalProperties:
+		// one violation per property, like gojsonschema, each reported at its own key;
+		// the engine lists them in map order
+		violation.Keyword = keyword
+		properties := append([]string(nil), errorKind.Properties...)
+		sort.Strings(properties)
+		for _, property := range properties {
+			violation.Property = property
+			violation.Description = (&kind.AdditionalProperties{Properties: []string{property}}).LocalizedString(draft2020Printer)
+			*violations = append(*violations, violation)
+		}
+		if len(properties) > 0 {
+			return
+		}
+	}
+	violation.Description, violation.Keyword = description, keyword
+	*violations = append(*violations, violation)
+}
+
+// draft2020Loader loads schemas the compiler was not given up front, e.g. custom meta-schemas.
+type draft2020Loader struct {
+	fetcher *schemaFetcher
+}
+
+func (l draft2020Loader) Load(location string) (any, error) {
+	return l.fetcher.load(location)
+}
+
+// withMetaSchema returns a shallow copy of a schema object with "$schema" replaced.
+func withMetaSchema(document interface{}, metaSchemaURL string) interface{} {
+	schemaMap, ok := document.(map[string]interface{})
+	if !ok {
+		return document
+	}
+	copied := make(map[string]interface{}, len(schemaMap)+1)
+	for key, value := range schemaMap {
+		copied[key] = value
+	}
+	copied["$schema"] = metaSchemaURL
+	return copied
+}
+
+// sortedKeys returns the keys of a schema document map in a deterministic order.
+func sortedKeys(docs map[string]interface{}) []string {
+	keys := make([]string, 0, len(docs))
+	for key := range docs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
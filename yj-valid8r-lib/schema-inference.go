@@ -0,0 +1,211 @@
+package yjvalid8r_lib
+
+import (
+	"fmt"
+	"sort"
+)
+
+// DefaultMaxEnumValues is the number of distinct values up to which sampled strings are inferred as an enum.
+const DefaultMaxEnumValues = 5
+
+// JSON Schema type names, in the order they are listed in inferred schemas.
+var inferredTypeOrder = []string{"object", "array", "string", "integer", "number", "boolean", "null"}
+
+// SchemaInferrer builds a JSON schema that accepts every sample added to it:
+// the types seen at each location, the object keys present in every sample as "required",
+// the item types of arrays, and enums for strings that take few distinct values.
+type SchemaInferrer struct {
+	opts SchemaInferenceOptions
+	root *inferredValue
+}
+
+// inferredValue accumulates every value seen at one location of the samples.
+type inferredValue struct {
+	types      map[string]bool
+	objects    int                       // number of objects seen
+	properties map[string]*inferredValue // values of object keys
+	keyCounts  map[string]int            // number of objects each key was present in
+	items      *inferredValue            // values of array items
+	strings    int                       // number of strings seen
+	values     map[string]bool           // distinct strings, up to MaxEnumValues+1
+}
+
+// NewSchemaInferrer creates a SchemaInferrer; the draft must be draft-07 or 2020-12.
+func NewSchemaInferrer(opts SchemaInferenceOptions) (*SchemaInferrer, error) {
+	if opts.Draft == SchemaDraftAuto {
+		opts.Draft = SchemaDraft7
+	}
+	if opts.Draft != SchemaDraft7 && opts.Draft != SchemaDraft2020 {
+		return nil, fmt.Errorf("schema inference supports draft-07 and 2020-12, not %q", opts.Draft)
+	}
+	if opts.MaxEnumValues == 0 {
+		opts.MaxEnumValues = DefaultMaxEnumValues
+	}
+	return &SchemaInferrer{opts: opts}, nil
+}
+
+// Add adds the YAML or JSON data of a sample file; every document of a multi-document stream is a sample.
+func (i *SchemaInferrer) Add(dataBytes []byte) error {
+	docs, err := DecodeDocuments(dataBytes)
+	if err != nil {
+		return fmt.Errorf("parse yaml/json into node: %w", err)
+	}
+	for _, doc := range docs {
+		var data interface{}
+		if err := doc.Node.Decode(&data); err != nil {
+			return fmt.Errorf("parse yaml/json into value: document %d: %w", doc.Index, err)
+		}
+		if i.root == nil {
+			i.root = &inferredValue{}
+		}
+		i.root.add(jsonCompatible(data), i.opts.MaxEnumValues)
+	}
+	return nil
+}
+
+// Schema returns the schema inferred from the samples added so far, ready to be marshaled as JSON or YAML.
+// Without samples it is an empty schema accepting anything.
+func (i *SchemaInferrer) Schema() map[string]interface{} {
+	schema := map[string]interface{}{}
+	if i.root != nil {
+		schema = i.root.schema(i.opts.MaxEnumValues)
+	}
+	schema["$schema"] = metaSchemaURLs[i.opts.Draft]
+	return schema
+}
+
+// InferSchema infers a JSON schema from the YAML or JSON data of one or more sample files.
+func InferSchema(samples [][]byte, opts SchemaInferenceOptions) (map[string]interface{}, error) {
+	inferrer, err := NewSchemaInferrer(opts)
+	if err != nil {
+		return nil, err
+	}
+	for index, sample := range samples {
+		if err := inferrer.Add(sample); err != nil {
+			return nil, fmt.Errorf("sample %d: %w", index, err)
+		}
+	}
+	return inferrer.Schema(), nil
+}
+
+func (v *inferredValue) add(value interface{}, maxEnumValues int) {
+	if v.types == nil {
+		v.types = make(map[string]bool)
+	}
+
+	switch val := value.(type) {
+	case map[string]interface{}:
+		v.types["object"] = true
+		v.objects++
+		if v.properties == nil {
+			v.properties = make(map[string]*inferredValue)
+			v.keyCounts = make(map[string]int)
+		}
+		for key, item := range val {
+			if v.properties[key] == nil {
+				v.properties[key] = &inferredValue{}
+			}
+			v.properties[key].add(item, maxEnumValues)
+			v.keyCounts[key]++
+		}
+	case []interface{}:
+		v.types["array"] = true
+		for _, item := range val {
+			if v.items == nil {
+				v.items = &inferredValue{}
+			}
+			v.items.add(item, maxEnumValues)
+		}
+	case string:
+		v.types["string"] = true
+		v.strings++
+		if v.values == nil {
+			v.values = make(map[string]bool)
+		}
+		if len(v.values) <= maxEnumValues {
+			v.values[val] = true
+		}
+	case bool:
+		v.types["boolean"] = true
+	case nil:
+		v.types["null"] = true
+	case int, int64, uint64:
+		v.types["integer"] = true
+	default:
+		v.types["number"] = true
+	}
+}
+
+func (v *inferredValue) schema(maxEnumValues int) map[string]interface{} {
+	schema := map[string]interface{}{}
+	if v.types["number"] {
+		delete(v.types, "integer") // every integer is a number
+	}
+
+	var types []interface{}
+	for _, name := range inferredTypeOrder {
+		if v.types[name] {
+			types = append(types, name)
+		}
+	}
+	if len(types) == 1 {
+		schema["type"] = types[0]
+	} else if len(types) > 1 {
+		schema["type"] = types
+	}
+
+	if v.types["object"] {
+		properties := make(map[string]interface{}, len(v.properties))
+		var required []string
+		for key, property := range v.properties {
+			properties[key] = property.schema(maxEnumValues)
+			if v.keyCounts[key] == v.objects {
+				required = append(required, key)
+			}
+		}
+		schema["properties"] = properties
+		if len(required) > 0 {
+			sort.Strings(required)
+			schema["required"] = required
+		}
+	}
+
+	if v.items != nil {
+		schema["items"] = v.items.schema(maxEnumValues)
+	}
+
+	if enum := v.enum(maxEnumValues); enum != nil {
+		schema["enum"] = enum
+	}
+
+	return schema
+}
+
+// enum returns the allowed values of a string location with few distinct values, at least one of
+// them repeated, so that values seen once (names, descriptions) are not mistaken for an enum.
+// A location that is also null now and then keeps null allowed.
+func (v *inferredValue) enum(maxEnumValues int) []interface{} {
+	if maxEnumValues < 0 || !v.types["string"] || len(v.values) > maxEnumValues || v.strings <= len(v.values) {
+		return nil
+	}
+	for name := range v.types {
+		if name != "string" && name != "null" {
+			return nil
+		}
+	}
+
+	values := make([]string, 0, len(v.values))
+	for value := range v.values {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+
+	enum := make([]interface{}, 0, len(values)+1)
+	for _, value := range values {
+		enum = append(enum, value)
+	}
+	if v.types["null"] {
+		enum = append(enum, nil)
+	}
+	return enum
+}
@@ -0,0 +1,158 @@
+package yjvalid8r_lib
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/xeipuuv/gojsonreference"
+	"github.com/xeipuuv/gojsonschema"
+	"gopkg.in/yaml.v3"
+)
+
+// cachedJSONLoader is a gojsonschema.JSONLoader that reads a schema, and every document it $refs,
+// through a SchemaResolver and SchemaCache instead of going to the network directly.
+type cachedJSONLoader struct {
+	source   string
+	fetcher  *schemaFetcher
+	document interface{} // Already decoded content of source, if it was fetched beforehand.
+}
+
+// cachedJSONLoaderFactory creates cachedJSONLoaders for the references gojsonschema resolves.
+type cachedJSONLoaderFactory struct {
+	fetcher *schemaFetcher
+}
+
+func (f cachedJSONLoaderFactory) New(source string) gojsonschema.JSONLoader {
+	return &cachedJSONLoader{source: source, fetcher: f.fetcher}
+}
+
+func (l *cachedJSONLoader) JsonSource() interface{} {
+	return l.source
+}
+
+func (l *cachedJSONLoader) JsonReference() (gojsonreference.JsonReference, error) {
+	return gojsonreference.NewJsonReference(l.source)
+}
+
+func (l *cachedJSONLoader) LoaderFactory() gojsonschema.JSONLoaderFactory {
+	return cachedJSONLoaderFactory{fetcher: l.fetcher}
+}
+
+func (l *cachedJSONLoader) LoadJSON() (interface{}, error) {
+	if l.document != nil {
+		return l.document, nil
+	}
+
+	reference, err := gojsonreference.NewJsonReference(l.source)
+	if err != nil {
+		return nil, err
+	}
+	refToURL := reference
+	refToURL.GetUrl().Fragment = ""
+
+	return l.fetcher.load(refToURL.String())
+}
+
+// decodeSchema decodes a schema written in JSON or YAML, as detected by DetectDataType, into JSON values.
+// Malformed schemas are reported with the line and column of the problem.
+func decodeSchema(schemaBytes []byte) (interface{}, error) {
+	switch DetectDataType(schemaBytes) {
+	case DataTypeJSON:
+		return decodeSchemaJSON(schemaBytes)
+	case DataTypeYAML:
+		return decodeSchemaYAML(schemaBytes)
+	}
+
+	// Neither format parsed: report the error of the format the schema was most likely written in.
+	trimmed := bytes.TrimSpace(schemaBytes)
+	if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') {
+		return decodeSchemaJSON(schemaBytes)
+	}
+	return decodeSchemaYAML(schemaBytes)
+}
+
+// decodeSchemaJSON decodes schema bytes keeping numbers as json.Number, as gojsonschema expects.
+func decodeSchemaJSON(schemaBytes []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(schemaBytes))
+	decoder.UseNumber()
+
+	var document interface{}
+	if err := decoder.Decode(&document); err != nil {
+		offset := decoder.InputOffset()
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			offset = syntaxErr.Offset - 1 // Offset is just past the offending byte
+		}
+		line, column := lineAndColumn(schemaBytes, offset)
+		return nil, fmt.Errorf("parse schema JSON: line %d, column %d: %w", line, column, err)
+	}
+	return document, nil
+}
+
+// decodeSchemaYAML decodes a single-document YAML schema into the same values decodeSchemaJSON produces.
+func decodeSchemaYAML(schemaBytes []byte) (interface{}, error) {
+	docs, err := DecodeDocuments(schemaBytes)
+	if err != nil {
+		// yaml errors already carry the line, e.g. "yaml: line 3: mapping values are not allowed in this context"
+		return nil, fmt.Errorf("parse schema YAML: %w", err)
+	}
+	if len(docs) != 1 {
+		return nil, fmt.Errorf("parse schema YAML: expected exactly one document, found %d", len(docs))
+	}
+	root := docs[0].Root()
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("parse schema YAML: line %d, column %d: schema must be a mapping", root.Line, root.Column)
+	}
+
+	var document interface{}
+	if err := docs[0].Node.Decode(&document); err != nil {
+		return nil, fmt.Errorf("parse schema YAML: %w", err)
+	}
+
+	// Round-trip through JSON so numbers and nested values match decodeSchemaJSON exactly.
+	jsonBytes, err := json.Marshal(jsonCompatible(document))
+	if err != nil {
+		return nil, fmt.Errorf("parse schema YAML: %w", err)
+	}
+	return decodeSchemaJSON(jsonBytes)
+}
+
+// jsonCompatible converts the non-string mapping keys YAML allows (e.g. `200:` in OpenAPI responses) to strings.
+func jsonCompatible(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = jsonCompatible(item)
+		}
+		return v
+	case map[interface{}]interface{}:
+		converted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			converted[fmt.Sprint(key)] = jsonCompatible(item)
+		}
+		return converted
+	case []interface{}:
+		for i, item := range v {
+			v[i] = jsonCompatible(item)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// lineAndColumn converts a zero-based byte offset into a 1-based line and column.
+func lineAndColumn(data []byte, offset int64) (int, int) {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	before := data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	column := len(before) - bytes.LastIndexByte(before, '\n')
+	return line, column
+}
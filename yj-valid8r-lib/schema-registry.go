@@ -0,0 +1,104 @@
+package yjvalid8r_lib
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// DefaultMaxRegistrySchemas is the number of compiled schemas a SchemaRegistry keeps when SchemaOptions.MaxSchemas is 0.
+const DefaultMaxRegistrySchemas = 256
+
+// SchemaRegistry compiles each schema location once and shares the CompiledSchema between callers,
+// e.g. across the data files of a CLI run or across web requests. It is safe for concurrent use.
+// Failed compilations are not remembered, so a later call retries them. The registry keeps at most
+// SchemaOptions.MaxSchemas schemas and drops the least recently used one to make room for another.
+type SchemaRegistry struct {
+	opts       SchemaOptions
+	maxSchemas int
+	mu         sync.Mutex
+	entries    map[string]*list.Element // values are *schemaRegistryEntry
+	recent     *list.List               // most recently used first
+}
+
+// schemaRegistryEntry holds one compiled schema; once guards the compilation.
+type schemaRegistryEntry struct {
+	key       string
+	once      sync.Once
+	schema    *CompiledSchema
+	err       error
+	createdAt time.Time
+}
+
+// NewSchemaRegistry creates a SchemaRegistry compiling schemas with the given options.
+// Without a cache the schema sources are not cached on disk, but compiled schemas are still shared.
+func NewSchemaRegistry(opts SchemaOptions) *SchemaRegistry {
+	maxSchemas := opts.MaxSchemas
+	if maxSchemas == 0 {
+		maxSchemas = DefaultMaxRegistrySchemas
+	}
+	return &SchemaRegistry{
+		opts:       opts,
+		maxSchemas: maxSchemas,
+		entries:    make(map[string]*list.Element),
+		recent:     list.New(),
+	}
+}
+
+// Get returns the compiled schema for schemaURL, compiling it on first use.
+// When the cache has a TTL, schemas older than the TTL are recompiled.
+func (r *SchemaRegistry) Get(schemaURL string) (*CompiledSchema, error) {
+	key := normalizeSchemaLocation(schemaURL)
+
+	r.mu.Lock()
+	var entry *schemaRegistryEntry
+	if element, ok := r.entries[key]; ok && !r.expired(element.Value.(*schemaRegistryEntry)) {
+		entry = element.Value.(*schemaRegistryEntry)
+		r.recent.MoveToFront(element)
+	} else {
+		if ok {
+			r.remove(element)
+		}
+		entry = &schemaRegistryEntry{key: key, createdAt: time.Now()}
+		r.entries[key] = r.recent.PushFront(entry)
+		for r.maxSchemas > 0 && r.recent.Len() > r.maxSchemas {
+			r.remove(r.recent.Back())
+		}
+	}
+	r.mu.Unlock()
+
+	entry.once.Do(func() {
+		entry.schema, entry.err = CompileSchema(key, r.opts)
+	})
+
+	if entry.err != nil {
+		r.mu.Lock()
+		if element, ok := r.entries[key]; ok && element.Value == entry {
+			r.remove(element)
+		}
+		r.mu.Unlock()
+	}
+
+	return entry.schema, entry.err
+}
+
+// Len returns the number of schemas the registry holds.
+func (r *SchemaRegistry) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.recent.Len()
+}
+
+// remove drops an entry; callers hold r.mu. Callers still compiling or using it keep their copy.
+func (r *SchemaRegistry) remove(element *list.Element) {
+	r.recent.Remove(element)
+	delete(r.entries, element.Value.(*schemaRegistryEntry).key)
+}
+
+// expired reports whether a compiled entry is older than the cache TTL; callers hold r.mu.
+func (r *SchemaRegistry) expired(entry *schemaRegistryEntry) bool {
+	if r.opts.Cache == nil || r.opts.Cache.ttl == 0 {
+		return false
+	}
+	return time.Since(entry.createdAt) >= r.opts.Cache.ttl
+}
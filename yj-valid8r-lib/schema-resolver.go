@@ -0,0 +1,210 @@
+package yjvalid8r_lib
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// SchemaResolver decides where a schema URL is actually read from, e.g. to serve remote
+// schemas from a local catalog. $refs keep resolving against the original URL.
+type SchemaResolver interface {
+	// ResolveSchema returns the location to read for a schema URL, or false to read the URL itself.
+	// An error rejects the URL, e.g. one mapped outside of the configured directory.
+	ResolveSchema(location string) (string, bool, error)
+}
+
+// PrefixSchemaResolver is a SchemaResolver backed by URL prefix to local directory mappings.
+// The longest matching prefix wins.
+type PrefixSchemaResolver []SchemaMapping
+
+// NewPrefixSchemaResolver creates a PrefixSchemaResolver from the given mappings.
+func NewPrefixSchemaResolver(mappings []SchemaMapping) PrefixSchemaResolver {
+	resolver := append(PrefixSchemaResolver(nil), mappings...)
+	sort.SliceStable(resolver, func(i, j int) bool {
+		return len(resolver[i].Prefix) > len(resolver[j].Prefix)
+	})
+	return resolver
+}
+
+// ResolveSchema maps a schema URL below a configured prefix to a file:// URL in the mapped directory.
+// URLs whose path leaves the directory, e.g. through "..", are rejected.
+func (r PrefixSchemaResolver) ResolveSchema(location string) (string, bool, error) {
+	for _, mapping := range r {
+		if mapping.Prefix == "" || !strings.HasPrefix(location, mapping.Prefix) {
+			continue
+		}
+		rest := strings.TrimPrefix(location, mapping.Prefix)
+		if unescaped, err := url.PathUnescape(rest); err == nil {
+			rest = unescaped
+		}
+		dir := filepath.Clean(mapping.Dir)
+		path := filepath.Join(dir, filepath.FromSlash(rest))
+		if rel, err := filepath.Rel(dir, path); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return "", true, fmt.Errorf("schema %s is mapped outside of %s", location, mapping.Dir)
+		}
+		return normalizeSchemaLocation(path), true, nil
+	}
+	return "", false, nil
+}
+
+// UnresolvedRefError reports a $ref that could not be loaded, with the chain of schemas leading to it.
+type UnresolvedRefError struct {
+	Chain []string // Schema locations from the root schema down to the unresolved reference.
+	Err   error    // Underlying fetch or parse error.
+}
+
+func (e *UnresolvedRefError) Error() string {
+	return fmt.Sprintf("unresolved $ref %s: %v", strings.Join(e.Chain, " -> "), e.Err)
+}
+
+func (e *UnresolvedRefError) Unwrap() error {
+	return e.Err
+}
+
+// schemaFetcher reads schema documents through an optional SchemaResolver and SchemaCache.
+type schemaFetcher struct {
+	cache    *SchemaCache
+	resolver SchemaResolver
+}
+
+// fetch returns the raw content of the schema at location.
+func (f *schemaFetcher) fetch(location string) ([]byte, error) {
+	if f.resolver != nil {
+		resolved, ok, err := f.resolver.ResolveSchema(location)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return f.cache.Fetch(resolved)
+		}
+	}
+	return f.cache.Fetch(location)
+}
+
+// load fetches and decodes the schema document at location; OpenAPI documents are translated to JSON Schema.
+func (f *schemaFetcher) load(location string) (interface{}, error) {
+	schemaBytes, err := f.fetch(location)
+	if err != nil {
+		return nil, err
+	}
+	document, err := decodeSchema(schemaBytes)
+	if err != nil {
+		return nil, err
+	}
+	return translateOpenAPI(document), nil
+}
+
+// collectRefs loads, depth-first, every schema document referenced from document (found at location)
+// into docs, keyed by URL without fragment. ids collects the $id values seen so far, which need no fetch.
+// Unloadable references are reported with their chain.
+func (f *schemaFetcher) collectRefs(location string, document interface{}, chain []string, docs map[string]interface{}, ids map[string]bool) error {
+	base, err := url.Parse(location)
+	if err != nil {
+		return &UnresolvedRefError{Chain: chain, Err: err}
+	}
+
+	var refs []string
+	walkSchemaRefs(document, base, ids, &refs)
+
+	for _, ref := range refs {
+		if _, ok := docs[ref]; ok || ids[ref] || isMetaSchemaURL(ref) {
+			continue
+		}
+		refChain := append(append([]string(nil), chain...), ref)
+
+		refDocument, err := f.load(ref)
+		if err != nil {
+			return &UnresolvedRefError{Chain: refChain, Err: err}
+		}
+		docs[ref] = refDocument
+
+		if err := f.collectRefs(ref, refDocument, refChain, docs, ids); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// walkSchemaRefs collects the absolute $id values and the absolute, fragment-less targets of every
+// $ref in a schema document, resolving both against the enclosing $id (or the document URL).
+func walkSchemaRefs(node interface{}, base *url.URL, ids map[string]bool, refs *[]string) {
+	switch v := node.(type) {
+	case []interface{}:
+		for _, item := range v {
+			walkSchemaRefs(item, base, ids, refs)
+		}
+	case map[string]interface{}:
+		for _, key := range []string{"$id", "id"} {
+			if id, ok := v[key].(string); ok {
+				if idURL, err := base.Parse(id); err == nil {
+					base = idURL
+					ids[withoutFragment(idURL)] = true
+				}
+				break
+			}
+		}
+
+		if ref, ok := v["$ref"].(string); ok {
+			if refURL, err := base.Parse(ref); err == nil {
+				target := withoutFragment(refURL)
+				if target != withoutFragment(base) {
+					*refs = append(*refs, target)
+				}
+			}
+		}
+
+		// sorted for a deterministic order of fetches and error chains
+		for _, key := range sortedKeys(v) {
+			switch key {
+			case "const", "enum", "examples", "default":
+				// literal values, not schemas
+			case "properties", "patternProperties", "dependentSchemas", "$defs", "definitions":
+				// member names are not keywords: a property named "default" is still a schema
+				members, ok := v[key].(map[string]interface{})
+				if !ok {
+					walkSchemaRefs(v[key], base, ids, refs)
+					continue
+				}
+				for _, name := range sortedKeys(members) {
+					walkSchemaRefs(members[name], base, ids, refs)
+				}
+			default:
+				walkSchemaRefs(v[key], base, ids, refs)
+			}
+		}
+	}
+}
+
+// splitFragment splits a schema location into the document location and its fragment, without the '#'.
+func splitFragment(location string) (string, string) {
+	document, fragment, _ := strings.Cut(location, "#")
+	if unescaped, err := url.PathUnescape(fragment); err == nil {
+		fragment = unescaped
+	}
+	return document, fragment
+}
+
+// withFragment appends a fragment to a document location, if there is one.
+func withFragment(location, fragment string) string {
+	if fragment == "" {
+		return location
+	}
+	return location + "#" + fragment
+}
+
+// withoutFragment returns the URL as a string without its fragment.
+func withoutFragment(u *url.URL) string {
+	stripped := *u
+	stripped.Fragment = ""
+	stripped.RawFragment = ""
+	return stripped.String()
+}
+
+// isMetaSchemaURL reports whether a URL is a JSON Schema meta-schema, which validators ship built in.
+func isMetaSchemaURL(location string) bool {
+	parsed, err := url.Parse(location)
+	return err == nil && parsed.Host == "json-schema.org"
+}
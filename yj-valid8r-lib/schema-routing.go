@@ -0,0 +1,120 @@
+package yjvalid8r_lib
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SelectSchema returns the schema location of the first route whose selector matches the document.
+// The location is rendered as a Go template with SchemaRouteParams, e.g.
+// "https://example.com/schemas/{{.Group}}/{{lower .Kind}}_{{.Version}}.json".
+// The boolean result is false when no route matches.
+func SelectSchema(routes []SchemaRoute, doc Document) (string, bool, error) {
+	root := doc.Root()
+	params := SchemaRouteParams{
+		APIVersion: scalarAt(root, "apiVersion"),
+		Kind:       scalarAt(root, "kind"),
+	}
+	params.Group, params.Version = splitAPIVersion(params.APIVersion)
+
+	for _, route := range routes {
+		matched, value, err := matchSchemaRoute(route, root, params)
+		if err != nil {
+			return "", false, fmt.Errorf("schema route %q: %w", route.Name, err)
+		}
+		if !matched {
+			continue
+		}
+
+		routeParams := params
+		routeParams.Value = value
+		location, err := renderSchemaLocation(route.Schema, routeParams)
+		if err != nil {
+			return "", false, fmt.Errorf("schema route %q: %w", route.Name, err)
+		}
+		return location, true, nil
+	}
+
+	return "", false, nil
+}
+
+// matchSchemaRoute checks a single route against the document root and returns the value found at the route pointer.
+func matchSchemaRoute(route SchemaRoute, root *yaml.Node, params SchemaRouteParams) (bool, string, error) {
+	if route.APIVersion != "" && !matchSelector(route.APIVersion, params.APIVersion) {
+		return false, "", nil
+	}
+	if route.Kind != "" && !matchSelector(route.Kind, params.Kind) {
+		return false, "", nil
+	}
+	if route.Pointer == "" {
+		return true, "", nil
+	}
+
+	node, err := nodeAtPointer(root, route.Pointer)
+	if err != nil {
+		return false, "", err
+	}
+	if node == nil {
+		return false, "", nil
+	}
+	if route.Value != "" && (node.Kind != yaml.ScalarNode || !matchSelector(route.Value, node.Value)) {
+		return false, "", nil
+	}
+	return true, node.Value, nil
+}
+
+// matchSelector matches a value against a selector where '*' stands for any sequence of characters.
+func matchSelector(selector, value string) bool {
+	if !strings.Contains(selector, "*") {
+		return selector == value
+	}
+	pattern := "^" + strings.ReplaceAll(regexp.QuoteMeta(selector), `\*`, ".*") + "$"
+	return regexp.MustCompile(pattern).MatchString(value)
+}
+
+// renderSchemaLocation expands the template placeholders of a schema location.
+func renderSchemaLocation(location string, params SchemaRouteParams) (string, error) {
+	if !strings.Contains(location, "{{") {
+		return location, nil
+	}
+
+	tmpl, err := template.New("schema").Funcs(template.FuncMap{
+		"lower": strings.ToLower,
+		"upper": strings.ToUpper,
+	}).Parse(location)
+	if err != nil {
+		return "", fmt.Errorf("parse schema template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, params); err != nil {
+		return "", fmt.Errorf("render schema template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// splitAPIVersion splits a Kubernetes apiVersion ("apps/v1") into group and version; the core group is empty.
+func splitAPIVersion(apiVersion string) (string, string) {
+	if group, version, found := strings.Cut(apiVersion, "/"); found {
+		return group, version
+	}
+	return "", apiVersion
+}
+
+// scalarAt returns the scalar value of a top-level mapping key, or "" if absent.
+func scalarAt(root *yaml.Node, key string) string {
+	if root == nil || root.Kind != yaml.MappingNode {
+		return ""
+	}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == key && root.Content[i+1].Kind == yaml.ScalarNode {
+			return root.Content[i+1].Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,198 @@
+package yjvalid8r_lib
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// searchPathTypes are the value types a SearchPathExpectation can require.
+var searchPathTypes = []string{"string", "number", "integer", "boolean", "null", "array", "object"}
+
+// Validate reports settings that cannot be checked, e.g. an unknown type or an invalid pattern.
+func (e SearchPathExpectation) Validate() error {
+	switch e.Severity {
+	case "", MessageTypeError, MessageTypeWarning:
+	default:
+		return fmt.Errorf("expect: unknown severity %q, expected error or warning", e.Severity)
+	}
+	if e.Required && e.Forbidden {
+		return fmt.Errorf("expect: required and forbidden exclude each other")
+	}
+	if (e.MinCount != nil && *e.MinCount < 0) || (e.MaxCount != nil && *e.MaxCount < 0) {
+		return fmt.Errorf("expect: minCount and maxCount must not be negative")
+	}
+	if e.MinCount != nil && e.MaxCount != nil && *e.MinCount > *e.MaxCount {
+		return fmt.Errorf("expect: minCount %d is greater than maxCount %d", *e.MinCount, *e.MaxCount)
+	}
+	if e.Minimum != nil && e.Maximum != nil && *e.Minimum > *e.Maximum {
+		return fmt.Errorf("expect: minimum %v is greater than maximum %v", *e.Minimum, *e.Maximum)
+	}
+	if e.Type != "" && !containsString(searchPathTypes, e.Type) {
+		return fmt.Errorf("expect: unknown type %q, expected one of %s", e.Type, strings.Join(searchPathTypes, ", "))
+	}
+	if _, err := regexp.Compile(e.Pattern); err != nil {
+		return fmt.Errorf("expect: invalid pattern: %v", err)
+	}
+	return nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// searchPathExpectation is a validated SearchPathExpectation with its pattern compiled.
+type searchPathExpectation struct {
+	SearchPathExpectation
+	severity ValidationMessageType
+	pattern  *regexp.Regexp
+}
+
+func compileExpectation(e SearchPathExpectation) (*searchPathExpectation, error) {
+	if err := e.Validate(); err != nil {
+		return nil, err
+	}
+	compiled := &searchPathExpectation{SearchPathExpectation: e, severity: e.Severity}
+	if compiled.severity == "" {
+		compiled.severity = MessageTypeError
+	}
+	if e.Pattern != "" {
+		compiled.pattern = regexp.MustCompile(e.Pattern)
+	}
+	return compiled, nil
+}
+
+// check reports the expectations the values found in a document do not meet. Count expectations are reported
+// at the document root, or at the first value too many; value expectations at each offending value.
+// Data without documents is checked as an empty Document, whose count findings are reported on line 1.
+func (e *searchPathExpectation) check(doc Document, pathKey string, matches []searchMatch, report *findingReport) {
+	add := func(ruleID, message string, node *yaml.Node) {
+		finding := nodeFinding(e.severity, ruleID, message, doc.Index, node)
+		if node == nil {
+			finding.Line, finding.Column = 1, 1
+		}
+		report.add(finding)
+	}
+
+	count := len(matches)
+	switch {
+	case e.Required && count == 0:
+		add(RuleSearchPathRequired, fmt.Sprintf("%s: required, but not found", pathKey), doc.Root())
+	case e.MinCount != nil && count < *e.MinCount:
+		add(RuleSearchPathCount, fmt.Sprintf("%s: found %d value(s), expected at least %d", pathKey, count, *e.MinCount), doc.Root())
+	case e.MaxCount != nil && count > *e.MaxCount:
+		add(RuleSearchPathCount, fmt.Sprintf("%s: found %d value(s), expected at most %d", pathKey, count, *e.MaxCount), matches[*e.MaxCount].at)
+	}
+
+	for _, match := range matches {
+		if e.Forbidden {
+			add(RuleSearchPathForbidden, fmt.Sprintf("%s: forbidden, but found", match.path), match.at)
+			continue
+		}
+		value, ok := match.value()
+		if !ok {
+			continue
+		}
+		if ruleID, problem := e.checkValue(match.node, value); problem != "" {
+			add(ruleID, fmt.Sprintf("%s: %s %s", match.path, compactValue(value), problem), match.at)
+		}
+	}
+}
+
+// checkValue returns the rule and a description of the first value expectation a value does not meet,
+// or an empty problem if it meets them all.
+func (e *searchPathExpectation) checkValue(node *yaml.Node, value interface{}) (ruleID, problem string) {
+	if e.Type != "" {
+		if actual := valueType(value); actual != e.Type && !(e.Type == "number" && actual == "integer") {
+			return RuleSearchPathType, fmt.Sprintf("is %s, expected %s", withArticle(actual), withArticle(e.Type))
+		}
+	}
+	if e.Equals != nil && !compareEqual(value, true, e.Equals, true) {
+		return RuleSearchPathValue, fmt.Sprintf("does not equal %s", compactValue(e.Equals))
+	}
+	if len(e.OneOf) > 0 {
+		allowed := false
+		for _, option := range e.OneOf {
+			if compareEqual(value, true, option, true) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return RuleSearchPathValue, fmt.Sprintf("is not one of %s", compactValue(e.OneOf))
+		}
+	}
+	if e.pattern != nil {
+		if node.Kind != yaml.ScalarNode {
+			return RuleSearchPathPattern, fmt.Sprintf("is not a scalar, expected a match of %q", e.Pattern)
+		}
+		if !e.pattern.MatchString(node.Value) {
+			return RuleSearchPathPattern, fmt.Sprintf("does not match %q", e.Pattern)
+		}
+	}
+	if e.Minimum != nil || e.Maximum != nil {
+		number, ok := filterNumber(value)
+		switch {
+		case !ok:
+			return RuleSearchPathRange, "is not a number"
+		case e.Minimum != nil && number < *e.Minimum:
+			return RuleSearchPathRange, fmt.Sprintf("is less than the minimum %v", *e.Minimum)
+		case e.Maximum != nil && number > *e.Maximum:
+			return RuleSearchPathRange, fmt.Sprintf("is greater than the maximum %v", *e.Maximum)
+		}
+	}
+	return "", ""
+}
+
+// valueType returns the JSON type of a decoded value; whole numbers are integers. YAML timestamps,
+// and any other value the decoder does not map to a JSON type, are strings, as they are in JSON.
+func valueType(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case string, time.Time:
+		return "string"
+	case bool:
+		return "boolean"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}, map[interface{}]interface{}:
+		return "object"
+	}
+	if number, ok := filterNumber(value); ok {
+		if number == math.Trunc(number) && !math.IsInf(number, 0) {
+			return "integer"
+		}
+		return "number"
+	}
+	return "string"
+}
+
+func withArticle(typeName string) string {
+	switch typeName {
+	case "null":
+		return "null"
+	case "array", "object", "integer":
+		return "an " + typeName
+	}
+	return "a " + typeName
+}
+
+// compactValue renders a value as single-line JSON for messages.
+func compactValue(value interface{}) string {
+	data, err := json.Marshal(jsonCompatible(value))
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
@@ -10,37 +10,133 @@ import (
 )
 
 // SearchPathsFinder searches for values in the input data at the specified paths.
+// Every document of a multi-document YAML stream is searched; each result item records
+// the index of the document it was found in.
 // It returns structured results for each search path and any error encountered.
 func SearchPathsFinder(input []byte, paths []SearchPathsDef) ([]SearchPathsOutput, error) {
 
-	var data interface{}
-	if err := yaml.Unmarshal(input, &data); err != nil {
+	docs, err := DecodeDocuments(input)
+	if err != nil {
 		return nil, fmt.Errorf("parse yaml/json into map: %w", err)
 	}
+	return SearchPathsInDocuments(docs, paths, 0)
+}
+
+// SearchPathsInDocuments searches already decoded documents, e.g. the records of DecodeJSONLines,
+// like SearchPathsFinder. The node tree of each document is walked in document order, so results
+// come back in a stable order, each with the line and column of its value. Documents with an anchor
+// containing itself, or whose aliases add more than maxAliasExpansion nodes, are rejected before the
+// walk, see CheckAliasExpansion.
+func SearchPathsInDocuments(docs []Document, paths []SearchPathsDef, maxAliasExpansion int) ([]SearchPathsOutput, error) {
+	if err := CheckAliasExpansion(docs, maxAliasExpansion); err != nil {
+		return nil, err
+	}
 
 	var outputs []SearchPathsOutput
 
 	for _, path := range paths {
-		results := resolvePath(data, path.PathKey)
-		outputs = append(outputs, SearchPathsOutput{
+		output := SearchPathsOutput{
 			PathName: path.PathName,
 			PathKey:  path.PathKey,
-			Results:  results,
-		})
+		}
+		search, err := compileSearchPath(path)
+		var expect *searchPathExpectation
+		if err == nil && path.Expect != nil {
+			expect, err = compileExpectation(*path.Expect)
+		}
+		if err != nil {
+			message := fmt.Sprintf("Invalid search path: %v", err)
+			output.Errors = append(output.Errors, message)
+			output.Findings = append(output.Findings, Finding{Severity: MessageTypeError, RuleID: RuleSearchPathInvalid, Message: message})
+			outputs = append(outputs, output)
+			continue
+		}
+
+		report := newFindingReport()
+		for _, doc := range docs {
+			matches := search(doc.Root())
+			for _, match := range matches {
+				item := match.result()
+				item.Document = doc.Index
+				output.Results = append(output.Results, item)
+			}
+			if expect != nil {
+				expect.check(doc, path.PathKey, matches, report)
+			}
+		}
+		if expect != nil && len(docs) == 0 {
+			// Empty data, e.g. "" or "---", still has to meet the required and minCount expectations
+			expect.check(Document{}, path.PathKey, nil, report)
+		}
+		output.Errors = report.errors
+		output.Warnings = report.warnings
+		output.Findings = report.sortedFindings()
+		outputs = append(outputs, output)
 	}
 
 	return outputs, nil
 }
 
-func resolvePath(data interface{}, path string) []SearchPathsOutputResultItem {
+// searchMatch is a value found by a search path.
+type searchMatch struct {
+	path string     // full path of the value, in the syntax of the search path
+	node *yaml.Node // value; aliases are resolved to the node they refer to
+	at   *yaml.Node // where the value appears in the document: the alias, for a value used through one
+}
+
+// value decodes the matched node; ok is false if it cannot be decoded.
+func (m searchMatch) value() (v interface{}, ok bool) {
+	if err := m.node.Decode(&v); err != nil {
+		return nil, false
+	}
+	return v, true
+}
+
+// result returns the result item of the match.
+func (m searchMatch) result() SearchPathsOutputResultItem {
+	raw := m.node.Value
+	if data, ok := m.value(); ok {
+		raw = marshalToString(data)
+	}
+	return SearchPathsOutputResultItem{FullPath: m.path, Raw: raw, Line: m.at.Line, Column: m.at.Column}
+}
+
+// compileSearchPath returns a function searching the root node of a document for the path, in the syntax of the path.
+func compileSearchPath(path SearchPathsDef) (func(root *yaml.Node) []searchMatch, error) {
+	switch path.Syntax {
+	case SearchPathSyntaxLegacy, "":
+		return func(root *yaml.Node) []searchMatch {
+			return resolvePath(root, path.PathKey)
+		}, nil
+	case SearchPathSyntaxJSONPath:
+		query, err := compileJSONPath(path.PathKey)
+		if err != nil {
+			return nil, err
+		}
+		return func(root *yaml.Node) []searchMatch {
+			var matches []searchMatch
+			for _, node := range query.evaluate(root) {
+				matches = append(matches, searchMatch{path: node.path, node: node.node, at: node.at})
+			}
+			return matches
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown syntax %q (%s or %s)", path.Syntax, SearchPathSyntaxLegacy, SearchPathSyntaxJSONPath)
+	}
+}
+
+func resolvePath(root *yaml.Node, path string) []searchMatch {
+	if root == nil {
+		return nil
+	}
 	segments := strings.Split(path, ".")
-	return resolve(data, segments, "")
+	return resolve(root, root, segments, "")
 }
 
-func resolve(data interface{}, segments []string, currentPath string) []SearchPathsOutputResultItem {
+// resolve walks node, which appears in the document at the position of at, along the path segments.
+func resolve(node, at *yaml.Node, segments []string, currentPath string) []searchMatch {
 	if len(segments) == 0 {
-		rawStr := marshalToString(data)
-		return []SearchPathsOutputResultItem{{FullPath: currentPath, Raw: rawStr}}
+		return []searchMatch{{path: currentPath, node: node, at: at}}
 	}
 
 	current := segments[0]
@@ -48,15 +144,14 @@ func resolve(data interface{}, segments []string, currentPath string) []SearchPa
 
 	if strings.HasSuffix(current, "[]") {
 		key := strings.TrimSuffix(current, "[]")
-		if m, ok := data.(map[string]interface{}); ok {
-			if arr, ok := m[key].([]interface{}); ok {
-				var results []SearchPathsOutputResultItem
-				for i, item := range arr {
-					path := fmt.Sprintf("%s%s[%d]", currentPathPrefix(currentPath), key, i)
-					results = append(results, resolve(item, rest, path)...)
-				}
-				return results
+		if arr, _ := mappingValue(node, key); arr != nil && arr.Kind == yaml.SequenceNode {
+			var results []searchMatch
+			for i, item := range arr.Content {
+				path := fmt.Sprintf("%s%s[%d]", currentPathPrefix(currentPath), key, i)
+				item, itemAt := followAlias(item)
+				results = append(results, resolve(item, itemAt, rest, path)...)
 			}
+			return results
 		}
 	} else if strings.Contains(current, "[") && strings.HasSuffix(current, "]") {
 		// Handles mixed key[index], e.g., "emails[0]"
@@ -64,40 +159,34 @@ func resolve(data interface{}, segments []string, currentPath string) []SearchPa
 		key := parts[0]
 		indexStr := strings.TrimSuffix(parts[1], "]")
 
-		if m, ok := data.(map[string]interface{}); ok {
-			if arr, ok := m[key].([]interface{}); ok {
-				if index, ok := tryParseArrayIndex(indexStr); ok && index < len(arr) {
-					path := fmt.Sprintf("%s%s[%d]", currentPathPrefix(currentPath), key, index)
-					return resolve(arr[index], rest, path)
-				}
-			}
-		}
-	} else {
-		if m, ok := data.(map[string]interface{}); ok {
-			if val, exists := m[current]; exists {
-				path := fmt.Sprintf("%s%s", currentPathPrefix(currentPath), current)
-				return resolve(val, rest, path)
+		if arr, _ := mappingValue(node, key); arr != nil && arr.Kind == yaml.SequenceNode {
+			if index, ok := tryParseArrayIndex(indexStr); ok && index >= 0 && index < len(arr.Content) {
+				path := fmt.Sprintf("%s%s[%d]", currentPathPrefix(currentPath), key, index)
+				item, itemAt := followAlias(arr.Content[index])
+				return resolve(item, itemAt, rest, path)
 			}
 		}
+	} else if value, valueAt := mappingValue(node, current); value != nil {
+		path := fmt.Sprintf("%s%s", currentPathPrefix(currentPath), current)
+		return resolve(value, valueAt, rest, path)
 	}
 
-	// fallback for recursive search
-	var found []SearchPathsOutputResultItem
-	switch v := data.(type) {
-	case []interface{}:
-		for i, item := range v {
+	// fallback for recursive search, in document order
+	var found []searchMatch
+	switch node.Kind {
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
 			path := fmt.Sprintf("%s[%d]", currentPath, i)
-			found = append(found, resolve(item, segments, path)...)
+			item, itemAt := followAlias(item)
+			found = append(found, resolve(item, itemAt, segments, path)...)
 		}
-	case map[string]interface{}:
-		for key, val := range v {
-			var path string
-			if key == segments[0] {
-				path = fmt.Sprintf("%s%s", currentPathPrefix(currentPath), key)
-				found = append(found, resolve(val, segments[1:], path)...)
+	case yaml.MappingNode:
+		for _, pair := range mappingPairs(node) {
+			path := fmt.Sprintf("%s%s", currentPathPrefix(currentPath), pair.key.Value)
+			if pair.key.Value == segments[0] {
+				found = append(found, resolve(pair.value, pair.at, segments[1:], path)...)
 			} else {
-				path = fmt.Sprintf("%s%s", currentPathPrefix(currentPath), key)
-				found = append(found, resolve(val, segments, path)...)
+				found = append(found, resolve(pair.value, pair.at, segments, path)...)
 			}
 		}
 	}
@@ -0,0 +1,101 @@
+package tests
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	yjvalid8r_lib "github.com/sassoftware/yj-valid8r/yj-valid8r-lib"
+)
+
+type duplicateKeyPosition struct {
+	line     int
+	column   int
+	endLine  int
+	endCol   int
+	pointer  string
+	document int
+}
+
+func TestCheckDuplicateKeysFinder(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       string
+		wantErrors []string
+		want       []duplicateKeyPosition
+	}{
+		{
+			name: "Unique keys",
+			data: "name: web\nspec:\n  port: 80\n",
+		},
+		{
+			name:       "YAML nested duplicate",
+			data:       "name: web\nspec:\n  port: 80\n  port: 81\nname: api\n",
+			wantErrors: []string{`Line 4: Duplicate key "port", first defined at line 3.`, `Line 5: Duplicate key "name", first defined at line 1.`},
+			want:       []duplicateKeyPosition{{4, 3, 4, 7, "/spec/port", 0}, {5, 1, 5, 5, "/name", 0}},
+		},
+		{
+			name:       "YAML duplicate in a sequence item of a later document",
+			data:       "a: 1\n---\nitems:\n  - id: 1\n    id: 2\n",
+			wantErrors: []string{`Line 5: Duplicate key "id", first defined at line 4.`},
+			want:       []duplicateKeyPosition{{5, 5, 5, 7, "/items/0/id", 1}},
+		},
+		{
+			name: "YAML merge keys are not duplicates",
+			data: "base: &base {a: 1}\nother: &other {b: 2}\nmerged:\n  <<: *base\n  <<: *other\n",
+		},
+		{
+			name:       "JSON duplicate on one line",
+			data:       `{"a": 1, "b": {"c": true, "c": false}, "a": 2}`,
+			wantErrors: []string{`Line 1: Duplicate key "c", first defined at line 1.`, `Line 1: Duplicate key "a", first defined at line 1.`},
+			want:       []duplicateKeyPosition{{1, 27, 1, 30, "/b/c", 0}, {1, 40, 1, 43, "/a", 0}},
+		},
+		{
+			name:       "JSON duplicate in an array element",
+			data:       "{\n  \"items\": [\n    {\"id\": 1},\n    {\"id\": 2,\n     \"id\": 3}\n  ]\n}\n",
+			wantErrors: []string{`Line 5: Duplicate key "id", first defined at line 4.`},
+			want:       []duplicateKeyPosition{{5, 6, 5, 10, "/items/1/id", 0}},
+		},
+		{
+			name:       "JSON escaped names are compared decoded",
+			data:       "{\"a\": 1,\n \"\\u0061\": 2}",
+			wantErrors: []string{`Line 2: Duplicate key "a", first defined at line 1.`},
+			want:       []duplicateKeyPosition{{2, 2, 2, 10, "/a", 0}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := yjvalid8r_lib.CheckDuplicateKeysFinder([]byte(tt.data))
+
+			if result.Valid != (len(tt.wantErrors) == 0) {
+				t.Errorf("Valid = %v, want %v", result.Valid, len(tt.wantErrors) == 0)
+			}
+			if !reflect.DeepEqual(result.Errors, tt.wantErrors) {
+				t.Errorf("Errors = %q, want %q", result.Errors, tt.wantErrors)
+			}
+
+			var got []duplicateKeyPosition
+			for _, f := range result.Findings {
+				if f.RuleID != yjvalid8r_lib.RuleDuplicateKey || f.Severity != yjvalid8r_lib.MessageTypeError {
+					t.Errorf("unexpected finding %+v", f)
+				}
+				got = append(got, duplicateKeyPosition{f.Line, f.Column, f.EndLine, f.EndColumn, f.Pointer, f.Document})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("positions = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckDuplicateKeysFinder_InvalidYAML(t *testing.T) {
+	result := yjvalid8r_lib.CheckDuplicateKeysFinder([]byte("a: [1, 2\n"))
+
+	if !result.Valid || len(result.Findings) != 0 {
+		t.Fatalf("expected no findings for unparsable data, got %+v", result)
+	}
+	if len(result.Messages) != 1 || !strings.HasPrefix(result.Messages[0], "Duplicate key check skipped:") {
+		t.Errorf("Messages = %q, want a skipped note", result.Messages)
+	}
+}
@@ -1,6 +1,7 @@
 package tests
 
 import (
+	"reflect"
 	"testing"
 
 	yjvalid8r_lib "github.com/sassoftware/yj-valid8r/yj-valid8r-lib"
@@ -58,3 +59,118 @@ func TestCheckTabsAndWhitespacesFinder(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckFormatting(t *testing.T) {
+	enabled := true
+	tests := []struct {
+		name      string
+		input     string
+		rules     yjvalid8r_lib.FormattingRules
+		wantRules []string
+	}{
+		{
+			name:  "No Issues",
+			input: "spec:\n  ports:\n    - 80\n  items:\n  - a\n",
+		},
+		{
+			name:      "CRLF Line Endings",
+			input:     "key: value \r\nother: line\r\n",
+			rules:     yjvalid8r_lib.FormattingRules{EndOfLine: yjvalid8r_lib.EndOfLineLF},
+			wantRules: []string{yjvalid8r_lib.RuleWhitespaceTrailing, yjvalid8r_lib.RuleLineEnding},
+		},
+		{
+			name:  "Expected CRLF",
+			input: "key: value\r\nother: line\r\n",
+			rules: yjvalid8r_lib.FormattingRules{EndOfLine: yjvalid8r_lib.EndOfLineCRLF},
+		},
+		{
+			name:      "BOM",
+			input:     "\uFEFFkey: value\n",
+			rules:     yjvalid8r_lib.FormattingRules{BOM: yjvalid8r_lib.FormattingRule{Enabled: &enabled}},
+			wantRules: []string{yjvalid8r_lib.RuleBOM},
+		},
+		{
+			name:      "Missing Final Newline",
+			input:     "key: value",
+			rules:     yjvalid8r_lib.FormattingRules{FinalNewline: yjvalid8r_lib.FormattingRule{Enabled: &enabled}},
+			wantRules: []string{yjvalid8r_lib.RuleFinalNewline},
+		},
+		{
+			name:      "Inconsistent Indentation",
+			input:     "spec:\n  ports:\n      - 80\n  selector:\n     app: web\n",
+			rules:     yjvalid8r_lib.FormattingRules{Indentation: yjvalid8r_lib.FormattingRule{Enabled: &enabled}},
+			wantRules: []string{yjvalid8r_lib.RuleIndentation, yjvalid8r_lib.RuleIndentation},
+		},
+		{
+			name:      "Configured Indent Width",
+			input:     "spec:\n  port: 80\n",
+			rules:     yjvalid8r_lib.FormattingRules{IndentWidth: 4},
+			wantRules: []string{yjvalid8r_lib.RuleIndentation},
+		},
+		{
+			name:  "Disabled Rule",
+			input: "key: value",
+			rules: yjvalid8r_lib.FormattingRules{FinalNewline: yjvalid8r_lib.FormattingRule{Enabled: new(bool)}, EndOfLine: yjvalid8r_lib.EndOfLineLF},
+		},
+		{
+			name:      "Zero Value Checks Only Tabs And Trailing Whitespace",
+			input:     "\uFEFFspec:\r\n   port: 80 \r\nname: x",
+			wantRules: []string{yjvalid8r_lib.RuleWhitespaceTrailing},
+		},
+		{
+			name:      "Severity Enables Rule",
+			input:     "key: value",
+			rules:     yjvalid8r_lib.FormattingRules{FinalNewline: yjvalid8r_lib.FormattingRule{Severity: yjvalid8r_lib.MessageTypeInfo}},
+			wantRules: []string{yjvalid8r_lib.RuleFinalNewline},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := yjvalid8r_lib.CheckFormatting([]byte(tt.input), tt.rules)
+			var gotRules []string
+			for _, finding := range result.Findings {
+				gotRules = append(gotRules, finding.RuleID)
+			}
+			if !reflect.DeepEqual(gotRules, tt.wantRules) {
+				t.Errorf("Expected rules %v, got findings: %+v", tt.wantRules, result.Findings)
+			}
+		})
+	}
+}
+
+func TestCheckFormatting_Severity(t *testing.T) {
+	rules := yjvalid8r_lib.FormattingRules{
+		Tabs:         yjvalid8r_lib.FormattingRule{Severity: yjvalid8r_lib.MessageTypeWarning},
+		FinalNewline: yjvalid8r_lib.FormattingRule{Severity: yjvalid8r_lib.MessageTypeError},
+		EndOfLine:    yjvalid8r_lib.EndOfLineLF,
+	}
+	result := yjvalid8r_lib.CheckFormatting([]byte("a:\n\tb: 1\r\nc: 2"), rules)
+
+	wantErrors := []string{"Line 3: No newline at end of file."}
+	wantWarnings := []string{"Line 2: Tab character found.", "Line 2: CRLF line ending found, expected LF (1 line)."}
+	if !reflect.DeepEqual(result.Errors, wantErrors) {
+		t.Errorf("Expected errors %v, got %v", wantErrors, result.Errors)
+	}
+	if !reflect.DeepEqual(result.Warnings, wantWarnings) {
+		t.Errorf("Expected warnings %v, got %v", wantWarnings, result.Warnings)
+	}
+
+	indentation := yjvalid8r_lib.CheckFormatting([]byte("spec:\n   port: 80\nmeta:\n  name: x\n"), yjvalid8r_lib.FormattingRules{IndentWidth: 2})
+	want := yjvalid8r_lib.Finding{Severity: yjvalid8r_lib.MessageTypeWarning, RuleID: yjvalid8r_lib.RuleIndentation, Message: "Indentation of 3 spaces found, expected 2.", Line: 2, Column: 1, EndLine: 2, EndColumn: 4}
+	if len(indentation.Findings) != 1 || indentation.Findings[0] != want {
+		t.Errorf("Expected finding %+v, got %+v", want, indentation.Findings)
+	}
+}
+
+func TestFormattingRules_Validate(t *testing.T) {
+	if err := (yjvalid8r_lib.FormattingRules{}).Validate(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := (yjvalid8r_lib.FormattingRules{BOM: yjvalid8r_lib.FormattingRule{Severity: "fatal"}}).Validate(); err == nil {
+		t.Error("Expected an error for an unknown severity")
+	}
+	if err := (yjvalid8r_lib.FormattingRules{EndOfLine: "cr"}).Validate(); err == nil {
+		t.Error("Expected an error for an unknown endOfLine")
+	}
+}
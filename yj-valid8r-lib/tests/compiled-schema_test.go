@@ -0,0 +1,97 @@
+package tests
+
+import (
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	yjvalid8r_lib "github.com/sassoftware/yj-valid8r/yj-valid8r-lib"
+)
+
+func TestCompileSchema_FetchesEachSchemaOnce(t *testing.T) {
+	var hits int32
+	server := newSchemaServer(t, &hits)
+
+	schema, err := yjvalid8r_lib.CompileSchema(server.URL+"/root.json", yjvalid8r_lib.SchemaOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	dataFiles := []string{
+		"name: web\nport: 80\n",
+		"name: api\nport: 0\n",
+		"name: db\nport: 5432\n---\nname: cache\nport: -1\n",
+	}
+	wantErrors := []int{0, 1, 1}
+
+	for i, data := range dataFiles {
+		results, err := schema.Validate([]byte(data))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(results) != wantErrors[i] {
+			t.Errorf("data file %d: expected %d messages, got: %+v", i, wantErrors[i], results)
+		}
+	}
+
+	// One request for the root schema and one for the $ref'd port schema, no matter how many validations.
+	if got := atomic.LoadInt32(&hits); got != 2 {
+		t.Errorf("Expected 2 requests to the schema server, got %d", got)
+	}
+}
+
+func TestCompiledSchema_Properties(t *testing.T) {
+	schemaURL := writeTempSchemaFile(t, `{
+		"type": "object",
+		"properties": {
+			"name": { "type": "string" },
+			"age": { "type": "number" }
+		}
+	}`)
+
+	schema, err := yjvalid8r_lib.CompileSchema(schemaURL, yjvalid8r_lib.SchemaOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	props := schema.Properties()
+	if _, ok := props["name"]; !ok || len(props) != 2 {
+		t.Errorf("Unexpected top-level properties: %v", props)
+	}
+	if !strings.HasPrefix(schema.Location(), "file://") {
+		t.Errorf("Expected file:// location, got %s", schema.Location())
+	}
+}
+
+func TestCompiledSchema_ValidateDocument(t *testing.T) {
+	schemaURL := writeTempSchemaFile(t, `{
+		"type": "object",
+		"properties": { "replicas": { "type": "integer" } }
+	}`)
+
+	schema, err := yjvalid8r_lib.CompileSchema(schemaURL, yjvalid8r_lib.SchemaOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	docs, err := yjvalid8r_lib.DecodeDocuments([]byte("replicas: 1\n---\nreplicas: two\n"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	results, err := schema.ValidateDocument(docs[1])
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(results) != 1 || results[0].Document != 1 || !strings.HasPrefix(results[0].Message, "Line 3:") {
+		t.Errorf("Expected a single error on line 3 of document 1, got: %+v", results)
+	}
+}
+
+func TestCompileSchema_InvalidSchema(t *testing.T) {
+	schemaURL := writeTempSchemaFile(t, `{ "type": 42 }`)
+
+	if _, err := yjvalid8r_lib.CompileSchema(schemaURL, yjvalid8r_lib.SchemaOptions{}); err == nil {
+		t.Error("Expected compile error for invalid schema")
+	}
+}
@@ -1,7 +1,9 @@
 package tests
 
 import (
+	"bytes"
 	"fmt"
+	"strings"
 	"testing"
 
 	yjvalid8r_lib "github.com/sassoftware/yj-valid8r/yj-valid8r-lib"
@@ -23,11 +25,56 @@ func TestDetectDataType(t *testing.T) {
 			input:    []byte("name: John\nage: 30"),
 			expected: yjvalid8r_lib.DataTypeYAML,
 		},
+		{
+			name:     "Valid YAML - Multiple Documents",
+			input:    []byte("name: John\n---\nname: Jane\n"),
+			expected: yjvalid8r_lib.DataTypeYAML,
+		},
+		{
+			name:     "Unknown Format - Scalar Second Document",
+			input:    []byte("name: John\n---\njust text\n"),
+			expected: yjvalid8r_lib.DataTypeUNKNOWN,
+		},
 		{
 			name:     "Unknown Format - Plain Text",
 			input:    []byte("Just a plain string"),
 			expected: yjvalid8r_lib.DataTypeUNKNOWN,
 		},
+		{
+			name:     "Valid TOML",
+			input:    []byte("title = \"web\"\n\n[owner]\nname = \"John\"\n"),
+			expected: yjvalid8r_lib.DataTypeTOML,
+		},
+		{
+			name:     "Valid JSON5 - Comments and Trailing Comma",
+			input:    []byte("{\n  // JSONC\n  \"name\": \"John\",\n}"),
+			expected: yjvalid8r_lib.DataTypeJSON5,
+		},
+		{
+			name:     "Valid JSON5 - Unquoted Keys",
+			input:    []byte("{name: 'John', age: +30}"),
+			expected: yjvalid8r_lib.DataTypeJSON5,
+		},
+		{
+			name:     "Valid YAML - Flow Style Root",
+			input:    []byte("{name: John, tags: [admin, dev]}"),
+			expected: yjvalid8r_lib.DataTypeYAML,
+		},
+		{
+			name:     "Valid YAML - Flow Style Mapping Without JSON5 Syntax",
+			input:    []byte("{a: 1}"),
+			expected: yjvalid8r_lib.DataTypeYAML,
+		},
+		{
+			name:     "Valid JSON5 - Unquoted Keys and Hex Number",
+			input:    []byte("{a: 0x1F}"),
+			expected: yjvalid8r_lib.DataTypeJSON5,
+		},
+		{
+			name:     "Valid YAML - Flow Style Document in a Stream",
+			input:    []byte("name: John\n---\n{\"name\": \"Jane\"}\n"),
+			expected: yjvalid8r_lib.DataTypeYAML,
+		},
 		{
 			name:     "Unknown Format - Malformed JSON",
 			input:    []byte(`{"name": "John", "age":}`),
@@ -46,6 +93,44 @@ func TestDetectDataType(t *testing.T) {
 	}
 }
 
+func TestDetectDataFormat(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          string
+		wantFormat     string
+		wantConfidence string
+		wantErr        string
+		wantLine       int
+		wantColumn     int
+	}{
+		{"JSON", `{"name": "John"}`, yjvalid8r_lib.DataTypeJSON, yjvalid8r_lib.ConfidenceHigh, "", 0, 0},
+		{"Block YAML", "name: John\n", yjvalid8r_lib.DataTypeYAML, yjvalid8r_lib.ConfidenceHigh, "", 0, 0},
+		{"Flow YAML", "[admin, dev]", yjvalid8r_lib.DataTypeYAML, yjvalid8r_lib.ConfidenceMedium, "", 0, 0},
+		{"Flow YAML mapping", "{a: 1}", yjvalid8r_lib.DataTypeYAML, yjvalid8r_lib.ConfidenceMedium, "", 0, 0},
+		{"JSON5 with comment", "{a: 1} // JSONC", yjvalid8r_lib.DataTypeJSON5, yjvalid8r_lib.ConfidenceHigh, "", 0, 0},
+		{"Scalar root", "name: John\n---\njust text\n", yjvalid8r_lib.DataTypeUNKNOWN, yjvalid8r_lib.ConfidenceNone, "document 1 is a scalar", 3, 1},
+		{"Malformed JSON", "{\n  \"name\": \"John\",\n  \"age\":\n}", yjvalid8r_lib.DataTypeUNKNOWN, yjvalid8r_lib.ConfidenceNone, "json: line 4, column 1: invalid character '}'", 4, 1},
+		{"Malformed YAML", "name: John\n  age: 30\n", yjvalid8r_lib.DataTypeUNKNOWN, yjvalid8r_lib.ConfidenceNone, "yaml: line 2: mapping values are not allowed", 2, 0},
+		{"Malformed TOML", "[owner]\nname = \n", yjvalid8r_lib.DataTypeUNKNOWN, yjvalid8r_lib.ConfidenceNone, "toml: line 2", 2, 8},
+		{"Malformed JSON5", "{name: 'John', // comment\n age: }", yjvalid8r_lib.DataTypeUNKNOWN, yjvalid8r_lib.ConfidenceNone, "json5: line 2, column 7: unexpected '}'", 2, 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := yjvalid8r_lib.DetectDataFormat([]byte(tt.input))
+			if got.Format != tt.wantFormat || got.Confidence != tt.wantConfidence {
+				t.Errorf("DetectDataFormat() = %s (%s), want %s (%s)", got.Format, got.Confidence, tt.wantFormat, tt.wantConfidence)
+			}
+			if !strings.Contains(got.Error, tt.wantErr) || (tt.wantErr == "") != (got.Error == "") {
+				t.Errorf("Error = %q, want it to contain %q", got.Error, tt.wantErr)
+			}
+			if got.Line != tt.wantLine || got.Column != tt.wantColumn {
+				t.Errorf("Position = %d:%d, want %d:%d", got.Line, got.Column, tt.wantLine, tt.wantColumn)
+			}
+		})
+	}
+}
+
 func TestIsUnknownDataType(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -78,3 +163,80 @@ func TestIsUnknownDataType(t *testing.T) {
 		})
 	}
 }
+
+func TestRegisterDataFormat(t *testing.T) {
+	ini := yjvalid8r_lib.DataFormat{
+		Name:   "test-ini",
+		Detect: func(data []byte) bool { return bytes.HasPrefix(data, []byte("[test-ini]")) },
+		Decode: func(data []byte) ([]yjvalid8r_lib.Document, error) {
+			return yjvalid8r_lib.DecodeDocuments([]byte("section: test-ini\n"))
+		},
+	}
+	if err := yjvalid8r_lib.RegisterDataFormat(ini); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := yjvalid8r_lib.UnregisterDataFormat(ini.Name); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+
+	data := []byte("[test-ini]\nkey=value\n")
+	if got := yjvalid8r_lib.DetectDataType(data); got != "test-ini" {
+		t.Errorf("DetectDataType() = %q, want test-ini", got)
+	}
+	docs, err := yjvalid8r_lib.DecodeDocuments(data)
+	if err != nil || len(docs) != 1 || docs[0].Root().Content[1].Value != "test-ini" {
+		t.Errorf("DecodeDocuments() = %+v, %v", docs, err)
+	}
+
+	formats := yjvalid8r_lib.DataFormats()
+	if last := formats[len(formats)-1].Name; last != "test-ini" {
+		t.Errorf("Expected the registered format last, got %q", last)
+	}
+
+	tests := []struct {
+		name    string
+		format  yjvalid8r_lib.DataFormat
+		wantErr string
+	}{
+		{"Already registered", ini, "already registered"},
+		{"Built-in name", yjvalid8r_lib.DataFormat{Name: yjvalid8r_lib.DataTypeTOML, Detect: ini.Detect, Decode: ini.Decode}, "already registered"},
+		{"No name", yjvalid8r_lib.DataFormat{Detect: ini.Detect, Decode: ini.Decode}, "needs a name"},
+		{"No decoder", yjvalid8r_lib.DataFormat{Name: "hcl", Detect: ini.Detect}, "needs a name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := yjvalid8r_lib.RegisterDataFormat(tt.format)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUnregisterDataFormat(t *testing.T) {
+	format := yjvalid8r_lib.DataFormat{
+		Name:   "test-unregister",
+		Detect: func(data []byte) bool { return bytes.HasPrefix(data, []byte("[test-unregister]")) },
+		Decode: func(data []byte) ([]yjvalid8r_lib.Document, error) { return nil, nil },
+	}
+	if err := yjvalid8r_lib.RegisterDataFormat(format); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := yjvalid8r_lib.UnregisterDataFormat(format.Name); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := yjvalid8r_lib.DetectDataType([]byte("[test-unregister]\n")); got == format.Name {
+		t.Errorf("Expected the format gone, got %q", got)
+	}
+
+	for name, wantErr := range map[string]string{
+		format.Name:                "not registered",
+		yjvalid8r_lib.DataTypeJSON: "built in",
+	} {
+		if err := yjvalid8r_lib.UnregisterDataFormat(name); err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Errorf("UnregisterDataFormat(%q) = %v, want it to contain %q", name, err, wantErr)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package tests
+
+import (
+	"testing"
+
+	yjvalid8r_lib "github.com/sassoftware/yj-valid8r/yj-valid8r-lib"
+)
+
+func TestDecodeDocuments(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantIndex []int
+		wantLines []int
+		wantErr   bool
+	}{
+		{
+			name:      "Single YAML document",
+			input:     "name: John\nage: 30\n",
+			wantIndex: []int{0},
+			wantLines: []int{1},
+		},
+		{
+			name:      "JSON document",
+			input:     `{"name": "John"}`,
+			wantIndex: []int{0},
+			wantLines: []int{1},
+		},
+		{
+			name:      "Multiple documents",
+			input:     "---\nkind: Service\n---\nkind: Deployment\nspec:\n  replicas: 1\n",
+			wantIndex: []int{0, 1},
+			wantLines: []int{2, 4},
+		},
+		{
+			name:      "Empty documents are skipped but counted",
+			input:     "kind: Service\n---\n---\nkind: Deployment\n---\n",
+			wantIndex: []int{0, 2},
+			wantLines: []int{1, 4},
+		},
+		{
+			name:    "Invalid second document",
+			input:   "kind: Service\n---\nkind: [Deployment\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			docs, err := yjvalid8r_lib.DecodeDocuments([]byte(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DecodeDocuments() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(docs) != len(tt.wantIndex) {
+				t.Fatalf("DecodeDocuments() returned %d documents, want %d", len(docs), len(tt.wantIndex))
+			}
+			for i, doc := range docs {
+				if doc.Index != tt.wantIndex[i] {
+					t.Errorf("document %d: Index = %d, want %d", i, doc.Index, tt.wantIndex[i])
+				}
+				if doc.StartLine() != tt.wantLines[i] {
+					t.Errorf("document %d: StartLine() = %d, want %d", i, doc.StartLine(), tt.wantLines[i])
+				}
+			}
+		})
+	}
+}
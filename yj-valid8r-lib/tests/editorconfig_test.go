@@ -0,0 +1,217 @@
+package tests
+
+import (
+	"path/filepath"
+	"testing"
+
+	yjvalid8r_lib "github.com/sassoftware/yj-valid8r/yj-valid8r-lib"
+)
+
+func TestApplyEditorConfig(t *testing.T) {
+	dir := t.TempDir()
+	writeSchemaFiles(t, dir, map[string]string{
+		".editorconfig": `root = true
+
+[*]
+end_of_line = lf
+insert_final_newline = true
+trim_trailing_whitespace = true
+
+[*.{yaml,yml}]
+indent_style = space
+indent_size = 4
+
+[legacy/**]
+end_of_line = CRLF
+insert_final_newline = false
+`,
+		"legacy/.editorconfig": `# nearer files override farther ones
+[*.json]
+indent_style = tab
+trim_trailing_whitespace = unset
+`,
+	})
+
+	tests := []struct {
+		name  string
+		path  string
+		rules yjvalid8r_lib.FormattingRules
+		check func(t *testing.T, rules yjvalid8r_lib.FormattingRules)
+	}{
+		{
+			name: "YAML file",
+			path: "config/app.yaml",
+			check: func(t *testing.T, rules yjvalid8r_lib.FormattingRules) {
+				if rules.IndentWidth != 4 || rules.EndOfLine != yjvalid8r_lib.EndOfLineLF {
+					t.Errorf("Expected indent 4 and lf, got: %+v", rules)
+				}
+				if rules.FinalNewline.Enabled == nil || !*rules.FinalNewline.Enabled {
+					t.Errorf("Expected final newline rule enabled, got: %+v", rules.FinalNewline)
+				}
+				if rules.Tabs.Enabled != nil {
+					t.Errorf("Expected tabs rule untouched, got: %+v", rules.Tabs)
+				}
+			},
+		},
+		{
+			name: "Nested file",
+			path: "legacy/data.json",
+			check: func(t *testing.T, rules yjvalid8r_lib.FormattingRules) {
+				if rules.EndOfLine != yjvalid8r_lib.EndOfLineCRLF || rules.IndentWidth != 0 {
+					t.Errorf("Expected crlf and no indent width, got: %+v", rules)
+				}
+				if rules.FinalNewline.Enabled == nil || *rules.FinalNewline.Enabled {
+					t.Errorf("Expected final newline rule disabled, got: %+v", rules.FinalNewline)
+				}
+				if rules.Tabs.Enabled == nil || *rules.Tabs.Enabled {
+					t.Errorf("Expected tabs allowed by indent_style = tab, got: %+v", rules.Tabs)
+				}
+				if rules.TrailingWhitespace.Enabled != nil {
+					t.Errorf("Expected unset trailing whitespace rule, got: %+v", rules.TrailingWhitespace)
+				}
+			},
+		},
+		{
+			name:  "Explicit rules win",
+			path:  "app.yml",
+			rules: yjvalid8r_lib.FormattingRules{IndentWidth: 2, FinalNewline: yjvalid8r_lib.FormattingRule{Enabled: new(bool)}},
+			check: func(t *testing.T, rules yjvalid8r_lib.FormattingRules) {
+				if rules.IndentWidth != 2 || *rules.FinalNewline.Enabled {
+					t.Errorf("Expected explicit settings kept, got: %+v", rules)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := yjvalid8r_lib.ApplyEditorConfig(filepath.Join(dir, tt.path), tt.rules)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			tt.check(t, rules)
+		})
+	}
+}
+
+func TestApplyEditorConfig_Checks(t *testing.T) {
+	dir := t.TempDir()
+	writeSchemaFiles(t, dir, map[string]string{
+		".editorconfig": "root = true\n[*.yaml]\ntrim_trailing_whitespace = false\nindent_size = 4\n",
+	})
+
+	rules, err := yjvalid8r_lib.ApplyEditorConfig(filepath.Join(dir, "data.yaml"), yjvalid8r_lib.FormattingRules{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	result := yjvalid8r_lib.CheckFormatting([]byte("spec: \n    port: 80\n"), rules)
+	if len(result.Findings) != 0 {
+		t.Errorf("Expected no findings under the .editorconfig rules, got: %+v", result.Findings)
+	}
+
+	result = yjvalid8r_lib.CheckFormatting([]byte("spec:\n  port: 80\n"), rules)
+	if len(result.Findings) != 1 || result.Findings[0].RuleID != yjvalid8r_lib.RuleIndentation {
+		t.Errorf("Expected an indentation finding, got: %+v", result.Findings)
+	}
+}
+
+func TestApplyEditorConfig_Fix(t *testing.T) {
+	dir := t.TempDir()
+	writeSchemaFiles(t, dir, map[string]string{
+		".editorconfig": "root = true\n[*.json]\nindent_style = tab\ntrim_trailing_whitespace = false\ninsert_final_newline = false\n",
+	})
+
+	rules, err := yjvalid8r_lib.ApplyEditorConfig(filepath.Join(dir, "data.json"), yjvalid8r_lib.FormattingRules{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	data := []byte("{ \n\t\"port\": 80}")
+	result := yjvalid8r_lib.FixWhitespace(data, yjvalid8r_lib.WhitespaceFixOptions{Rules: rules})
+	if result.Changed || string(result.Data) != string(data) {
+		t.Errorf("Expected the data kept as the .editorconfig allows it, got: %q (%v)", result.Data, result.Messages)
+	}
+	if check := yjvalid8r_lib.CheckFormatting(data, rules); len(check.Findings) != 0 {
+		t.Errorf("Expected no findings under the .editorconfig rules, got: %+v", check.Findings)
+	}
+}
+
+func TestApplyEditorConfig_TabIndentStyleKeepsYAMLTabsRule(t *testing.T) {
+	dir := t.TempDir()
+	writeSchemaFiles(t, dir, map[string]string{
+		".editorconfig": "root = true\n[*]\nindent_style = tab\n",
+	})
+
+	for _, name := range []string{"data.yaml", "data.YML"} {
+		rules, err := yjvalid8r_lib.ApplyEditorConfig(filepath.Join(dir, name), yjvalid8r_lib.FormattingRules{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if rules.Tabs.Enabled != nil || rules.Indentation.Enabled != nil {
+			t.Errorf("%s: expected the tabs and indentation rules untouched, got: %+v", name, rules)
+		}
+		result := yjvalid8r_lib.CheckFormatting([]byte("spec:\n\tport: 80\n"), rules)
+		if len(result.Findings) != 1 || result.Findings[0].RuleID != yjvalid8r_lib.RuleWhitespaceTab {
+			t.Errorf("%s: expected a tabs finding, got: %+v", name, result.Findings)
+		}
+	}
+}
+
+func TestApplyEditorConfig_NestedBraces(t *testing.T) {
+	dir := t.TempDir()
+	writeSchemaFiles(t, dir, map[string]string{
+		".editorconfig": "root = true\n[{app,{db,cache}-*}.{json,{yaml,yml}}]\nindent_size = 4\n[{single}.yaml]\nindent_size = 8\n",
+	})
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{path: "app.json", want: 4},
+		{path: "db-main.yml", want: 4},
+		{path: "config/cache-1.yaml", want: 4},
+		{path: "web.yaml", want: 0},
+		{path: "app.xml", want: 0},
+		{path: "{single}.yaml", want: 8},
+		{path: "single.yaml", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rules, err := yjvalid8r_lib.ApplyEditorConfig(filepath.Join(dir, tt.path), yjvalid8r_lib.FormattingRules{})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if rules.IndentWidth != tt.want {
+				t.Errorf("Expected indent width %d, got %d", tt.want, rules.IndentWidth)
+			}
+		})
+	}
+}
+
+func TestApplyEditorConfig_SkipsUnparseableLines(t *testing.T) {
+	dir := t.TempDir()
+	writeSchemaFiles(t, dir, map[string]string{
+		".editorconfig": "root = true\nnot a property\n[*.yaml]\n<<<<<<< HEAD\nindent_size = 4\n[[*.json]\nindent_size = 8\n",
+	})
+
+	rules, err := yjvalid8r_lib.ApplyEditorConfig(filepath.Join(dir, "data.yaml"), yjvalid8r_lib.FormattingRules{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if rules.IndentWidth != 4 {
+		t.Errorf("Expected the parseable properties applied, got: %+v", rules)
+	}
+}
+
+func TestApplyEditorConfig_NoFile(t *testing.T) {
+	dir := t.TempDir()
+	writeSchemaFiles(t, dir, map[string]string{".editorconfig": "root = true\n"})
+
+	rules, err := yjvalid8r_lib.ApplyEditorConfig(filepath.Join(dir, "data.yaml"), yjvalid8r_lib.FormattingRules{IndentWidth: 3})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if rules != (yjvalid8r_lib.FormattingRules{IndentWidth: 3}) {
+		t.Errorf("Expected rules unchanged, got: %+v", rules)
+	}
+}
@@ -0,0 +1,151 @@
+package tests
+
+import (
+	"testing"
+
+	yjvalid8r_lib "github.com/sassoftware/yj-valid8r/yj-valid8r-lib"
+)
+
+func TestCheckTabsAndWhitespacesFinder_Findings(t *testing.T) {
+	result := yjvalid8r_lib.CheckTabsAndWhitespacesFinder([]byte("name: web\n  \tport: 80\nhost: a  \n"))
+
+	want := []yjvalid8r_lib.Finding{
+		{Severity: yjvalid8r_lib.MessageTypeError, RuleID: yjvalid8r_lib.RuleWhitespaceTab, Message: "Tab character found.", Line: 2, Column: 3, EndLine: 2, EndColumn: 4},
+		{Severity: yjvalid8r_lib.MessageTypeWarning, RuleID: yjvalid8r_lib.RuleWhitespaceTrailing, Message: "Trailing whitespace found.", Line: 3, Column: 8, EndLine: 3, EndColumn: 10},
+	}
+	if len(result.Findings) != len(want) {
+		t.Fatalf("Expected %d findings, got: %+v", len(want), result.Findings)
+	}
+	for i := range want {
+		if result.Findings[i] != want[i] {
+			t.Errorf("Finding %d: expected %+v, got %+v", i, want[i], result.Findings[i])
+		}
+	}
+}
+
+func TestCheckTabsAndWhitespacesFinder_FindingColumnsCountCharacters(t *testing.T) {
+	result := yjvalid8r_lib.CheckTabsAndWhitespacesFinder([]byte("é: x  \n"))
+
+	want := yjvalid8r_lib.Finding{Severity: yjvalid8r_lib.MessageTypeWarning, RuleID: yjvalid8r_lib.RuleWhitespaceTrailing, Message: "Trailing whitespace found.", Line: 1, Column: 5, EndLine: 1, EndColumn: 7}
+	if len(result.Findings) != 1 || result.Findings[0] != want {
+		t.Errorf("Expected finding %+v, got: %+v", want, result.Findings)
+	}
+}
+
+func TestRegexPatternRulesFinder_Findings(t *testing.T) {
+	t.Setenv("FINDINGS_SET", "yes")
+	rules := []yjvalid8r_lib.RegexPatternRules{{
+		Name:     "env",
+		Regex:    `\$\{(\w+)\}`,
+		CheckEnv: &yjvalid8r_lib.RegexPatternRulesCheckEnvConfig{Enabled: true, Strict: true},
+	}}
+
+	results, strictErr := yjvalid8r_lib.RegexPatternRulesFinder(rules, []byte("a: ${FINDINGS_SET}\nb: x-${FINDINGS_UNSET_VAR}\n"))
+	if !strictErr {
+		t.Error("Expected strict mode error")
+	}
+
+	findings := results[0].Findings
+	if len(findings) != 3 {
+		t.Fatalf("Expected 3 findings, got: %+v", findings)
+	}
+	if findings[0].RuleID != yjvalid8r_lib.RuleRegexMatch || findings[0].Line != 1 || findings[0].Column != 4 || findings[0].EndColumn != 19 {
+		t.Errorf("Unexpected match finding: %+v", findings[0])
+	}
+	missing := findings[2]
+	if missing.RuleID != yjvalid8r_lib.RuleRegexEnvMissing || missing.Severity != yjvalid8r_lib.MessageTypeError || missing.Line != 2 || missing.Column != 6 {
+		t.Errorf("Unexpected env finding: %+v", missing)
+	}
+}
+
+func TestValidateAgainstSchemaFinder_Findings(t *testing.T) {
+	schemaURL := writeTempSchemaFile(t, `{
+		"type": "object",
+		"properties": {
+			"name": { "type": "string" },
+			"ports": { "type": "array", "items": { "type": "integer" } }
+		}
+	}`)
+
+	results, err := yjvalid8r_lib.ValidateAgainstSchemaFinder(schemaURL, []byte("name: web\nports:\n  - 80\n  - \"http\"\n"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 message, got: %+v", results)
+	}
+
+	want := yjvalid8r_lib.Finding{
+		Severity:  yjvalid8r_lib.MessageTypeError,
+		RuleID:    "schema/invalid_type",
+		Message:   "Invalid type. Expected: integer, given: string",
+		Line:      4,
+		Column:    5,
+		EndLine:   4,
+		EndColumn: 11,
+		Pointer:   "/ports/1",
+	}
+	if results[0].Finding != want {
+		t.Errorf("Expected finding %+v, got %+v", want, results[0].Finding)
+	}
+}
+
+func TestValidateAgainstSchemaFinder_FindingPositions(t *testing.T) {
+	schemaURL := writeTempSchemaFile(t, `{
+		"type": "object",
+		"required": ["name"],
+		"properties": {
+			"metadata": {
+				"type": "object",
+				"required": ["namespace"],
+				"properties": {
+					"labels": {
+						"type": "object",
+						"additionalProperties": { "type": "string" }
+					}
+				}
+			},
+			"spec": {
+				"type": "object",
+				"properties": { "replicas": { "type": "integer" } },
+				"additionalProperties": false
+			}
+		}
+	}`)
+
+	data := "metadata:\n  labels:\n    app.kubernetes.io/name: 3\nspec:\n  replicas: 2\n  replica: 3\n"
+	results, err := yjvalid8r_lib.ValidateAgainstSchemaFinder(schemaURL, []byte(data))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		ruleID  string
+		line    int
+		column  int
+		pointer string
+	}{
+		// missing properties point at the key of the enclosing mapping, or the root
+		{ruleID: "schema/required", line: 1, column: 1, pointer: ""},
+		{ruleID: "schema/required", line: 1, column: 1, pointer: "/metadata"},
+		// keys containing dots and slashes are not split
+		{ruleID: "schema/invalid_type", line: 3, column: 29, pointer: "/metadata/labels/app.kubernetes.io~1name"},
+		// extra properties point at the offending key
+		{ruleID: "schema/additional_property_not_allowed", line: 6, column: 3, pointer: "/spec/replica"},
+	}
+	for _, tt := range tests {
+		found := false
+		for _, result := range results {
+			f := result.Finding
+			if f.RuleID == tt.ruleID && f.Pointer == tt.pointer {
+				found = true
+				if f.Line != tt.line || f.Column != tt.column {
+					t.Errorf("%s at %q: expected %d:%d, got %d:%d", tt.ruleID, tt.pointer, tt.line, tt.column, f.Line, f.Column)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("Expected %s finding at %q, got: %+v", tt.ruleID, tt.pointer, results)
+		}
+	}
+}
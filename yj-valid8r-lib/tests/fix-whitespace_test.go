@@ -0,0 +1,148 @@
+package tests
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	yjvalid8r_lib "github.com/sassoftware/yj-valid8r/yj-valid8r-lib"
+)
+
+func TestFixWhitespace(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		indentWidth  int
+		want         string
+		wantMessages int
+	}{
+		{
+			name:  "No Issues",
+			input: "key: value\nanother: line\n",
+			want:  "key: value\nanother: line\n",
+		},
+		{
+			name:         "Trailing Whitespace",
+			input:        "key: value \nanother: line\t\n",
+			want:         "key: value\nanother: line\n",
+			wantMessages: 2,
+		},
+		{
+			name:         "Leading Tabs",
+			input:        "spec:\n\tports:\n\t\t- 80\n  \tname: a\tb\n",
+			want:         "spec:\n  ports:\n    - 80\n    name: a\tb\n",
+			wantMessages: 3,
+		},
+		{
+			name:         "Indent Width",
+			input:        "spec:\n\tport: 80\n",
+			indentWidth:  4,
+			want:         "spec:\n    port: 80\n",
+			wantMessages: 1,
+		},
+		{
+			name:         "Final Newline",
+			input:        "key: value",
+			want:         "key: value\n",
+			wantMessages: 1,
+		},
+		{
+			name:         "CRLF Line Endings",
+			input:        "key: value  \r\nother: x",
+			want:         "key: value\r\nother: x\r\n",
+			wantMessages: 2,
+		},
+		{
+			name:  "Empty",
+			input: "",
+			want:  "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := yjvalid8r_lib.FixWhitespace([]byte(tt.input), yjvalid8r_lib.WhitespaceFixOptions{IndentWidth: tt.indentWidth})
+			if string(result.Data) != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, result.Data)
+			}
+			if result.Changed != (tt.input != tt.want) {
+				t.Errorf("Expected Changed=%v", tt.input != tt.want)
+			}
+			if len(result.Messages) != tt.wantMessages {
+				t.Errorf("Expected %d messages, got: %v", tt.wantMessages, result.Messages)
+			}
+			if fixed := yjvalid8r_lib.CheckTabsAndWhitespacesFinder(result.Data); len(fixed.Errors)+len(fixed.Warnings) != 0 {
+				t.Errorf("Expected no whitespace issues after fixing, got: %+v", fixed)
+			}
+		})
+	}
+}
+
+func TestFixWhitespace_KeepsBlockScalars(t *testing.T) {
+	input := "script: |\n  echo\ta \n  \tindented\n\n  last  \nfolded: >-\n  text \nkey: value \n"
+	want := "script: |\n  echo\ta \n  \tindented\n\n  last  \nfolded: >-\n  text \nkey: value\n"
+
+	result := yjvalid8r_lib.FixWhitespace([]byte(input), yjvalid8r_lib.WhitespaceFixOptions{})
+	if string(result.Data) != want {
+		t.Errorf("Expected %q, got %q", want, result.Data)
+	}
+	if len(result.Messages) != 1 || !strings.HasPrefix(result.Messages[0], "Line 8:") {
+		t.Errorf("Expected only line 8 to be fixed, got: %v", result.Messages)
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	oldData := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl"
+	newData := "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\n"
+
+	want := `--- data.yaml
++++ data.yaml (fixed)
+@@ -1,5 +1,5 @@
+ a
+-b
++B
+ c
+ d
+ e
+@@ -9,4 +9,4 @@
+ i
+ j
+ k
+-l
+\ No newline at end of file
++l
+`
+	if got := yjvalid8r_lib.UnifiedDiff("data.yaml", "data.yaml (fixed)", []byte(oldData), []byte(newData)); got != want {
+		t.Errorf("Unexpected diff:\n%s", got)
+	}
+
+	if got := yjvalid8r_lib.UnifiedDiff("a", "b", []byte("same\n"), []byte("same\n")); got != "" {
+		t.Errorf("Expected no diff for equal data, got:\n%s", got)
+	}
+
+	want = "--- a\n+++ b\n@@ -0,0 +1,2 @@\n+x: 1\n+y: 2\n"
+	if got := yjvalid8r_lib.UnifiedDiff("a", "b", nil, []byte("x: 1\ny: 2\n")); got != want {
+		t.Errorf("Unexpected diff:\n%s", got)
+	}
+}
+
+func TestUnifiedDiff_LargeRewrite(t *testing.T) {
+	var oldData, newData strings.Builder
+	for i := 0; i < 5000; i++ {
+		fmt.Fprintf(&oldData, "key%d: value %d  \n", i, i)
+		fmt.Fprintf(&newData, "key%d: value %d\n", i, i)
+	}
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	diff := yjvalid8r_lib.UnifiedDiff("a", "b", []byte(oldData.String()), []byte(newData.String()))
+	runtime.ReadMemStats(&after)
+
+	if got := strings.Count(diff, "\n-key"); got != 5000 {
+		t.Errorf("Expected 5000 removed lines, got %d", got)
+	}
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 64<<20 {
+		t.Errorf("Expected the diff to allocate less than 64MB, allocated %dMB", allocated>>20)
+	}
+}
@@ -0,0 +1,140 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	yjvalid8r_lib "github.com/sassoftware/yj-valid8r/yj-valid8r-lib"
+)
+
+func TestFormatData(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		opts     yjvalid8r_lib.FormatOptions
+		want     string
+		wantType string
+	}{
+		{
+			name:     "Formatted YAML is unchanged",
+			data:     "name: web\nports:\n  - 80\n",
+			want:     "name: web\nports:\n  - 80\n",
+			wantType: yjvalid8r_lib.DataTypeYAML,
+		},
+		{
+			name:     "Sorting keeps an anchor in front of its alias",
+			data:     "b: &x 1\na: *x\nc:\n  q: 1\n  p: 2\n",
+			opts:     yjvalid8r_lib.FormatOptions{SortKeys: true},
+			want:     "b: &x 1\na: *x\nc:\n  p: 2\n  q: 1\n",
+			wantType: yjvalid8r_lib.DataTypeYAML,
+		},
+		{
+			name:     "Sorting keeps a nested anchor in front of its alias",
+			data:     "d:\n  k: &x 1\na: *x\n",
+			opts:     yjvalid8r_lib.FormatOptions{SortKeys: true},
+			want:     "d:\n  k: &x 1\na: *x\n",
+			wantType: yjvalid8r_lib.DataTypeYAML,
+		},
+		{
+			name:     "Sorting moves anchors and aliases that stay in order",
+			data:     "b: 1\na: &x 2\nc: *x\n",
+			opts:     yjvalid8r_lib.FormatOptions{SortKeys: true},
+			want:     "a: &x 2\nb: 1\nc: *x\n",
+			wantType: yjvalid8r_lib.DataTypeYAML,
+		},
+		{
+			name:     "YAML flow collections, indent and comments",
+			data:     "# service\nname: web   # inline\nlabels: {app: web, tier: [a, b]}\nports:\n- 80\n",
+			opts:     yjvalid8r_lib.FormatOptions{IndentWidth: 4},
+			want:     "# service\nname: web # inline\nlabels:\n    app: web\n    tier:\n        - a\n        - b\nports:\n    - 80\n",
+			wantType: yjvalid8r_lib.DataTypeYAML,
+		},
+		{
+			name:     "YAML quoting",
+			data:     "a: 'web'\nb: \"yes\"\nc: '8080'\nd: \"tab\\there\"\ne: ''\n",
+			want:     "a: web\nb: \"yes\"\nc: \"8080\"\nd: \"tab\\there\"\ne: \"\"\n",
+			wantType: yjvalid8r_lib.DataTypeYAML,
+		},
+		{
+			name:     "YAML single quotes",
+			data:     "b: \"yes\"\nd: \"tab\\there\"\n",
+			opts:     yjvalid8r_lib.FormatOptions{QuoteStyle: yjvalid8r_lib.QuoteStyleSingle},
+			want:     "b: 'yes'\nd: \"tab\\there\"\n",
+			wantType: yjvalid8r_lib.DataTypeYAML,
+		},
+		{
+			name:     "YAML sorted keys keep comments, block scalars and merge keys",
+			data:     "---\nscript: |\n  echo hi\n# the base\nbase: &base\n  z: 1\n  a: 2\nmerged:\n  name: x\n  <<: *base\n",
+			opts:     yjvalid8r_lib.FormatOptions{SortKeys: true},
+			want:     "---\n# the base\nbase: &base\n  a: 2\n  z: 1\nmerged:\n  <<: *base\n  name: x\nscript: |\n  echo hi\n",
+			wantType: yjvalid8r_lib.DataTypeYAML,
+		},
+		{
+			name:     "YAML stream",
+			data:     "---\na: 1\n---\n---\nb: 2\n---\n",
+			want:     "---\na: 1\n---\nb: 2\n",
+			wantType: yjvalid8r_lib.DataTypeYAML,
+		},
+		{
+			name:     "JSON keeps key order",
+			data:     `{"b": [1, {"z": 2}], "a": "<&>", "n": 1.50}`,
+			want:     "{\n  \"b\": [\n    1,\n    {\n      \"z\": 2\n    }\n  ],\n  \"a\": \"<&>\",\n  \"n\": 1.50\n}\n",
+			wantType: yjvalid8r_lib.DataTypeJSON,
+		},
+		{
+			name:     "JSON sorted keys",
+			data:     `{"b": {"z": 2, "y": 1}, "a": "<&>", "n": 1.50}`,
+			opts:     yjvalid8r_lib.FormatOptions{IndentWidth: 4, SortKeys: true},
+			want:     "{\n    \"a\": \"<&>\",\n    \"b\": {\n        \"y\": 1,\n        \"z\": 2\n    },\n    \"n\": 1.50\n}\n",
+			wantType: yjvalid8r_lib.DataTypeJSON,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := yjvalid8r_lib.FormatData([]byte(tt.data), tt.opts)
+			if err != nil {
+				t.Fatalf("FormatData: %v", err)
+			}
+			if string(result.Data) != tt.want {
+				t.Errorf("Data =\n%s\nwant\n%s", result.Data, tt.want)
+			}
+			if result.Changed != (tt.data != tt.want) {
+				t.Errorf("Changed = %v", result.Changed)
+			}
+			if result.DataType != tt.wantType {
+				t.Errorf("DataType = %q, want %q", result.DataType, tt.wantType)
+			}
+
+			// formatting is idempotent
+			again, err := yjvalid8r_lib.FormatData(result.Data, tt.opts)
+			if err != nil || again.Changed {
+				t.Errorf("formatting the output again changed it:\n%s", again.Data)
+			}
+		})
+	}
+}
+
+func TestFormatData_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		opts    yjvalid8r_lib.FormatOptions
+		wantErr string
+	}{
+		{"Unknown quote style", "a: 1\n", yjvalid8r_lib.FormatOptions{QuoteStyle: "backtick"}, "unknown quoteStyle"},
+		{"Negative indent", "a: 1\n", yjvalid8r_lib.FormatOptions{IndentWidth: -1}, "must not be negative"},
+		{"Invalid data", "a: [1\n", yjvalid8r_lib.FormatOptions{}, "neither valid JSON nor YAML"},
+		{"TOML data", "a = 1\n", yjvalid8r_lib.FormatOptions{}, "formatting toml data is not supported"},
+		{"Sorting JSON with duplicate keys", `{"a": 1, "a": 2}`, yjvalid8r_lib.FormatOptions{SortKeys: true}, `cannot sort keys: Line 1: Duplicate key "a"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := yjvalid8r_lib.FormatData([]byte(tt.data), tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	yjvalid8r_lib "github.com/sassoftware/yj-valid8r/yj-valid8r-lib"
+)
+
+func TestDecodeJSONLines(t *testing.T) {
+	data := "{\"id\": 1}\r\n\n  [1, 2]\n{\"id\": 2, oops}\n\"text\"\n"
+
+	docs, findings := yjvalid8r_lib.DecodeJSONLines([]byte(data))
+
+	wantDocs := []struct{ index, line, column int }{{0, 1, 1}, {2, 3, 3}, {4, 5, 1}}
+	if len(docs) != len(wantDocs) {
+		t.Fatalf("Expected %d records, got %d", len(wantDocs), len(docs))
+	}
+	for i, want := range wantDocs {
+		root := docs[i].Root()
+		if docs[i].Index != want.index || root.Line != want.line || root.Column != want.column {
+			t.Errorf("Record %d: index %d at %d:%d, want %d at %d:%d", i, docs[i].Index, root.Line, root.Column, want.index, want.line, want.column)
+		}
+	}
+	if value := docs[1].Root().Content[1]; value.Line != 3 || value.Column != 7 {
+		t.Errorf("Expected the second array element at 3:7, got %d:%d", value.Line, value.Column)
+	}
+
+	if len(findings) != 1 {
+		t.Fatalf("Expected 1 finding, got: %+v", findings)
+	}
+	got := findings[0]
+	if got.RuleID != yjvalid8r_lib.RuleJSONLineSyntax || got.Document != 3 || got.Line != 4 || got.Column != 11 || !strings.Contains(got.Message, "invalid character 'o'") {
+		t.Errorf("Unexpected finding: %+v", got)
+	}
+}
+
+func TestDetectDataType_JSONLines(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"Records", "{\"id\": 1}\n{\"id\": 2}\n", yjvalid8r_lib.DataTypeNDJSON},
+		{"Single record is JSON", "{\"id\": 1}\n", yjvalid8r_lib.DataTypeJSON},
+		{"Invalid record", "{\"id\": 1}\n{\"id\": }\n", yjvalid8r_lib.DataTypeUNKNOWN},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := yjvalid8r_lib.DetectDataType([]byte(tt.data)); got != tt.want {
+				t.Errorf("DetectDataType() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckJSONLinesDuplicateKeysFinder(t *testing.T) {
+	data := "{\"a\": 1}\n{\"a\": 1, \"a\": 2}\nnot json\n{\"b\": {\"c\": 1, \"c\": 2}}\n"
+
+	result := yjvalid8r_lib.CheckJSONLinesDuplicateKeysFinder([]byte(data))
+
+	want := []struct {
+		document, line, column int
+		pointer                string
+	}{{1, 2, 10, "/a"}, {3, 4, 16, "/b/c"}}
+	if result.Valid || len(result.Findings) != len(want) {
+		t.Fatalf("Expected %d duplicate keys, got: %+v", len(want), result)
+	}
+	for i, w := range want {
+		got := result.Findings[i]
+		if got.Document != w.document || got.Line != w.line || got.Column != w.column || got.Pointer != w.pointer {
+			t.Errorf("Finding %d: %+v", i, got)
+		}
+	}
+
+	// detected JSON Lines data is checked the same way
+	if detected := yjvalid8r_lib.CheckDuplicateKeysFinder([]byte("{\"a\": 1}\n{\"a\": 1, \"a\": 2}\n")); len(detected.Findings) != 1 || detected.Findings[0].Line != 2 {
+		t.Errorf("Unexpected findings for detected JSON Lines: %+v", detected.Findings)
+	}
+}
+
+func TestJSONLinesConfig(t *testing.T) {
+	if limit := (yjvalid8r_lib.JSONLinesConfig{}).Limit(); limit != yjvalid8r_lib.DefaultJSONLinesMaxErrors {
+		t.Errorf("Limit() = %d, want the default", limit)
+	}
+	if limit := (yjvalid8r_lib.JSONLinesConfig{MaxErrors: 5}).Limit(); limit != 5 {
+		t.Errorf("Limit() = %d, want 5", limit)
+	}
+	if err := (yjvalid8r_lib.JSONLinesConfig{MaxErrors: -1}).Validate(); err == nil {
+		t.Error("Expected an error for a negative maxErrors")
+	}
+}
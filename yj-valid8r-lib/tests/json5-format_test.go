@@ -0,0 +1,79 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	yjvalid8r_lib "github.com/sassoftware/yj-valid8r/yj-valid8r-lib"
+)
+
+func TestDecodeDocuments_JSON5(t *testing.T) {
+	data := `// settings (JSONC)
+{
+  name: 'web',   /* unquoted key, single quotes */
+  "port": 0x1F,
+  ratio: .5,
+  limits: [+1, Infinity, NaN,],
+  note: "line \
+continued é",
+}
+`
+	docs, err := yjvalid8r_lib.DecodeDocuments([]byte(data))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	root := docs[0].Root()
+
+	want := []struct {
+		key, tag, value string
+		line, column    int
+	}{
+		{"name", "!!str", "web", 3, 9},
+		{"port", "!!int", "31", 4, 11},
+		{"ratio", "!!float", "0.5", 5, 10},
+		{"limits", "!!seq", "", 6, 11},
+		{"note", "!!str", "line continued é", 7, 9},
+	}
+	if len(root.Content) != 2*len(want) {
+		t.Fatalf("Expected %d keys, got %d", len(want), len(root.Content)/2)
+	}
+	for i, w := range want {
+		key, value := root.Content[2*i], root.Content[2*i+1]
+		if key.Value != w.key || value.Tag != w.tag || value.Value != w.value || value.Line != w.line || value.Column != w.column {
+			t.Errorf("%s: got %s %s %q at %d:%d", w.key, key.Value, value.Tag, value.Value, value.Line, value.Column)
+		}
+	}
+
+	var limits []string
+	for _, element := range root.Content[7].Content {
+		limits = append(limits, element.Value)
+	}
+	if got := strings.Join(limits, ","); got != "1,.inf,.nan" {
+		t.Errorf("Unexpected limits: %s", got)
+	}
+}
+
+func TestDecodeDocuments_JSON5DuplicateKeys(t *testing.T) {
+	result := yjvalid8r_lib.CheckDuplicateKeysFinder([]byte("{a: 1, // first\n a: 2}"))
+	if result.Valid || len(result.Findings) != 1 || result.Findings[0].Line != 2 {
+		t.Errorf("Expected a duplicate key on line 2, got: %+v", result)
+	}
+}
+
+func TestDetectDataType_JSON5Errors(t *testing.T) {
+	tests := []string{
+		"{a: 1,, b: 2}",
+		"{a: 01}",
+		"{a: 'unterminated}",
+		"{a: undefined}",
+		"/* unterminated {a: 1}",
+	}
+
+	for _, data := range tests {
+		t.Run(data, func(t *testing.T) {
+			if got := yjvalid8r_lib.DetectDataType([]byte(data)); got == yjvalid8r_lib.DataTypeJSON5 {
+				t.Errorf("DetectDataType() = %q", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package tests
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	yjvalid8r_lib "github.com/sassoftware/yj-valid8r/yj-valid8r-lib"
+)
+
+const jsonPathData = `
+name: shop
+services:
+  - name: web
+    port: 80
+    tags: [frontend, public]
+  - name: api
+    port: 8080
+    tags: [backend]
+  - name: db
+    port: 5432
+    internal: true
+limits: {cpu: 2, "memory.max": 4Gi}
+`
+
+func TestSearchPathsFinder_JSONPath(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		wantPaths []string
+		wantRaw   []string
+		wantLines []int
+	}{
+		{name: "member names", query: "$.services[0].name", wantPaths: []string{"$.services[0].name"}, wantRaw: []string{`"web"`}},
+		{name: "bracket names", query: "$['limits']['memory.max']", wantPaths: []string{"$.limits['memory.max']"}, wantRaw: []string{`"4Gi"`}},
+		{name: "wildcard", query: "$.services[*].port", wantPaths: []string{"$.services[0].port", "$.services[1].port", "$.services[2].port"}, wantRaw: []string{"80", "8080", "5432"}, wantLines: []int{5, 8, 11}},
+		{name: "descendants", query: "$..name", wantPaths: []string{"$.name", "$.services[0].name", "$.services[1].name", "$.services[2].name"}},
+		{name: "negative index", query: "$.services[-1].name", wantRaw: []string{`"db"`}},
+		{name: "union", query: "$.services[0,2].name", wantRaw: []string{`"web"`, `"db"`}},
+		{name: "slice", query: "$.services[1:].name", wantRaw: []string{`"api"`, `"db"`}},
+		{name: "reverse slice", query: "$.services[::-2].name", wantRaw: []string{`"db"`, `"web"`}},
+		{name: "slice with huge step", query: "$.services[1::9223372036854775807].name", wantRaw: []string{`"api"`}},
+		{name: "reverse slice with huge step", query: "$.services[::-9223372036854775808].name", wantRaw: []string{`"db"`}},
+		{name: "filter comparison", query: "$.services[?(@.port > 80)].name", wantRaw: []string{`"api"`, `"db"`}},
+		{name: "filter without parentheses", query: "$.services[?@.port >= 80 && @.port < 6000 && @.name != 'web'].name", wantRaw: []string{`"db"`}},
+		{name: "filter existence", query: "$.services[?@.internal].name", wantRaw: []string{`"db"`}},
+		{name: "filter negation", query: "$.services[?!@.internal].name", wantRaw: []string{`"web"`, `"api"`}},
+		{name: "filter functions", query: "$.services[?length(@.tags) == 2 || match(@.name, 'd.')].name", wantRaw: []string{`"web"`, `"db"`}},
+		{name: "filter search", query: "$.services[?search(@.name, 'p')].name", wantRaw: []string{`"api"`}},
+		{name: "filter on root", query: "$.services[?@.name == $.services[1].name].port", wantRaw: []string{"8080"}},
+		{name: "descendant filter", query: "$..[?@ == 'backend']", wantPaths: []string{"$.services[1].tags[0]"}},
+		{name: "no match", query: "$.missing.name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			paths := []yjvalid8r_lib.SearchPathsDef{{PathName: tt.name, PathKey: tt.query, Syntax: yjvalid8r_lib.SearchPathSyntaxJSONPath}}
+			outputs, err := yjvalid8r_lib.SearchPathsFinder([]byte(jsonPathData), paths)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(outputs[0].Errors) > 0 {
+				t.Fatalf("Unexpected errors: %v", outputs[0].Errors)
+			}
+			var gotPaths, gotRaw []string
+			var gotLines []int
+			for _, item := range outputs[0].Results {
+				gotPaths = append(gotPaths, item.FullPath)
+				gotRaw = append(gotRaw, item.Raw)
+				gotLines = append(gotLines, item.Line)
+			}
+			if tt.wantPaths != nil && !reflect.DeepEqual(gotPaths, tt.wantPaths) {
+				t.Errorf("Expected paths %v, got %v", tt.wantPaths, gotPaths)
+			}
+			if tt.wantRaw != nil && !reflect.DeepEqual(gotRaw, tt.wantRaw) {
+				t.Errorf("Expected values %v, got %v", tt.wantRaw, gotRaw)
+			}
+			if tt.wantLines != nil && !reflect.DeepEqual(gotLines, tt.wantLines) {
+				t.Errorf("Expected lines %v, got %v", tt.wantLines, gotLines)
+			}
+			if tt.wantPaths == nil && tt.wantRaw == nil && len(gotPaths) > 0 {
+				t.Errorf("Expected no results, got %v", gotPaths)
+			}
+		})
+	}
+}
+
+func TestSearchPathsFinder_JSONPathErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    yjvalid8r_lib.SearchPathsDef
+		wantErr string
+	}{
+		{name: "missing root", path: yjvalid8r_lib.SearchPathsDef{PathKey: "services[0]", Syntax: "jsonpath"}, wantErr: "query must start with $ at position 1"},
+		{name: "unclosed bracket", path: yjvalid8r_lib.SearchPathsDef{PathKey: "$.services[0", Syntax: "jsonpath"}, wantErr: "expected , or ] at position 13"},
+		{name: "bad filter", path: yjvalid8r_lib.SearchPathsDef{PathKey: "$.services[?@.port >]", Syntax: "jsonpath"}, wantErr: "expected a value"},
+		{name: "unknown function", path: yjvalid8r_lib.SearchPathsDef{PathKey: "$[?size(@) > 1]", Syntax: "jsonpath"}, wantErr: `unknown function or value "size"`},
+		{name: "uncompared length", path: yjvalid8r_lib.SearchPathsDef{PathKey: "$[?length(@)]", Syntax: "jsonpath"}, wantErr: "result of length() must be compared"},
+		{name: "invalid pattern", path: yjvalid8r_lib.SearchPathsDef{PathKey: "$[?match(@.name, '(')]", Syntax: "jsonpath"}, wantErr: "invalid pattern of match()"},
+		{name: "unknown syntax", path: yjvalid8r_lib.SearchPathsDef{PathKey: "/services", Syntax: "xpath"}, wantErr: `unknown syntax "xpath"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			paths := []yjvalid8r_lib.SearchPathsDef{tt.path, {PathName: "Name", PathKey: "name"}}
+			outputs, err := yjvalid8r_lib.SearchPathsFinder([]byte(jsonPathData), paths)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(outputs[0].Errors) != 1 || !strings.Contains(outputs[0].Errors[0], tt.wantErr) {
+				t.Errorf("Expected an error containing %q, got %v", tt.wantErr, outputs[0].Errors)
+			}
+			if len(outputs[0].Results) != 0 {
+				t.Errorf("Expected no results for an invalid path, got %v", outputs[0].Results)
+			}
+			// other paths are still searched, in the legacy syntax by default
+			if len(outputs[1].Results) != 1 || outputs[1].Results[0].Raw != `"shop"` {
+				t.Errorf("Expected the other path to be searched, got %+v", outputs[1])
+			}
+		})
+	}
+}
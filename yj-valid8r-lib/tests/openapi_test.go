@@ -0,0 +1,174 @@
+package tests
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	yjvalid8r_lib "github.com/sassoftware/yj-valid8r/yj-valid8r-lib"
+)
+
+const openAPI30Document = `openapi: 3.0.3
+info:
+  title: Services
+  version: 1.0.0
+paths: {}
+components:
+  schemas:
+    Service:
+      type: object
+      required: [name, backend]
+      properties:
+        name:
+          type: string
+        owner:
+          type: string
+          nullable: true
+        tier:
+          type: string
+          enum: [frontend, backend]
+          nullable: true
+        backend:
+          $ref: '#/components/schemas/Backend'
+    Backend:
+      oneOf:
+        - $ref: '#/components/schemas/Database'
+        - $ref: '#/components/schemas/Queue'
+      discriminator:
+        propertyName: kind
+        mapping:
+          db: '#/components/schemas/Database'
+    Database:
+      type: object
+      required: [kind, engine]
+      properties:
+        kind:
+          type: string
+        engine:
+          type: string
+    Queue:
+      type: object
+      required: [kind, topic]
+      properties:
+        kind:
+          type: string
+        topic:
+          type: string
+`
+
+func writeOpenAPIDocument(t *testing.T, content string) string {
+	t.Helper()
+	dir := t.TempDir()
+	writeSchemaFiles(t, dir, map[string]string{"openapi.yaml": content})
+	return filepath.Join(dir, "openapi.yaml")
+}
+
+func TestValidateAgainstSchemaFinder_OpenAPIComponent(t *testing.T) {
+	schemaURL := writeOpenAPIDocument(t, openAPI30Document) + "#/components/schemas/Service"
+
+	tests := []struct {
+		name      string
+		data      string
+		wantError string // prefix of one of the reported errors; empty for valid data
+	}{
+		{
+			name: "Valid with nulls",
+			data: "name: web\nowner: null\ntier: null\nbackend:\n  kind: db\n  engine: postgres\n",
+		},
+		{
+			name: "Implicit discriminator value",
+			data: "name: web\nbackend:\n  kind: Queue\n  topic: events\n",
+		},
+		{
+			name:      "Wrong type despite nullable",
+			data:      "name: web\nowner: 42\nbackend:\n  kind: db\n  engine: postgres\n",
+			wantError: "Line 2: owner:",
+		},
+		{
+			name:      "Discriminator selects the alternative",
+			data:      "name: web\nbackend:\n  kind: db\n  topic: events\n",
+			wantError: "Line 2: backend: engine is required",
+		},
+		{
+			name:      "Unknown discriminator value",
+			data:      "name: web\nbackend:\n  kind: cache\n",
+			wantError: "Line 3: backend.kind: backend.kind must be one of the following",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := yjvalid8r_lib.ValidateAgainstSchemaFinder(schemaURL, []byte(tt.data))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if tt.wantError == "" {
+				if len(results) != 0 {
+					t.Errorf("Expected no messages, got: %+v", results)
+				}
+				return
+			}
+
+			found := false
+			for _, result := range results {
+				if strings.HasPrefix(result.Message, tt.wantError) {
+					found = true
+				}
+				// only the alternative selected by the discriminator is reported
+				if strings.Contains(result.Message, "topic is required") {
+					t.Errorf("Unexpected error from the other alternative: %+v", result)
+				}
+			}
+			if !found {
+				t.Errorf("Expected an error starting with %q, got: %+v", tt.wantError, results)
+			}
+		})
+	}
+}
+
+func TestCompileSchema_OpenAPI31Component(t *testing.T) {
+	schemaURL := writeOpenAPIDocument(t, `openapi: 3.1.0
+info:
+  title: Services
+  version: 1.0.0
+components:
+  schemas:
+    Endpoint:
+      type: object
+      properties:
+        address:
+          type: array
+          prefixItems:
+            - type: string
+            - type: integer
+          items: false
+`) + "#/components/schemas/Endpoint"
+
+	schema, err := yjvalid8r_lib.CompileSchema(schemaURL, yjvalid8r_lib.SchemaOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if schema.Draft() != yjvalid8r_lib.SchemaDraft2020 {
+		t.Errorf("Expected OpenAPI 3.1 to use draft 2020-12, got %q", schema.Draft())
+	}
+	if !strings.HasSuffix(schema.Location(), "openapi.yaml#/components/schemas/Endpoint") {
+		t.Errorf("Expected location to keep the fragment, got %s", schema.Location())
+	}
+
+	results, err := schema.Validate([]byte("address:\n  - localhost\n  - eighty\n"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(results) != 1 || !strings.HasPrefix(results[0].Message, "Line 3: address.1:") {
+		t.Errorf("Expected an error for the second item, got: %+v", results)
+	}
+}
+
+func TestCompileSchema_MissingFragment(t *testing.T) {
+	schemaURL := writeOpenAPIDocument(t, openAPI30Document) + "#/components/schemas/Missing"
+
+	_, err := yjvalid8r_lib.CompileSchema(schemaURL, yjvalid8r_lib.SchemaOptions{})
+	if err == nil || !strings.Contains(err.Error(), "no schema at #/components/schemas/Missing") {
+		t.Errorf("Expected missing fragment error, got: %v", err)
+	}
+}
@@ -0,0 +1,139 @@
+package tests
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	yjvalid8r_lib "github.com/sassoftware/yj-valid8r/yj-valid8r-lib"
+)
+
+const cachedRootSchema = `{
+	"type": "object",
+	"properties": {
+		"name": { "type": "string" },
+		"port": { "$ref": "port.json" }
+	}
+}`
+
+const cachedPortSchema = `{ "type": "integer", "minimum": 1 }`
+
+func newSchemaServer(t *testing.T, hits *int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(hits, 1)
+		switch r.URL.Path {
+		case "/root.json":
+			w.Write([]byte(cachedRootSchema))
+		case "/port.json":
+			w.Write([]byte(cachedPortSchema))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestSchemaCache_FetchUsesCache(t *testing.T) {
+	var hits int32
+	server := newSchemaServer(t, &hits)
+	cache, err := yjvalid8r_lib.NewSchemaCache(yjvalid8r_lib.SchemaCacheConfig{Dir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		data, err := cache.Fetch(server.URL + "/root.json")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if string(data) != cachedRootSchema {
+			t.Fatalf("Unexpected schema content: %s", data)
+		}
+	}
+	if hits != 1 {
+		t.Errorf("Expected 1 request to the schema server, got %d", hits)
+	}
+}
+
+func TestSchemaCache_Offline(t *testing.T) {
+	var hits int32
+	server := newSchemaServer(t, &hits)
+	dir := t.TempDir()
+
+	offline, err := yjvalid8r_lib.NewSchemaCache(yjvalid8r_lib.SchemaCacheConfig{Dir: dir, Offline: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := offline.Fetch(server.URL + "/root.json"); err == nil || !strings.Contains(err.Error(), "offline") {
+		t.Errorf("Expected offline error for uncached schema, got: %v", err)
+	}
+	if hits != 0 {
+		t.Errorf("Expected no requests in offline mode, got %d", hits)
+	}
+
+	// Warm the cache online, then validate offline with the server gone.
+	online, err := yjvalid8r_lib.NewSchemaCache(yjvalid8r_lib.SchemaCacheConfig{Dir: dir, TTL: "1h"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := yjvalid8r_lib.CompileSchema(server.URL+"/root.json", yjvalid8r_lib.SchemaOptions{Cache: online}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	server.Close()
+
+	schema, err := yjvalid8r_lib.CompileSchema(server.URL+"/root.json", yjvalid8r_lib.SchemaOptions{Cache: offline})
+	if err != nil {
+		t.Fatalf("Unexpected error in offline mode: %v", err)
+	}
+	results, err := schema.Validate([]byte("name: web\nport: 0\n"))
+	if err != nil {
+		t.Fatalf("Unexpected error in offline mode: %v", err)
+	}
+	if len(results) != 1 || !strings.Contains(results[0].Message, "port") {
+		t.Errorf("Expected a single port error from the cached $ref schema, got: %+v", results)
+	}
+}
+
+func TestSchemaCache_Pins(t *testing.T) {
+	var hits int32
+	server := newSchemaServer(t, &hits)
+	sum := sha256.Sum256([]byte(cachedPortSchema))
+
+	tests := []struct {
+		name    string
+		pin     string
+		wantErr bool
+	}{
+		{name: "Matching pin", pin: "sha256:" + hex.EncodeToString(sum[:]), wantErr: false},
+		{name: "Mismatching pin", pin: strings.Repeat("0", 64), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache, err := yjvalid8r_lib.NewSchemaCache(yjvalid8r_lib.SchemaCacheConfig{
+				Dir:  t.TempDir(),
+				Pins: map[string]string{server.URL + "/port.json": tt.pin},
+			})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			_, err = cache.Fetch(server.URL + "/port.json")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Fetch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewSchemaCache_InvalidTTL(t *testing.T) {
+	_, err := yjvalid8r_lib.NewSchemaCache(yjvalid8r_lib.SchemaCacheConfig{Dir: t.TempDir(), TTL: "soon"})
+	if err == nil {
+		t.Error("Expected error for invalid TTL")
+	}
+}
@@ -0,0 +1,169 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	yjvalid8r_lib "github.com/sassoftware/yj-valid8r/yj-valid8r-lib"
+)
+
+const draft2020Schema = `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"type": "object",
+	"$defs": {
+		"port": { "type": "integer", "minimum": 1 }
+	},
+	"properties": {
+		"name": { "type": "string" },
+		"port": { "$ref": "#/$defs/port" },
+		"endpoint": {
+			"type": "array",
+			"prefixItems": [ { "type": "string" }, { "$ref": "#/$defs/port" } ],
+			"items": false
+		}
+	},
+	"required": ["name"],
+	"unevaluatedProperties": false
+}`
+
+func TestCompileSchema_DetectsDraft(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		want   yjvalid8r_lib.SchemaDraft
+	}{
+		{"2020-12", draft2020Schema, yjvalid8r_lib.SchemaDraft2020},
+		{"2019-09", `{"$schema": "https://json-schema.org/draft/2019-09/schema", "type": "object"}`, yjvalid8r_lib.SchemaDraft2019},
+		{"draft-07", `{"$schema": "http://json-schema.org/draft-07/schema#", "type": "object"}`, yjvalid8r_lib.SchemaDraft7},
+		{"draft-04", `{"$schema": "http://json-schema.org/draft-04/schema#", "type": "object"}`, yjvalid8r_lib.SchemaDraft4},
+		{"No $schema", `{"type": "object"}`, yjvalid8r_lib.SchemaDraftAuto},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := yjvalid8r_lib.CompileSchema(writeTempSchemaFile(t, tt.schema), yjvalid8r_lib.SchemaOptions{})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if schema.Draft() != tt.want {
+				t.Errorf("Expected draft %q, got %q", tt.want, schema.Draft())
+			}
+		})
+	}
+}
+
+func TestValidateAgainstSchemaFinder_Draft2020(t *testing.T) {
+	schemaURL := writeTempSchemaFile(t, draft2020Schema)
+
+	tests := []struct {
+		name       string
+		data       string
+		wantErrors []string
+	}{
+		{
+			name: "Valid",
+			data: "name: web\nport: 80\nendpoint: [localhost, 8080]\n",
+		},
+		{
+			name:       "Unevaluated property",
+			data:       "name: web\nextra: true\n",
+			wantErrors: []string{"Line 2: extra: value is not allowed here"},
+		},
+		{
+			name:       "$defs reference",
+			data:       "name: web\nport: 0\n",
+			wantErrors: []string{"Line 2: port:"},
+		},
+		{
+			name:       "prefixItems",
+			data:       "name: web\nendpoint:\n  - localhost\n  - http\n",
+			wantErrors: []string{"Line 4: endpoint.1:"},
+		},
+		{
+			name:       "Missing required",
+			data:       "port: 80\n",
+			wantErrors: []string{"Line 1: (root): missing property 'name'"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := yjvalid8r_lib.ValidateAgainstSchemaFinder(schemaURL, []byte(tt.data))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(results) != len(tt.wantErrors) {
+				t.Fatalf("Expected %d messages, got: %+v", len(tt.wantErrors), results)
+			}
+			for i, want := range tt.wantErrors {
+				if results[i].Type != yjvalid8r_lib.MessageTypeError || !strings.HasPrefix(results[i].Message, want) {
+					t.Errorf("Expected error starting with %q, got: %+v", want, results[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCompileSchema_ForcedDraft(t *testing.T) {
+	// Without "$schema", unevaluatedProperties is only honoured when 2020-12 is forced.
+	schemaURL := writeTempSchemaFile(t, `{
+		"type": "object",
+		"properties": { "name": { "type": "string" } },
+		"unevaluatedProperties": false
+	}`)
+	data := []byte("name: web\nextra: true\n")
+
+	auto, err := yjvalid8r_lib.CompileSchema(schemaURL, yjvalid8r_lib.SchemaOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if results, _ := auto.Validate(data); len(results) != 0 {
+		t.Errorf("Expected no messages without forced draft, got: %+v", results)
+	}
+
+	forced, err := yjvalid8r_lib.CompileSchema(schemaURL, yjvalid8r_lib.SchemaOptions{Draft: yjvalid8r_lib.SchemaDraft2020})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if forced.Draft() != yjvalid8r_lib.SchemaDraft2020 {
+		t.Errorf("Expected forced draft 2020-12, got %q", forced.Draft())
+	}
+	if results, _ := forced.Validate(data); len(results) != 1 {
+		t.Errorf("Expected 1 message with forced 2020-12, got: %+v", results)
+	}
+}
+
+func TestParseSchemaDraft(t *testing.T) {
+	for _, name := range []string{"", "auto", "draft-04", "draft-06", "draft-07", "2019-09", "2020-12"} {
+		if _, err := yjvalid8r_lib.ParseSchemaDraft(name); err != nil {
+			t.Errorf("ParseSchemaDraft(%q): unexpected error: %v", name, err)
+		}
+	}
+	if _, err := yjvalid8r_lib.ParseSchemaDraft("draft-03"); err == nil {
+		t.Error("Expected error for unsupported draft")
+	}
+}
+
+func TestValidateAgainstSchemaFinder_Draft2020EveryAdditionalProperty(t *testing.T) {
+	schemaURL := writeTempSchemaFile(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"properties": {
+			"service": { "type": "object", "properties": { "name": { "type": "string" } }, "additionalProperties": false }
+		}
+	}`)
+
+	results, err := yjvalid8r_lib.ValidateAgainstSchemaFinder(schemaURL, []byte("service:\n  name: web\n  extra: true\n  other: 1\n"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := []string{"Line 3: service: additional properties 'extra' not allowed", "Line 4: service: additional properties 'other' not allowed"}
+	if len(results) != len(want) {
+		t.Fatalf("Expected %d messages, got: %+v", len(want), results)
+	}
+	for i := range want {
+		if results[i].Message != want[i] || results[i].Finding.RuleID != "schema/additionalProperties" {
+			t.Errorf("Expected %q (schema/additionalProperties), got: %+v", want[i], results[i])
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package tests
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	yjvalid8r_lib "github.com/sassoftware/yj-valid8r/yj-valid8r-lib"
+)
+
+var inferenceSamples = [][]byte{
+	[]byte("name: web\nenv: prod\nreplicas: 2\nports:\n  - 80\n  - 443\nowner: alice\n"),
+	[]byte("name: api\nenv: prod\nreplicas: 1.5\nports: []\n---\nname: db\nenv: dev\nreplicas: 1\nports: [5432]\nowner: null\n"),
+	[]byte(`{"name": "cache", "env": "dev", "replicas": 3, "ports": [6379], "debug": true}`),
+}
+
+func TestInferSchema(t *testing.T) {
+	schema, err := yjvalid8r_lib.InferSchema(inferenceSamples, yjvalid8r_lib.SchemaInferenceOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	jsonBytes, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var got map[string]interface{}
+	if err := json.Unmarshal(jsonBytes, &got); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var want map[string]interface{}
+	if err := json.Unmarshal([]byte(`{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"type": "object",
+		"required": ["env", "name", "ports", "replicas"],
+		"properties": {
+			"name": { "type": "string" },
+			"env": { "type": "string", "enum": ["dev", "prod"] },
+			"replicas": { "type": "number" },
+			"ports": { "type": "array", "items": { "type": "integer" } },
+			"owner": { "type": ["string", "null"] },
+			"debug": { "type": "boolean" }
+		}
+	}`), &want); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Unexpected schema:\n%s", jsonBytes)
+	}
+}
+
+func TestInferSchema_ValidatesSamples(t *testing.T) {
+	schema, err := yjvalid8r_lib.InferSchema(inferenceSamples, yjvalid8r_lib.SchemaInferenceOptions{Draft: yjvalid8r_lib.SchemaDraft2020})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if schema["$schema"] != "https://json-schema.org/draft/2020-12/schema" {
+		t.Errorf("Expected a 2020-12 schema, got %v", schema["$schema"])
+	}
+
+	schemaBytes, _ := json.Marshal(schema)
+	schemaURL := writeTempSchemaFile(t, string(schemaBytes))
+	for i, sample := range inferenceSamples {
+		results, err := yjvalid8r_lib.ValidateAgainstSchemaFinder(schemaURL, sample)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(results) != 0 {
+			t.Errorf("Sample %d: expected no messages, got: %+v", i, results)
+		}
+	}
+}
+
+func TestInferSchema_ArrayRootAndEnums(t *testing.T) {
+	samples := [][]byte{[]byte("- role: admin\n  id: 1\n- role: user\n  id: 2\n- role: user\n  id: 3\n")}
+
+	schema, err := yjvalid8r_lib.InferSchema(samples, yjvalid8r_lib.SchemaInferenceOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if schema["type"] != "array" {
+		t.Fatalf("Expected an array schema, got: %v", schema)
+	}
+	items := schema["items"].(map[string]interface{})
+	role := items["properties"].(map[string]interface{})["role"].(map[string]interface{})
+	if !reflect.DeepEqual(role["enum"], []interface{}{"admin", "user"}) {
+		t.Errorf("Expected role enum, got: %v", role)
+	}
+
+	schema, err = yjvalid8r_lib.InferSchema(samples, yjvalid8r_lib.SchemaInferenceOptions{MaxEnumValues: -1})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	items = schema["items"].(map[string]interface{})
+	if _, ok := items["properties"].(map[string]interface{})["role"].(map[string]interface{})["enum"]; ok {
+		t.Errorf("Expected no enum when enums are disabled, got: %v", items)
+	}
+}
+
+func TestInferSchema_Errors(t *testing.T) {
+	_, err := yjvalid8r_lib.InferSchema(nil, yjvalid8r_lib.SchemaInferenceOptions{Draft: yjvalid8r_lib.SchemaDraft4})
+	if err == nil || !strings.Contains(err.Error(), "draft-07 and 2020-12") {
+		t.Errorf("Expected unsupported draft error, got: %v", err)
+	}
+
+	_, err = yjvalid8r_lib.InferSchema([][]byte{[]byte("a: 1\n"), []byte("a: [\n")}, yjvalid8r_lib.SchemaInferenceOptions{})
+	if err == nil || !strings.HasPrefix(err.Error(), "sample 1:") {
+		t.Errorf("Expected parse error for sample 1, got: %v", err)
+	}
+}
@@ -0,0 +1,122 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	yjvalid8r_lib "github.com/sassoftware/yj-valid8r/yj-valid8r-lib"
+)
+
+const yamlServiceSchema = `type: object
+properties:
+  name:
+    type: string
+  port:
+    $ref: port.yaml
+  responses:
+    type: object
+    properties:
+      200:
+        type: string
+required:
+  - name
+`
+
+const yamlPortSchema = `type: integer
+minimum: 1
+`
+
+func TestCompileSchema_YAMLFile(t *testing.T) {
+	dir := t.TempDir()
+	writeSchemaFiles(t, dir, map[string]string{
+		"service.yaml": yamlServiceSchema,
+		"port.yaml":    yamlPortSchema,
+	})
+
+	schema, err := yjvalid8r_lib.CompileSchema(filepath.Join(dir, "service.yaml"), yjvalid8r_lib.SchemaOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, ok := schema.Properties()["port"]; !ok {
+		t.Errorf("Expected top-level properties from the YAML schema, got: %v", schema.Properties())
+	}
+
+	results, err := schema.Validate([]byte("name: web\nport: 0\nresponses:\n  \"200\": 42\n"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var messages []string
+	for _, result := range results {
+		messages = append(messages, result.Message)
+	}
+	sort.Strings(messages)
+	if len(messages) != 2 || !strings.HasPrefix(messages[0], "Line 2: port:") || !strings.HasPrefix(messages[1], "Line 4: responses.200:") {
+		t.Errorf("Expected errors for port and responses.200, got: %+v", results)
+	}
+}
+
+func TestCompileSchema_YAMLURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/service.yaml":
+			w.Write([]byte(yamlServiceSchema))
+		case "/port.yaml":
+			w.Write([]byte(yamlPortSchema))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	results, err := yjvalid8r_lib.ValidateAgainstSchemaFinder(server.URL+"/service.yaml", []byte(`{"port": 8080}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(results) != 1 || !strings.Contains(results[0].Message, "name is required") {
+		t.Errorf("Expected a missing name error, got: %+v", results)
+	}
+}
+
+func TestCompileSchema_MalformedSchemaPosition(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		schema  string
+		wantErr string
+	}{
+		{
+			name:    "JSON",
+			file:    "schema.json",
+			schema:  "{\n  \"type\": \"object\",\n  \"properties\": {,\n}",
+			wantErr: "parse schema JSON: line 3, column 18",
+		},
+		{
+			name:    "YAML",
+			file:    "schema.yaml",
+			schema:  "type: object\nproperties:\n  name:\n\ttype: string\n",
+			wantErr: "parse schema YAML: document 0: yaml: line 4:",
+		},
+		{
+			name:    "YAML scalar",
+			file:    "schema.yaml",
+			schema:  "just a string\n",
+			wantErr: "line 1, column 1: schema must be a mapping",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeSchemaFiles(t, dir, map[string]string{tt.file: tt.schema})
+
+			_, err := yjvalid8r_lib.CompileSchema(filepath.Join(dir, tt.file), yjvalid8r_lib.SchemaOptions{})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package tests
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	yjvalid8r_lib "github.com/sassoftware/yj-valid8r/yj-valid8r-lib"
+)
+
+func TestSchemaRegistry_SharesCompiledSchemas(t *testing.T) {
+	var hits int32
+	server := newSchemaServer(t, &hits)
+	registry := yjvalid8r_lib.NewSchemaRegistry(yjvalid8r_lib.SchemaOptions{})
+
+	var wg sync.WaitGroup
+	schemas := make([]*yjvalid8r_lib.CompiledSchema, 8)
+	for i := range schemas {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			schema, err := registry.Get(server.URL + "/root.json")
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+				return
+			}
+			schemas[i] = schema
+		}(i)
+	}
+	wg.Wait()
+
+	for i, schema := range schemas {
+		if schema != schemas[0] {
+			t.Errorf("registry returned a different schema instance for call %d", i)
+		}
+	}
+	if got := atomic.LoadInt32(&hits); got != 2 {
+		t.Errorf("Expected 2 requests to the schema server, got %d", got)
+	}
+}
+
+func TestSchemaRegistry_RetriesFailedSchemas(t *testing.T) {
+	schemaPath := filepath.Join(t.TempDir(), "late.json")
+	registry := yjvalid8r_lib.NewSchemaRegistry(yjvalid8r_lib.SchemaOptions{})
+
+	if _, err := registry.Get(schemaPath); err == nil {
+		t.Fatal("Expected error for missing schema")
+	}
+
+	if err := os.WriteFile(schemaPath, []byte(`{"type": "object"}`), 0644); err != nil {
+		t.Fatalf("Failed to write schema: %v", err)
+	}
+	if _, err := registry.Get(schemaPath); err != nil {
+		t.Errorf("Expected schema to compile once it exists, got: %v", err)
+	}
+}
+
+func TestSchemaRegistry_EvictsLeastRecentlyUsed(t *testing.T) {
+	dir := t.TempDir()
+	paths := make([]string, 3)
+	for i := range paths {
+		paths[i] = filepath.Join(dir, fmt.Sprintf("schema%d.json", i))
+		if err := os.WriteFile(paths[i], []byte(`{"type": "object"}`), 0644); err != nil {
+			t.Fatalf("Failed to write schema: %v", err)
+		}
+	}
+	registry := yjvalid8r_lib.NewSchemaRegistry(yjvalid8r_lib.SchemaOptions{MaxSchemas: 2})
+
+	get := func(path string) *yjvalid8r_lib.CompiledSchema {
+		schema, err := registry.Get(path)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return schema
+	}
+	first := get(paths[0])
+	second := get(paths[1])
+	get(paths[0]) // schema1 is now the least recently used
+	get(paths[2])
+
+	if got := registry.Len(); got != 2 {
+		t.Errorf("Expected the registry to hold 2 schemas, got %d", got)
+	}
+	if get(paths[0]) != first {
+		t.Error("Expected the recently used schema to be kept")
+	}
+	if get(paths[1]) == second {
+		t.Error("Expected the least recently used schema to be dropped and recompiled")
+	}
+}
@@ -0,0 +1,163 @@
+package tests
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	yjvalid8r_lib "github.com/sassoftware/yj-valid8r/yj-valid8r-lib"
+)
+
+func writeSchemaFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create schema dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write schema: %v", err)
+		}
+	}
+}
+
+func TestPrefixSchemaResolver(t *testing.T) {
+	resolver := yjvalid8r_lib.NewPrefixSchemaResolver([]yjvalid8r_lib.SchemaMapping{
+		{Prefix: "https://example.com/", Dir: "/catalog/all"},
+		{Prefix: "https://example.com/schemas/", Dir: "/catalog/schemas"},
+	})
+
+	tests := []struct {
+		name      string
+		location  string
+		want      string
+		wantFound bool
+		wantErr   bool
+	}{
+		{name: "Longest prefix wins", location: "https://example.com/schemas/v1/service.json", want: "file:///catalog/schemas/v1/service.json", wantFound: true},
+		{name: "Shorter prefix", location: "https://example.com/other.json", want: "file:///catalog/all/other.json", wantFound: true},
+		{name: "Unmapped URL", location: "https://other.example.com/service.json", wantFound: false},
+		{name: "Dot segments inside the directory", location: "https://example.com/schemas/v1/../v2/service.json", want: "file:///catalog/schemas/v2/service.json", wantFound: true},
+		{name: "Dot segments leaving the directory", location: "https://example.com/schemas/../../etc/passwd", wantFound: true, wantErr: true},
+		{name: "Escaped dot segments", location: "https://example.com/schemas/%2e%2e/%2e%2e/etc/passwd", wantFound: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found, err := resolver.ResolveSchema(tt.location)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveSchema() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if found != tt.wantFound {
+				t.Fatalf("ResolveSchema() found = %v, want %v", found, tt.wantFound)
+			}
+			if found && !tt.wantErr && filepath.ToSlash(got) != tt.want {
+				t.Errorf("ResolveSchema() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompileSchema_MappedRemoteRefs(t *testing.T) {
+	schemaDir := t.TempDir()
+	catalogDir := t.TempDir()
+
+	writeSchemaFiles(t, schemaDir, map[string]string{
+		"service.json": `{
+			"type": "object",
+			"properties": {
+				"port": { "$ref": "https://schemas.example.invalid/common/types.json#/definitions/port" }
+			}
+		}`,
+	})
+	writeSchemaFiles(t, catalogDir, map[string]string{
+		"common/types.json":  `{ "definitions": { "port": { "$ref": "number.json" } } }`,
+		"common/number.json": `{ "type": "integer", "maximum": 65535 }`,
+	})
+
+	schema, err := yjvalid8r_lib.CompileSchema(filepath.Join(schemaDir, "service.json"), yjvalid8r_lib.SchemaOptions{
+		Resolver: yjvalid8r_lib.NewPrefixSchemaResolver([]yjvalid8r_lib.SchemaMapping{
+			{Prefix: "https://schemas.example.invalid/", Dir: catalogDir},
+		}),
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	results, err := schema.Validate([]byte("port: 70000\n"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(results) != 1 || !strings.Contains(results[0].Message, "port") {
+		t.Errorf("Expected a single port error, got: %+v", results)
+	}
+}
+
+func TestCompileSchema_RelativeRefsIndependentOfWorkingDir(t *testing.T) {
+	schemaDir := t.TempDir()
+	writeSchemaFiles(t, schemaDir, map[string]string{
+		"root.json":          `{ "type": "object", "properties": { "name": { "$ref": "defs/name.json" } } }`,
+		"defs/name.json":     `{ "$ref": "../strings/short.json" }`,
+		"strings/short.json": `{ "type": "string", "maxLength": 3 }`,
+	})
+
+	t.Chdir(t.TempDir())
+
+	schema, err := yjvalid8r_lib.CompileSchema("file://"+filepath.ToSlash(filepath.Join(schemaDir, "root.json")), yjvalid8r_lib.SchemaOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	results, err := schema.Validate([]byte("name: toolong\n"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(results) != 1 {
+		t.Errorf("Expected a single maxLength error, got: %+v", results)
+	}
+}
+
+func TestCompileSchema_UnresolvedRefChain(t *testing.T) {
+	schemaDir := t.TempDir()
+	writeSchemaFiles(t, schemaDir, map[string]string{
+		"root.json": `{ "properties": { "a": { "$ref": "a.json" } } }`,
+		"a.json":    `{ "items": { "$ref": "missing.json" } }`,
+	})
+
+	_, err := yjvalid8r_lib.CompileSchema(filepath.Join(schemaDir, "root.json"), yjvalid8r_lib.SchemaOptions{})
+
+	var refErr *yjvalid8r_lib.UnresolvedRefError
+	if !errors.As(err, &refErr) {
+		t.Fatalf("Expected UnresolvedRefError, got: %v", err)
+	}
+	if len(refErr.Chain) != 3 {
+		t.Fatalf("Expected a chain of 3 schemas, got: %v", refErr.Chain)
+	}
+	for i, name := range []string{"root.json", "a.json", "missing.json"} {
+		if !strings.HasSuffix(refErr.Chain[i], name) {
+			t.Errorf("chain[%d] = %s, want suffix %s", i, refErr.Chain[i], name)
+		}
+	}
+}
+
+func TestCompileSchema_RefsUnderPropertiesNamedLikeKeywords(t *testing.T) {
+	for _, name := range []string{"default", "enum", "const", "examples"} {
+		t.Run(name, func(t *testing.T) {
+			schemaDir := t.TempDir()
+			writeSchemaFiles(t, schemaDir, map[string]string{
+				"root.json": `{ "properties": { "` + name + `": { "$ref": "missing.json" } }, "default": { "$ref": "literal.json" } }`,
+			})
+
+			_, err := yjvalid8r_lib.CompileSchema(filepath.Join(schemaDir, "root.json"), yjvalid8r_lib.SchemaOptions{})
+
+			var refErr *yjvalid8r_lib.UnresolvedRefError
+			if !errors.As(err, &refErr) {
+				t.Fatalf("Expected UnresolvedRefError, got: %v", err)
+			}
+			if len(refErr.Chain) != 2 || !strings.HasSuffix(refErr.Chain[1], "missing.json") {
+				t.Errorf("Expected the chain to end at missing.json, got: %v", refErr.Chain)
+			}
+		})
+	}
+}
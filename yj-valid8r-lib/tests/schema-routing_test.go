@@ -0,0 +1,113 @@
+package tests
+
+import (
+	"testing"
+
+	yjvalid8r_lib "github.com/sassoftware/yj-valid8r/yj-valid8r-lib"
+)
+
+func TestSelectSchema(t *testing.T) {
+	data := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  labels:
+    tier: backend
+---
+apiVersion: v1
+kind: Service
+---
+apiVersion: example.com/v1alpha1
+kind: Widget
+`
+	docs, err := yjvalid8r_lib.DecodeDocuments([]byte(data))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		routes      []yjvalid8r_lib.SchemaRoute
+		doc         int
+		wantSchema  string
+		wantMatched bool
+		wantErr     bool
+	}{
+		{
+			name: "Kubeconform style template",
+			routes: []yjvalid8r_lib.SchemaRoute{
+				{Name: "k8s", APIVersion: "*", Kind: "*", Schema: "https://schemas.example.com/{{.Group}}/{{.Kind}}_{{.Version}}.json"},
+			},
+			doc:         0,
+			wantSchema:  "https://schemas.example.com/apps/Deployment_v1.json",
+			wantMatched: true,
+		},
+		{
+			name: "Core group with lower-cased kind",
+			routes: []yjvalid8r_lib.SchemaRoute{
+				{Name: "core", APIVersion: "v1", Schema: "schemas/{{lower .Kind}}-{{.Version}}.json"},
+			},
+			doc:         1,
+			wantSchema:  "schemas/service-v1.json",
+			wantMatched: true,
+		},
+		{
+			name: "First matching route wins",
+			routes: []yjvalid8r_lib.SchemaRoute{
+				{Name: "apps", APIVersion: "apps/*", Schema: "apps.json"},
+				{Name: "any", Schema: "any.json"},
+			},
+			doc:         0,
+			wantSchema:  "apps.json",
+			wantMatched: true,
+		},
+		{
+			name: "JSON pointer value selector",
+			routes: []yjvalid8r_lib.SchemaRoute{
+				{Name: "frontend", Pointer: "/metadata/labels/tier", Value: "frontend", Schema: "frontend.json"},
+				{Name: "tier", Pointer: "/metadata/labels/tier", Schema: "{{.Value}}.json"},
+			},
+			doc:         0,
+			wantSchema:  "backend.json",
+			wantMatched: true,
+		},
+		{
+			name: "No route matches",
+			routes: []yjvalid8r_lib.SchemaRoute{
+				{Name: "deployments", Kind: "Deployment", Schema: "deployment.json"},
+			},
+			doc:         2,
+			wantMatched: false,
+		},
+		{
+			name: "Invalid template",
+			routes: []yjvalid8r_lib.SchemaRoute{
+				{Name: "broken", Schema: "{{.Missing}}.json"},
+			},
+			doc:     0,
+			wantErr: true,
+		},
+		{
+			name: "Invalid pointer",
+			routes: []yjvalid8r_lib.SchemaRoute{
+				{Name: "broken", Pointer: "metadata", Schema: "schema.json"},
+			},
+			doc:     0,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, matched, err := yjvalid8r_lib.SelectSchema(tt.routes, docs[tt.doc])
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SelectSchema() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if matched != tt.wantMatched {
+				t.Errorf("SelectSchema() matched = %v, want %v", matched, tt.wantMatched)
+			}
+			if schema != tt.wantSchema {
+				t.Errorf("SelectSchema() = %q, want %q", schema, tt.wantSchema)
+			}
+		})
+	}
+}
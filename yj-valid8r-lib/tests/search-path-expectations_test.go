@@ -0,0 +1,246 @@
+package tests
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	yjvalid8r_lib "github.com/sassoftware/yj-valid8r/yj-valid8r-lib"
+)
+
+const expectationData = `
+service:
+  name: web
+  port: 99999
+  replicas: 2.5
+  env: staging
+  tags: [frontend, public]
+  debug: true
+  released: 2020-01-01
+`
+
+func intPtr(i int) *int { return &i }
+
+func floatPtr(f float64) *float64 { return &f }
+
+func TestSearchPathsFinder_Expectations(t *testing.T) {
+	tests := []struct {
+		name       string
+		pathKey    string
+		expect     yjvalid8r_lib.SearchPathExpectation
+		wantErrors []string
+		wantRule   string
+	}{
+		{name: "required, found", pathKey: "service.name", expect: yjvalid8r_lib.SearchPathExpectation{Required: true}},
+		{
+			name: "required, missing", pathKey: "service.owner", expect: yjvalid8r_lib.SearchPathExpectation{Required: true},
+			wantErrors: []string{"Line 2: service.owner: required, but not found"}, wantRule: yjvalid8r_lib.RuleSearchPathRequired,
+		},
+		{
+			name: "forbidden", pathKey: "service.debug", expect: yjvalid8r_lib.SearchPathExpectation{Forbidden: true},
+			wantErrors: []string{"Line 8: service.debug: forbidden, but found"}, wantRule: yjvalid8r_lib.RuleSearchPathForbidden,
+		},
+		{
+			name: "min count", pathKey: "service.tags[]", expect: yjvalid8r_lib.SearchPathExpectation{MinCount: intPtr(3)},
+			wantErrors: []string{"Line 2: service.tags[]: found 2 value(s), expected at least 3"}, wantRule: yjvalid8r_lib.RuleSearchPathCount,
+		},
+		{
+			name: "max count reported at the first value too many", pathKey: "service.tags[]", expect: yjvalid8r_lib.SearchPathExpectation{MaxCount: intPtr(1)},
+			wantErrors: []string{"Line 7: service.tags[]: found 2 value(s), expected at most 1"}, wantRule: yjvalid8r_lib.RuleSearchPathCount,
+		},
+		{name: "equals", pathKey: "service.name", expect: yjvalid8r_lib.SearchPathExpectation{Equals: "web"}},
+		{
+			name: "equals, different", pathKey: "service.env", expect: yjvalid8r_lib.SearchPathExpectation{Equals: "production"},
+			wantErrors: []string{`Line 6: service.env: "staging" does not equal "production"`}, wantRule: yjvalid8r_lib.RuleSearchPathValue,
+		},
+		{
+			name: "one of", pathKey: "service.env", expect: yjvalid8r_lib.SearchPathExpectation{OneOf: []interface{}{"dev", "production"}},
+			wantErrors: []string{`Line 6: service.env: "staging" is not one of ["dev","production"]`}, wantRule: yjvalid8r_lib.RuleSearchPathValue,
+		},
+		{
+			name: "pattern", pathKey: "service.tags[]", expect: yjvalid8r_lib.SearchPathExpectation{Pattern: "^front"},
+			wantErrors: []string{`Line 7: service.tags[1]: "public" does not match "^front"`}, wantRule: yjvalid8r_lib.RuleSearchPathPattern,
+		},
+		{
+			name: "maximum", pathKey: "service.port", expect: yjvalid8r_lib.SearchPathExpectation{Minimum: floatPtr(1), Maximum: floatPtr(65535)},
+			wantErrors: []string{"Line 4: service.port: 99999 is greater than the maximum 65535"}, wantRule: yjvalid8r_lib.RuleSearchPathRange,
+		},
+		{
+			name: "range of a string", pathKey: "service.env", expect: yjvalid8r_lib.SearchPathExpectation{Minimum: floatPtr(0)},
+			wantErrors: []string{`Line 6: service.env: "staging" is not a number`}, wantRule: yjvalid8r_lib.RuleSearchPathRange,
+		},
+		{name: "integer is a number", pathKey: "service.port", expect: yjvalid8r_lib.SearchPathExpectation{Type: "number"}},
+		{
+			name: "type", pathKey: "service.replicas", expect: yjvalid8r_lib.SearchPathExpectation{Type: "integer"},
+			wantErrors: []string{"Line 5: service.replicas: 2.5 is a number, expected an integer"}, wantRule: yjvalid8r_lib.RuleSearchPathType,
+		},
+		{
+			name: "type of a collection", pathKey: "service.tags", expect: yjvalid8r_lib.SearchPathExpectation{Type: "object"},
+			wantErrors: []string{`Line 7: service.tags: ["frontend","public"] is an array, expected an object`}, wantRule: yjvalid8r_lib.RuleSearchPathType,
+		},
+		{name: "timestamp is a string", pathKey: "service.released", expect: yjvalid8r_lib.SearchPathExpectation{Type: "string"}},
+		{
+			name: "type of a timestamp", pathKey: "service.released", expect: yjvalid8r_lib.SearchPathExpectation{Type: "integer"},
+			wantErrors: []string{`Line 9: service.released: "2020-01-01T00:00:00Z" is a string, expected an integer`}, wantRule: yjvalid8r_lib.RuleSearchPathType,
+		},
+		{
+			name: "jsonpath", pathKey: "$.service[?@ == 'staging']", expect: yjvalid8r_lib.SearchPathExpectation{Forbidden: true},
+			wantErrors: []string{"Line 6: $.service.env: forbidden, but found"}, wantRule: yjvalid8r_lib.RuleSearchPathForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := yjvalid8r_lib.SearchPathsDef{PathName: tt.name, PathKey: tt.pathKey, Expect: &tt.expect}
+			if strings.HasPrefix(tt.pathKey, "$") {
+				path.Syntax = yjvalid8r_lib.SearchPathSyntaxJSONPath
+			}
+			outputs, err := yjvalid8r_lib.SearchPathsFinder([]byte(expectationData), []yjvalid8r_lib.SearchPathsDef{path})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			output := outputs[0]
+			if !reflect.DeepEqual(output.Errors, tt.wantErrors) {
+				t.Errorf("errors = %q, want %q", output.Errors, tt.wantErrors)
+			}
+			if len(output.Findings) != len(tt.wantErrors) {
+				t.Fatalf("got %d findings, want %d", len(output.Findings), len(tt.wantErrors))
+			}
+			for _, finding := range output.Findings {
+				if finding.RuleID != tt.wantRule || finding.Severity != yjvalid8r_lib.MessageTypeError {
+					t.Errorf("finding = %+v, want rule %s of severity error", finding, tt.wantRule)
+				}
+			}
+		})
+	}
+}
+
+func TestSearchPathsFinder_ExpectationWarning(t *testing.T) {
+	path := yjvalid8r_lib.SearchPathsDef{
+		PathName: "env",
+		PathKey:  "service.env",
+		Expect:   &yjvalid8r_lib.SearchPathExpectation{Severity: yjvalid8r_lib.MessageTypeWarning, Equals: "production"},
+	}
+	outputs, err := yjvalid8r_lib.SearchPathsFinder([]byte(expectationData), []yjvalid8r_lib.SearchPathsDef{path})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	output := outputs[0]
+	if len(output.Errors) != 0 {
+		t.Errorf("unexpected errors: %q", output.Errors)
+	}
+	want := []string{`Line 6: service.env: "staging" does not equal "production"`}
+	if !reflect.DeepEqual(output.Warnings, want) {
+		t.Errorf("warnings = %q, want %q", output.Warnings, want)
+	}
+	if len(output.Findings) != 1 || output.Findings[0].Severity != yjvalid8r_lib.MessageTypeWarning || output.Findings[0].Column != 8 {
+		t.Errorf("findings = %+v, want one warning at column 8", output.Findings)
+	}
+	if len(output.Results) != 1 {
+		t.Errorf("results = %+v, want the value still reported", output.Results)
+	}
+}
+
+func TestSearchPathsFinder_ExpectationPerDocument(t *testing.T) {
+	data := `db:
+  port: 5432
+---
+cache:
+  port: 6379
+---
+db:
+  port: 5433
+`
+	path := yjvalid8r_lib.SearchPathsDef{
+		PathName: "db port",
+		PathKey:  "db.port",
+		Expect:   &yjvalid8r_lib.SearchPathExpectation{Required: true, MaxCount: intPtr(1)},
+	}
+	outputs, err := yjvalid8r_lib.SearchPathsFinder([]byte(data), []yjvalid8r_lib.SearchPathsDef{path})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	output := outputs[0]
+	if len(output.Results) != 2 {
+		t.Errorf("got %d results, want 2", len(output.Results))
+	}
+	want := []string{"Line 4: db.port: required, but not found"}
+	if !reflect.DeepEqual(output.Errors, want) {
+		t.Errorf("errors = %q, want %q", output.Errors, want)
+	}
+	if len(output.Findings) != 1 || output.Findings[0].Document != 1 {
+		t.Errorf("findings = %+v, want one finding in document 1", output.Findings)
+	}
+}
+
+func TestSearchPathsFinder_ExpectationEmptyData(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       string
+		expect     yjvalid8r_lib.SearchPathExpectation
+		wantErrors []string
+	}{
+		{name: "required, no data", data: "", expect: yjvalid8r_lib.SearchPathExpectation{Required: true}, wantErrors: []string{"Line 1: db.port: required, but not found"}},
+		{name: "required, empty document", data: "---\n", expect: yjvalid8r_lib.SearchPathExpectation{Required: true}, wantErrors: []string{"Line 1: db.port: required, but not found"}},
+		{name: "min count, empty document", data: "---\n", expect: yjvalid8r_lib.SearchPathExpectation{MinCount: intPtr(1)}, wantErrors: []string{"Line 1: db.port: found 0 value(s), expected at least 1"}},
+		{name: "optional, no data", data: "", expect: yjvalid8r_lib.SearchPathExpectation{MaxCount: intPtr(1)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := yjvalid8r_lib.SearchPathsDef{PathName: "db port", PathKey: "db.port", Expect: &tt.expect}
+			outputs, err := yjvalid8r_lib.SearchPathsFinder([]byte(tt.data), []yjvalid8r_lib.SearchPathsDef{path})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			output := outputs[0]
+			if !reflect.DeepEqual(output.Errors, tt.wantErrors) {
+				t.Errorf("errors = %q, want %q", output.Errors, tt.wantErrors)
+			}
+			if len(output.Findings) != len(tt.wantErrors) {
+				t.Fatalf("got %d findings, want %d", len(output.Findings), len(tt.wantErrors))
+			}
+			for _, finding := range output.Findings {
+				if finding.Line != 1 || finding.Column != 1 || finding.Document != 0 {
+					t.Errorf("finding = %+v, want it at line 1, column 1 of document 0", finding)
+				}
+			}
+		})
+	}
+}
+
+func TestSearchPathsFinder_InvalidExpectation(t *testing.T) {
+	tests := []struct {
+		name    string
+		expect  yjvalid8r_lib.SearchPathExpectation
+		wantErr string
+	}{
+		{name: "severity", expect: yjvalid8r_lib.SearchPathExpectation{Severity: "fatal"}, wantErr: `unknown severity "fatal"`},
+		{name: "required and forbidden", expect: yjvalid8r_lib.SearchPathExpectation{Required: true, Forbidden: true}, wantErr: "required and forbidden exclude each other"},
+		{name: "negative count", expect: yjvalid8r_lib.SearchPathExpectation{MinCount: intPtr(-1)}, wantErr: "must not be negative"},
+		{name: "counts", expect: yjvalid8r_lib.SearchPathExpectation{MinCount: intPtr(3), MaxCount: intPtr(2)}, wantErr: "minCount 3 is greater than maxCount 2"},
+		{name: "range", expect: yjvalid8r_lib.SearchPathExpectation{Minimum: floatPtr(3), Maximum: floatPtr(2)}, wantErr: "minimum 3 is greater than maximum 2"},
+		{name: "type", expect: yjvalid8r_lib.SearchPathExpectation{Type: "date"}, wantErr: `unknown type "date"`},
+		{name: "pattern", expect: yjvalid8r_lib.SearchPathExpectation{Pattern: "("}, wantErr: "invalid pattern"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := yjvalid8r_lib.SearchPathsDef{PathName: tt.name, PathKey: "service.name", Expect: &tt.expect}
+			outputs, err := yjvalid8r_lib.SearchPathsFinder([]byte(expectationData), []yjvalid8r_lib.SearchPathsDef{path})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			output := outputs[0]
+			if len(output.Errors) != 1 || !strings.HasPrefix(output.Errors[0], "Invalid search path: expect: ") || !strings.Contains(output.Errors[0], tt.wantErr) {
+				t.Errorf("errors = %q, want one containing %q", output.Errors, tt.wantErr)
+			}
+			if len(output.Results) != 0 {
+				t.Errorf("results = %+v, want none for an invalid path", output.Results)
+			}
+			if len(output.Findings) != 1 || output.Findings[0].RuleID != yjvalid8r_lib.RuleSearchPathInvalid {
+				t.Errorf("findings = %+v, want one %s finding", output.Findings, yjvalid8r_lib.RuleSearchPathInvalid)
+			}
+		})
+	}
+}
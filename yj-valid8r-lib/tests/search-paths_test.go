@@ -34,14 +34,14 @@ user:
 					PathName: "User Name",
 					PathKey:  "user.name",
 					Results: []yjvalid8r_lib.SearchPathsOutputResultItem{
-						{FullPath: "user.name", Raw: `"John Doe"`},
+						{FullPath: "user.name", Raw: `"John Doe"`, Line: 3, Column: 9},
 					},
 				},
 				{
 					PathName: "First Email",
 					PathKey:  "user.emails[0]",
 					Results: []yjvalid8r_lib.SearchPathsOutputResultItem{
-						{FullPath: "user.emails[0]", Raw: `"john@example.com"`},
+						{FullPath: "user.emails[0]", Raw: `"john@example.com"`, Line: 6, Column: 7},
 					},
 				},
 			},
@@ -64,8 +64,92 @@ employees:
 					PathName: "Employee Roles",
 					PathKey:  "employees[].role",
 					Results: []yjvalid8r_lib.SearchPathsOutputResultItem{
-						{FullPath: "employees[0].role", Raw: `"Developer"`},
-						{FullPath: "employees[1].role", Raw: `"Manager"`},
+						{FullPath: "employees[0].role", Raw: `"Developer"`, Line: 4, Column: 11},
+						{FullPath: "employees[1].role", Raw: `"Manager"`, Line: 6, Column: 11},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Multiple documents",
+			input: `
+kind: Service
+metadata:
+  name: web
+---
+kind: Deployment
+metadata:
+  name: api
+`,
+			paths: []yjvalid8r_lib.SearchPathsDef{
+				{PathName: "Names", PathKey: "metadata.name"},
+			},
+			wantOutput: []yjvalid8r_lib.SearchPathsOutput{
+				{
+					PathName: "Names",
+					PathKey:  "metadata.name",
+					Results: []yjvalid8r_lib.SearchPathsOutputResultItem{
+						{FullPath: "metadata.name", Raw: `"web"`, Document: 0, Line: 4, Column: 9},
+						{FullPath: "metadata.name", Raw: `"api"`, Document: 1, Line: 8, Column: 9},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Recursive search in document order",
+			input: `
+zeta:
+  name: z
+alpha:
+  name: a
+mid:
+  - name: m
+`,
+			paths: []yjvalid8r_lib.SearchPathsDef{
+				{PathName: "Names", PathKey: "name"},
+			},
+			wantOutput: []yjvalid8r_lib.SearchPathsOutput{
+				{
+					PathName: "Names",
+					PathKey:  "name",
+					Results: []yjvalid8r_lib.SearchPathsOutputResultItem{
+						{FullPath: "zeta.name", Raw: `"z"`, Line: 3, Column: 9},
+						{FullPath: "alpha.name", Raw: `"a"`, Line: 5, Column: 9},
+						{FullPath: "mid[0].name", Raw: `"m"`, Line: 7, Column: 11},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Aliases and merge keys",
+			input: `
+base: &base
+  port: 80
+web:
+  <<: *base
+  name: web
+api: *base
+`,
+			paths: []yjvalid8r_lib.SearchPathsDef{
+				{PathName: "Web Port", PathKey: "web.port"},
+				{PathName: "API", PathKey: "api"},
+			},
+			wantOutput: []yjvalid8r_lib.SearchPathsOutput{
+				{
+					PathName: "Web Port",
+					PathKey:  "web.port",
+					Results: []yjvalid8r_lib.SearchPathsOutputResultItem{
+						{FullPath: "web.port", Raw: "80", Line: 3, Column: 9},
+					},
+				},
+				{
+					PathName: "API",
+					PathKey:  "api",
+					Results: []yjvalid8r_lib.SearchPathsOutputResultItem{
+						{FullPath: "api", Raw: "{\n  \"port\": 80\n}", Line: 7, Column: 6},
 					},
 				},
 			},
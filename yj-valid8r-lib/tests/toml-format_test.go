@@ -0,0 +1,101 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	yjvalid8r_lib "github.com/sassoftware/yj-valid8r/yj-valid8r-lib"
+)
+
+const tomlData = `# service
+name = "web"
+port = 0
+
+[owner]
+email = "dev@example.com"
+
+[[replicas]]
+zone = "a"
+
+[[replicas]]
+zone = 1
+`
+
+func TestDecodeDocuments_TOML(t *testing.T) {
+	docs, err := yjvalid8r_lib.DecodeDocuments([]byte(tomlData))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(docs) != 1 {
+		t.Fatalf("Expected 1 document, got %d", len(docs))
+	}
+
+	root := docs[0].Root()
+	var keys []string
+	for i := 0; i < len(root.Content); i += 2 {
+		keys = append(keys, root.Content[i].Value)
+	}
+	if got := strings.Join(keys, ","); got != "name,port,owner,replicas" {
+		t.Errorf("Expected keys in data order, got %s", got)
+	}
+
+	port := root.Content[3]
+	if port.Tag != "!!int" || port.Value != "0" || port.Line != 3 || port.Column != 8 {
+		t.Errorf("Unexpected port node: %s %q at %d:%d", port.Tag, port.Value, port.Line, port.Column)
+	}
+	secondZone := root.Content[7].Content[1].Content[0]
+	if secondZone.Value != "zone" || secondZone.Line != 12 || secondZone.Column != 1 {
+		t.Errorf("Unexpected zone key of the second replica: %q at %d:%d", secondZone.Value, secondZone.Line, secondZone.Column)
+	}
+}
+
+func TestValidateAgainstSchemaFinder_TOML(t *testing.T) {
+	schemaURL := writeTempSchemaFile(t, `{
+		"type": "object",
+		"properties": {
+			"port": { "type": "integer", "minimum": 1 },
+			"replicas": { "type": "array", "items": { "properties": { "zone": { "type": "string" } } } }
+		}
+	}`)
+	schema, err := yjvalid8r_lib.CompileSchema(schemaURL, yjvalid8r_lib.SchemaOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	messages, err := schema.Validate([]byte(tomlData))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := map[string]int{"/port": 3, "/replicas/1/zone": 12}
+	if len(messages) != len(want) {
+		t.Fatalf("Expected %d messages, got: %+v", len(want), messages)
+	}
+	for _, msg := range messages {
+		if line, ok := want[msg.Finding.Pointer]; !ok || msg.Finding.Line != line {
+			t.Errorf("Unexpected finding: %+v", msg.Finding)
+		}
+	}
+}
+
+func TestDecodeDocuments_TOMLErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{"Key defined twice", "a = 1\na = 2\n", "toml: line 2, column 1"},
+		{"Table defined twice", "[a]\nb = 1\n[a]\nc = 2\n", "toml: line 3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := yjvalid8r_lib.DetectDataType([]byte(tt.data)); got != yjvalid8r_lib.DataTypeTOML {
+				t.Fatalf("DetectDataType() = %q, want toml", got)
+			}
+			_, err := yjvalid8r_lib.DecodeDocuments([]byte(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
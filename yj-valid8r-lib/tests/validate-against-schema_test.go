@@ -80,6 +80,41 @@ age: thirty
 	}
 }
 
+func TestValidateAgainstSchemaFinder_MultipleDocuments(t *testing.T) {
+	schema := `{
+		"type": "object",
+		"properties": {
+			"name": { "type": "string" },
+			"age": { "type": "number" }
+		},
+		"required": ["name", "age"]
+	}`
+
+	data := `name: John Doe
+age: 30
+---
+name: Jane Doe
+age: thirty
+`
+
+	schemaURL := writeTempSchemaFile(t, schema)
+
+	results, err := yjvalid8r_lib.ValidateAgainstSchemaFinder(schemaURL, []byte(data))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(results) != 1 {
+		t.Fatalf("Expected exactly one validation message, got: %+v", results)
+	}
+	if results[0].Document != 1 {
+		t.Errorf("Expected error in document 1, got document %d", results[0].Document)
+	}
+	if !strings.HasPrefix(results[0].Message, "Line 5:") {
+		t.Errorf("Expected error on line 5 of the stream, got: %s", results[0].Message)
+	}
+}
+
 func TestValidateAgainstSchemaFinder_InvalidYAML(t *testing.T) {
 	schema := `{
 		"type": "object",
@@ -110,3 +145,84 @@ func TestValidateAgainstSchemaFinder_MissingSchema(t *testing.T) {
 		t.Errorf("Expected missing schema error, got: %v", err)
 	}
 }
+
+func TestValidateAgainstSchemaFinder_ArrayRoot(t *testing.T) {
+	schemaURL := writeTempSchemaFile(t, `{
+		"type": "array",
+		"items": {
+			"type": "object",
+			"properties": {
+				"name": { "type": "string" },
+				"age": { "type": "number" }
+			},
+			"required": ["name"]
+		}
+	}`)
+
+	tests := []struct {
+		name         string
+		data         string
+		wantMessages []string // message prefixes, in order
+	}{
+		{
+			name: "Valid YAML list",
+			data: "- name: John Doe\n  age: 30\n- name: Jane Doe\n",
+		},
+		{
+			name: "Valid JSON array",
+			data: `[{"name": "John Doe"}, {"name": "Jane Doe", "age": 28}]`,
+		},
+		{
+			name:         "Invalid item",
+			data:         "- name: John Doe\n- name: Jane Doe\n  age: thirty\n",
+			wantMessages: []string{"Line 3: 1.age:"},
+		},
+		{
+			name:         "Unrelated items",
+			data:         "- host: a\n- host: b\n",
+			wantMessages: []string{"schema appears irrelevant", "Line 1: 0:", "Line 2: 1:"},
+		},
+		{
+			name:         "Scalar root",
+			data:         "just a string\n",
+			wantMessages: []string{"Line 1: (root): Invalid type. Expected: array, given: string"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := yjvalid8r_lib.ValidateAgainstSchemaFinder(schemaURL, []byte(tt.data))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(results) != len(tt.wantMessages) {
+				t.Fatalf("Expected %d messages, got: %+v", len(tt.wantMessages), results)
+			}
+			for i, want := range tt.wantMessages {
+				if !strings.HasPrefix(results[i].Message, want) {
+					t.Errorf("Message %d: expected prefix %q, got %q", i, want, results[i].Message)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateAgainstSchemaFinder_TupleItems(t *testing.T) {
+	schemaURL := writeTempSchemaFile(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "array",
+		"prefixItems": [
+			{ "type": "object", "properties": { "kind": { "type": "string" } } }
+		],
+		"items": { "type": "object", "properties": { "port": { "type": "integer" } } }
+	}`)
+
+	// only the second item matches, through "items"
+	results, err := yjvalid8r_lib.ValidateAgainstSchemaFinder(schemaURL, []byte("- host: a\n- port: 80\n"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, msg := range results {
+		t.Errorf("Expected no validation messages, got: %+v", msg)
+	}
+}
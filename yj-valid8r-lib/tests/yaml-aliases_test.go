@@ -0,0 +1,161 @@
+package tests
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	yjvalid8r_lib "github.com/sassoftware/yj-valid8r/yj-valid8r-lib"
+)
+
+func TestValidateAgainstSchemaFinder_AliasesAndMergeKeys(t *testing.T) {
+	schema := `{
+		"type": "object",
+		"properties": {
+			"servers": {
+				"type": "array",
+				"items": {
+					"type": "object",
+					"required": ["name"],
+					"properties": {
+						"port": { "type": "integer" },
+						"settings": { "type": "object", "additionalProperties": false, "properties": { "port": {} } }
+					}
+				}
+			}
+		}
+	}`
+
+	data := `defaults: &defaults
+  port: "80"
+  host: web
+servers:
+  - name: a
+    <<: *defaults
+  - name: b
+    port: 80
+    settings: *defaults
+  - <<: [*defaults]
+    port: 8080
+`
+
+	results, err := yjvalid8r_lib.ValidateAgainstSchemaFinder(writeTempSchemaFile(t, schema), []byte(data))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := []struct {
+		pointer    string
+		line       int
+		anchorLine int
+	}{
+		{pointer: "/servers/0/port", line: 6, anchorLine: 2},          // merged value, reported at the merge alias
+		{pointer: "/servers/1/settings/host", line: 9, anchorLine: 3}, // property not allowed, through an alias
+		{pointer: "/servers/2", line: 10, anchorLine: 0},              // missing in the document itself
+	}
+	if len(results) != len(want) {
+		t.Fatalf("Expected %d messages, got: %+v", len(want), results)
+	}
+	for _, w := range want {
+		found := false
+		for _, msg := range results {
+			if msg.Finding.Pointer != w.pointer {
+				continue
+			}
+			found = true
+			if msg.Finding.Line != w.line || msg.Finding.AnchorLine != w.anchorLine {
+				t.Errorf("%s: expected line %d and anchor line %d, got: %+v", w.pointer, w.line, w.anchorLine, msg.Finding)
+			}
+			if !strings.HasPrefix(msg.Message, fmt.Sprintf("Line %d: ", w.line)) {
+				t.Errorf("%s: expected the message to start with its line, got: %s", w.pointer, msg.Message)
+			}
+			if w.anchorLine > 0 && !strings.Contains(msg.Message, fmt.Sprintf("(value from anchor &defaults at line %d)", w.anchorLine)) {
+				t.Errorf("%s: expected the anchor definition in the message, got: %s", w.pointer, msg.Message)
+			}
+		}
+		if !found {
+			t.Errorf("Expected a message at %s, got: %+v", w.pointer, results)
+		}
+	}
+}
+
+// billionLaughs returns a document of the given depth whose every level holds ten aliases of the previous one.
+func billionLaughs(depth int) string {
+	var sb strings.Builder
+	sb.WriteString("l0: &l0 [lol, lol, lol, lol, lol, lol, lol, lol, lol, lol]\n")
+	for i := 1; i < depth; i++ {
+		aliases := strings.TrimSuffix(strings.Repeat(fmt.Sprintf("*l%d, ", i-1), 10), ", ")
+		fmt.Fprintf(&sb, "l%d: &l%d [%s]\n", i, i, aliases)
+	}
+	return sb.String()
+}
+
+func TestCheckAliasExpansion(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		limit   int
+		wantErr string
+	}{
+		{name: "no aliases", data: "a: 1\nb: [1, 2]\n"},
+		{name: "shared block", data: "base: &base {a: 1, b: 2}\nx:\n  <<: *base\ny: *base\n"},
+		{name: "billion laughs", data: billionLaughs(9), wantErr: "document 0: aliases expand to more than 100000 nodes"},
+		{name: "custom limit", data: "base: &base {a: 1, b: 2}\nx: *base\ny: *base\n", limit: 5, wantErr: "more than 5 nodes"},
+		{name: "within custom limit", data: "base: &base {a: 1, b: 2}\nx: *base\n", limit: 5},
+		{name: "no limit", data: billionLaughs(6), limit: -1},
+		{name: "second document", data: "a: 1\n---\n" + billionLaughs(9), wantErr: "document 1:"},
+		{name: "anchor containing itself", data: "a: &a [1, *a]\n", wantErr: "line 1: anchor &a contains an alias to itself"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			docs, err := yjvalid8r_lib.DecodeDocuments([]byte(tt.data))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			err = yjvalid8r_lib.CheckAliasExpansion(docs, tt.limit)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestSearchPathsFinder_RejectsUnboundedAliases(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		path    yjvalid8r_lib.SearchPathsDef
+		wantErr string
+	}{
+		{name: "anchor containing itself, legacy", data: "a: &a [*a]\n", path: yjvalid8r_lib.SearchPathsDef{PathKey: "b"}, wantErr: "anchor &a contains an alias to itself"},
+		{name: "anchor containing itself, jsonpath", data: "a: &a [*a]\n", path: yjvalid8r_lib.SearchPathsDef{PathKey: "$..b", Syntax: yjvalid8r_lib.SearchPathSyntaxJSONPath}, wantErr: "anchor &a contains an alias to itself"},
+		{name: "billion laughs, legacy", data: billionLaughs(9), path: yjvalid8r_lib.SearchPathsDef{PathKey: "missing"}, wantErr: "aliases expand to more than 100000 nodes"},
+		{name: "billion laughs, jsonpath", data: billionLaughs(9), path: yjvalid8r_lib.SearchPathsDef{PathKey: "$..*", Syntax: yjvalid8r_lib.SearchPathSyntaxJSONPath}, wantErr: "aliases expand to more than 100000 nodes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			done := make(chan error, 1)
+			go func() {
+				_, err := yjvalid8r_lib.SearchPathsFinder([]byte(tt.data), []yjvalid8r_lib.SearchPathsDef{tt.path})
+				done <- err
+			}()
+			select {
+			case err := <-done:
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Expected error containing %q, got: %v", tt.wantErr, err)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("SearchPathsFinder did not return")
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	yjvalid8r_lib "github.com/sassoftware/yj-valid8r/yj-valid8r-lib"
+)
+
+type lintPosition struct {
+	ruleID string
+	line   int
+	column int
+}
+
+func lintPositions(result yjvalid8r_lib.YAMLLintResult) []lintPosition {
+	var positions []lintPosition
+	for _, finding := range result.Findings {
+		positions = append(positions, lintPosition{finding.RuleID, finding.Line, finding.Column})
+	}
+	return positions
+}
+
+func TestLintYAML(t *testing.T) {
+	enabled := true
+	disabled := false
+
+	tests := []struct {
+		name   string
+		data   string
+		config yjvalid8r_lib.YAMLLintConfig
+		want   []lintPosition
+	}{
+		{
+			name: "Clean document",
+			data: "---\n# settings\nname: web  # inline\nenabled: true\nlabels: {app: web}\n",
+		},
+		{
+			name: "Duplicate keys",
+			data: "---\nname: web\nspec:\n  port: 80\n  port: 81\nname: api\n",
+			want: []lintPosition{{yjvalid8r_lib.RuleYAMLKeyDuplicates, 5, 3}, {yjvalid8r_lib.RuleYAMLKeyDuplicates, 6, 1}},
+		},
+		{
+			name:   "Key ordering",
+			data:   "---\nb: 1\na: 2\nc:\n  z: 1\n  y: 2\n",
+			config: yjvalid8r_lib.YAMLLintConfig{KeyOrdering: yjvalid8r_lib.YAMLLintRule{Enabled: &enabled}},
+			want:   []lintPosition{{yjvalid8r_lib.RuleYAMLKeyOrdering, 3, 1}, {yjvalid8r_lib.RuleYAMLKeyOrdering, 6, 3}},
+		},
+		{
+			name: "Truthy values",
+			data: "---\ndebug: yes\nenabled: True\nquoted: 'on'\nno: 1\n",
+			want: []lintPosition{{yjvalid8r_lib.RuleYAMLTruthy, 2, 8}, {yjvalid8r_lib.RuleYAMLTruthy, 3, 10}, {yjvalid8r_lib.RuleYAMLTruthy, 5, 1}},
+		},
+		{
+			name: "Truthy allowed values without keys",
+			data: "---\ndebug: yes\non: off\n",
+			config: yjvalid8r_lib.YAMLLintConfig{Truthy: yjvalid8r_lib.YAMLLintTruthyRule{
+				AllowedValues: []string{"yes", "no"},
+				CheckKeys:     &disabled,
+			}},
+			want: []lintPosition{{yjvalid8r_lib.RuleYAMLTruthy, 3, 5}},
+		},
+		{
+			name: "Missing document start",
+			data: "# comment\nname: web\n",
+			want: []lintPosition{{yjvalid8r_lib.RuleYAMLDocumentStart, 2, 1}},
+		},
+		{
+			name:   "Forbidden document start",
+			data:   "---\nname: web\n---\nname: api\n",
+			config: yjvalid8r_lib.YAMLLintConfig{DocumentStart: yjvalid8r_lib.YAMLLintDocumentStartRule{Present: &disabled}},
+			want:   []lintPosition{{yjvalid8r_lib.RuleYAMLDocumentStart, 1, 1}, {yjvalid8r_lib.RuleYAMLDocumentStart, 3, 1}},
+		},
+		{
+			name:   "Line length",
+			data:   "---\ndescription: a rather long sentence\nurl: https://example.com/a/very/long/path\n# https://example.com/a/very/long/path\n",
+			config: yjvalid8r_lib.YAMLLintConfig{LineLength: yjvalid8r_lib.YAMLLintLineLengthRule{Max: 20}},
+			want:   []lintPosition{{yjvalid8r_lib.RuleYAMLLineLength, 2, 21}, {yjvalid8r_lib.RuleYAMLLineLength, 3, 21}},
+		},
+		{
+			name: "Comments",
+			data: "---\n#no space\nname: web # close\n#!not a shebang\n###\nurl: 'a #b'  # ok\n",
+			want: []lintPosition{{yjvalid8r_lib.RuleYAMLComments, 2, 2}, {yjvalid8r_lib.RuleYAMLComments, 3, 11}, {yjvalid8r_lib.RuleYAMLComments, 4, 2}},
+		},
+		{
+			name: "Comment characters in block scalars",
+			data: "---\nscript: |\n  echo a #b\n  #c\n",
+		},
+		{
+			name:   "Quoted strings required",
+			data:   "---\nname: web\nport: 80\nkind: 'Service'\nenv: \"prod\"\n",
+			config: yjvalid8r_lib.YAMLLintConfig{QuotedStrings: yjvalid8r_lib.YAMLLintQuotedStringsRule{YAMLLintRule: yjvalid8r_lib.YAMLLintRule{Enabled: &enabled}, QuoteType: "double"}},
+			want:   []lintPosition{{yjvalid8r_lib.RuleYAMLQuotedStrings, 2, 7}, {yjvalid8r_lib.RuleYAMLQuotedStrings, 4, 7}},
+		},
+		{
+			name:   "Quoted strings only when needed",
+			data:   "---\nname: \"web\"\nversion: \"1.0\"\nempty: ''\nmessage: 'a: b'\n",
+			config: yjvalid8r_lib.YAMLLintConfig{QuotedStrings: yjvalid8r_lib.YAMLLintQuotedStringsRule{YAMLLintRule: yjvalid8r_lib.YAMLLintRule{Enabled: &enabled}, Required: "only-when-needed"}},
+			want:   []lintPosition{{yjvalid8r_lib.RuleYAMLQuotedStrings, 2, 7}},
+		},
+		{
+			name: "Braces",
+			data: "---\na: { x: 1 }\nb: {y: 2,  z: 3}\nc: {}\nd: {\n  w: 4\n}\n",
+			want: []lintPosition{{yjvalid8r_lib.RuleYAMLBraces, 2, 5}, {yjvalid8r_lib.RuleYAMLBraces, 2, 10}},
+		},
+		{
+			name:   "Braces min spaces",
+			data:   "---\na: {x: 1}\nb: { y: '}' }\n",
+			config: yjvalid8r_lib.YAMLLintConfig{Braces: yjvalid8r_lib.YAMLLintBracesRule{MinSpacesInside: 1, MaxSpacesInside: 1}},
+			want:   []lintPosition{{yjvalid8r_lib.RuleYAMLBraces, 2, 5}, {yjvalid8r_lib.RuleYAMLBraces, 2, 9}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := yjvalid8r_lib.LintYAML([]byte(tt.data), tt.config)
+			got := lintPositions(result)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Expected %v, got findings: %+v", tt.want, result.Findings)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("Finding %d: expected %v, got %+v", i, tt.want[i], result.Findings[i])
+				}
+			}
+		})
+	}
+}
+
+func TestLintYAML_SeverityAndMessages(t *testing.T) {
+	disabled := false
+	config := yjvalid8r_lib.YAMLLintConfig{
+		Truthy:        yjvalid8r_lib.YAMLLintTruthyRule{YAMLLintRule: yjvalid8r_lib.YAMLLintRule{Severity: yjvalid8r_lib.MessageTypeInfo}},
+		DocumentStart: yjvalid8r_lib.YAMLLintDocumentStartRule{YAMLLintRule: yjvalid8r_lib.YAMLLintRule{Enabled: &disabled}},
+	}
+	result := yjvalid8r_lib.LintYAML([]byte("debug: on\ndebug: off\n"), config)
+
+	if result.Valid {
+		t.Error("Expected duplicate keys to make the result invalid")
+	}
+	if len(result.Errors) != 1 || result.Errors[0] != `Line 2: Duplicate key "debug", first defined at line 1.` {
+		t.Errorf("Unexpected errors: %v", result.Errors)
+	}
+	if len(result.Messages) != 2 || !strings.HasPrefix(result.Messages[0], "Line 1: Truthy value should be one of [false, true].") {
+		t.Errorf("Unexpected messages: %v", result.Messages)
+	}
+}
+
+func TestLintYAML_MultiDocumentFindings(t *testing.T) {
+	result := yjvalid8r_lib.LintYAML([]byte("---\ndebug: on\n---\nname: a\nname: b\n"), yjvalid8r_lib.YAMLLintConfig{})
+
+	want := []struct {
+		ruleID   string
+		line     int
+		document int
+	}{
+		{yjvalid8r_lib.RuleYAMLTruthy, 2, 0},
+		{yjvalid8r_lib.RuleYAMLKeyDuplicates, 5, 1},
+	}
+	if len(result.Findings) != len(want) {
+		t.Fatalf("Expected %d findings, got: %+v", len(want), result.Findings)
+	}
+	for i, finding := range result.Findings {
+		if finding.RuleID != want[i].ruleID || finding.Line != want[i].line || finding.Document != want[i].document {
+			t.Errorf("Expected %s at line %d of document %d, got: %+v", want[i].ruleID, want[i].line, want[i].document, finding)
+		}
+	}
+}
+
+func TestLintYAML_ParseError(t *testing.T) {
+	result := yjvalid8r_lib.LintYAML([]byte("---\na: [1\nb: 2 # x\n"), yjvalid8r_lib.YAMLLintConfig{})
+	if len(result.Messages) != 1 || !strings.Contains(result.Messages[0], "document structure skipped") {
+		t.Errorf("Expected skipped structure rules, got: %v", result.Messages)
+	}
+}
+
+func TestYAMLLintConfig_Validate(t *testing.T) {
+	valid := yjvalid8r_lib.YAMLLintConfig{Braces: yjvalid8r_lib.YAMLLintBracesRule{MinSpacesInside: 1, MaxSpacesInside: -1}}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	invalid := []yjvalid8r_lib.YAMLLintConfig{
+		{KeyOrdering: yjvalid8r_lib.YAMLLintRule{Severity: "fatal"}},
+		{QuotedStrings: yjvalid8r_lib.YAMLLintQuotedStringsRule{QuoteType: "backtick"}},
+		{QuotedStrings: yjvalid8r_lib.YAMLLintQuotedStringsRule{Required: "always"}},
+		{Braces: yjvalid8r_lib.YAMLLintBracesRule{MinSpacesInside: 2, MaxSpacesInside: 1}},
+	}
+	for i, config := range invalid {
+		if err := config.Validate(); err == nil {
+			t.Errorf("Config %d: expected an error", i)
+		}
+	}
+}
@@ -0,0 +1,275 @@
+package yjvalid8r_lib
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/pelletier/go-toml/v2/unstable"
+	"gopkg.in/yaml.v3"
+)
+
+// tomlPosition is where a key (or array element) and its value are written in a TOML document.
+type tomlPosition struct {
+	order                  int // order of first appearance
+	line, column           int // key, or the element itself for array elements
+	valueLine, valueColumn int
+}
+
+// isTOMLData reports whether the data is syntactically valid TOML with at least one expression.
+// Semantic errors, such as a key defined twice, are reported when the data is decoded.
+func isTOMLData(data []byte) bool {
+	var parser unstable.Parser
+	parser.Reset(data)
+	expressions := 0
+	for parser.NextExpression() {
+		expressions++
+	}
+	return parser.Error() == nil && expressions > 0
+}
+
+// decodeTOMLDocuments decodes a TOML document into a single document node. The values are those of the
+// TOML decoder; keys keep their order and every node the line and column of its key or value in the data.
+func decodeTOMLDocuments(data []byte) ([]Document, error) {
+	var value map[string]interface{}
+	if err := toml.Unmarshal(data, &value); err != nil {
+		var decodeErr *toml.DecodeError
+		if errors.As(err, &decodeErr) {
+			line, column := decodeErr.Position()
+			return nil, fmt.Errorf("toml: line %d, column %d: %s", line, column, strings.TrimPrefix(decodeErr.Error(), "toml: "))
+		}
+		// keys and tables defined twice are reported without a position
+		if _, positionErr := tomlPositions(data); positionErr != nil {
+			return nil, positionErr
+		}
+		return nil, err
+	}
+
+	positions, err := tomlPositions(data)
+	if err != nil {
+		return nil, err
+	}
+
+	root := tomlNode(value, nil, positions)
+	root.Line, root.Column = 1, 1
+	if len(root.Content) > 0 {
+		root.Line, root.Column = root.Content[0].Line, root.Content[0].Column
+	}
+	document := &yaml.Node{Kind: yaml.DocumentNode, Line: root.Line, Column: root.Column, Content: []*yaml.Node{root}}
+	return []Document{{Index: 0, Node: document}}, nil
+}
+
+// tomlPositionMap records the positions of the keys and values of a TOML document by JSON pointer.
+type tomlPositionMap struct {
+	parser      *unstable.Parser
+	positions   map[string]tomlPosition
+	arrayTables map[string]int  // JSON pointer of an array of tables -> number of tables so far
+	defined     map[string]bool // JSON pointers of the values and [tables] defined so far
+	err         error           // first key or table defined twice
+}
+
+// tomlPositions walks the expressions of a TOML document and returns the position map.
+func tomlPositions(data []byte) (map[string]tomlPosition, error) {
+	m := &tomlPositionMap{
+		parser:      &unstable.Parser{},
+		positions:   make(map[string]tomlPosition),
+		arrayTables: make(map[string]int),
+		defined:     make(map[string]bool),
+	}
+	m.parser.Reset(data)
+
+	var table []string // path of the current [table] or [[array table]] element
+	for m.parser.NextExpression() {
+		expression := m.parser.Expression()
+		switch expression.Kind {
+		case unstable.Table:
+			table = m.tablePath(expression)
+			m.define(table, "table", expression)
+		case unstable.ArrayTable:
+			path := m.tablePath(expression)
+			pointer := jsonPointer(path)
+			index := m.arrayTables[pointer]
+			m.arrayTables[pointer] = index + 1
+			line, column := m.keyPosition(expression)
+			table = appendPath(path, strconv.Itoa(index))
+			m.record(table, line, column, line, column)
+		case unstable.KeyValue:
+			m.keyValue(table, expression)
+		}
+	}
+	if err := m.parser.Error(); err != nil {
+		return nil, fmt.Errorf("toml: %w", err)
+	}
+	return m.positions, m.err
+}
+
+// define marks the value or table at path as defined, failing on the second definition.
+func (m *tomlPositionMap) define(path []string, kind string, expression *unstable.Node) {
+	pointer := jsonPointer(path)
+	if !m.defined[pointer] {
+		m.defined[pointer] = true
+		return
+	}
+	if m.err == nil {
+		line, column := m.keyPosition(expression)
+		m.err = fmt.Errorf("toml: line %d, column %d: %s %s is already defined", line, column, kind, strings.Join(path, "."))
+	}
+}
+
+// tablePath resolves the key of a table header. A key part naming an array of tables refers to its last table.
+func (m *tomlPositionMap) tablePath(expression *unstable.Node) []string {
+	var path []string
+	keys := expression.Key()
+	for keys.Next() {
+		if count := m.arrayTables[jsonPointer(path)]; count > 0 && len(path) > 0 {
+			path = appendPath(path, strconv.Itoa(count-1))
+		}
+		key := keys.Node()
+		path = appendPath(path, string(key.Data))
+		line, column := m.shape(key)
+		m.record(path, line, column, line, column)
+	}
+	return path
+}
+
+// keyValue records the (dotted) key of a key/value expression below base, and its value.
+func (m *tomlPositionMap) keyValue(base []string, expression *unstable.Node) {
+	path := base
+	var line, column int
+	keys := expression.Key()
+	for keys.Next() {
+		key := keys.Node()
+		path = appendPath(path, string(key.Data))
+		line, column = m.shape(key)
+		m.record(path, line, column, line, column)
+	}
+	m.define(path, "key", expression)
+	m.value(path, expression.Value(), line, column)
+}
+
+// value records the position of a value, falling back to the position of its key, and of its elements.
+func (m *tomlPositionMap) value(path []string, node *unstable.Node, keyLine, keyColumn int) {
+	valueLine, valueColumn := keyLine, keyColumn
+	if node.Raw.Length > 0 {
+		valueLine, valueColumn = m.shape(node)
+	}
+	position := m.positions[jsonPointer(path)]
+	position.valueLine, position.valueColumn = valueLine, valueColumn
+	m.positions[jsonPointer(path)] = position
+
+	children := node.Children()
+	switch node.Kind {
+	case unstable.InlineTable:
+		for children.Next() {
+			m.keyValue(path, children.Node())
+		}
+	case unstable.Array:
+		for index := 0; children.Next(); index++ {
+			child := children.Node()
+			elementLine, elementColumn := valueLine, valueColumn
+			if child.Raw.Length > 0 {
+				elementLine, elementColumn = m.shape(child)
+			}
+			elementPath := appendPath(path, strconv.Itoa(index))
+			m.record(elementPath, elementLine, elementColumn, elementLine, elementColumn)
+			m.value(elementPath, child, elementLine, elementColumn)
+		}
+	}
+}
+
+// record stores the position of a path the first time it is seen.
+func (m *tomlPositionMap) record(path []string, line, column, valueLine, valueColumn int) {
+	pointer := jsonPointer(path)
+	if _, ok := m.positions[pointer]; ok {
+		return
+	}
+	m.positions[pointer] = tomlPosition{order: len(m.positions), line: line, column: column, valueLine: valueLine, valueColumn: valueColumn}
+}
+
+// keyPosition returns the position of the first key part of a table header or key/value expression.
+func (m *tomlPositionMap) keyPosition(expression *unstable.Node) (int, int) {
+	keys := expression.Key()
+	if !keys.Next() {
+		return 0, 0
+	}
+	return m.shape(keys.Node())
+}
+
+// shape returns the line and (rune-based) column a node starts at.
+func (m *tomlPositionMap) shape(node *unstable.Node) (int, int) {
+	return offsetPosition(m.parser.Data(), m.parser.Shape(node.Raw).Start.Offset)
+}
+
+// tomlNode converts a decoded TOML value at path into a YAML node placed at its position.
+func tomlNode(value interface{}, path []string, positions map[string]tomlPosition) *yaml.Node {
+	position := positions[jsonPointer(path)]
+	node := &yaml.Node{Line: position.valueLine, Column: position.valueColumn}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		node.Kind, node.Tag = yaml.MappingNode, "!!map"
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			a, okA := positions[jsonPointer(appendPath(path, keys[i]))]
+			b, okB := positions[jsonPointer(appendPath(path, keys[j]))]
+			if okA != okB {
+				return okA
+			}
+			if a.order != b.order {
+				return a.order < b.order
+			}
+			return keys[i] < keys[j]
+		})
+		for _, key := range keys {
+			keyPath := appendPath(path, key)
+			keyPosition := positions[jsonPointer(keyPath)]
+			keyNode := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key, Line: keyPosition.line, Column: keyPosition.column}
+			node.Content = append(node.Content, keyNode, tomlNode(v[key], keyPath, positions))
+		}
+	case []interface{}:
+		node.Kind, node.Tag = yaml.SequenceNode, "!!seq"
+		for i, element := range v {
+			node.Content = append(node.Content, tomlNode(element, appendPath(path, strconv.Itoa(i)), positions))
+		}
+	default:
+		node.Kind = yaml.ScalarNode
+		node.Tag, node.Value = tomlScalar(v)
+	}
+	return node
+}
+
+// tomlScalar returns the YAML tag and value of a decoded TOML scalar. Dates and times become strings,
+// as in JSON (RFC 3339 for offset date-times).
+func tomlScalar(value interface{}) (string, string) {
+	switch v := value.(type) {
+	case string:
+		return "!!str", v
+	case bool:
+		return "!!bool", strconv.FormatBool(v)
+	case int64:
+		return "!!int", strconv.FormatInt(v, 10)
+	case float64:
+		switch {
+		case math.IsInf(v, 1):
+			return "!!float", ".inf"
+		case math.IsInf(v, -1):
+			return "!!float", "-.inf"
+		case math.IsNaN(v):
+			return "!!float", ".nan"
+		}
+		return "!!float", strconv.FormatFloat(v, 'g', -1, 64)
+	case time.Time:
+		return "!!str", v.Format(time.RFC3339Nano)
+	case fmt.Stringer: // toml.LocalDate, toml.LocalTime, toml.LocalDateTime
+		return "!!str", v.String()
+	}
+	return "!!str", fmt.Sprint(value)
+}
@@ -5,10 +5,46 @@ const (
 	DataTypeYAML string = "yaml"
 	// DataTypeJSON indicates that the input data is in JSON format.
 	DataTypeJSON string = "json"
-	// DataTypeUNKNOWN indicates that the input data format is unrecognized (neither JSON nor YAML).
+	// DataTypeTOML indicates that the input data is in TOML format.
+	DataTypeTOML string = "toml"
+	// DataTypeJSON5 indicates that the input data is in JSON5 format, which includes JSON with comments (JSONC).
+	DataTypeJSON5 string = "json5"
+	// DataTypeNDJSON indicates that the input data is JSON Lines (NDJSON): one JSON value per line.
+	DataTypeNDJSON string = "ndjson"
+	// DataTypeUNKNOWN indicates that the input data format is unrecognized (none of the registered formats).
 	DataTypeUNKNOWN string = "unknown"
 )
 
+// DataFormat is an input format of the format registry: how to recognize data written in it and how to
+// decode that data into YAML node documents, which all checks work on.
+type DataFormat struct {
+	Name   string                                // Data type reported by DetectDataType, e.g. "toml".
+	Detect func(data []byte) bool                // Reports whether the data (without surrounding whitespace) is in this format.
+	Decode func(data []byte) ([]Document, error) // Decodes the data; node lines and columns must point into data.
+
+	detect func(probe *formatProbe) bool // Detect of a built-in format, reusing the YAML node tree of the probe.
+}
+
+// Confidence levels of DataFormatDetection.
+const (
+	// ConfidenceHigh indicates that the data is written in the detected format and no other.
+	ConfidenceHigh string = "high"
+	// ConfidenceMedium indicates that the data was only recognized as flow-style YAML, which is close to JSON.
+	ConfidenceMedium string = "medium"
+	// ConfidenceNone indicates that no registered format recognized the data.
+	ConfidenceNone string = "none"
+)
+
+// DataFormatDetection is the result of DetectDataFormat. For unknown data it explains why it was rejected,
+// with the error of the format the data was most likely written in.
+type DataFormatDetection struct {
+	Format     string `json:"format"`           // Detected data type, e.g. "yaml"; "unknown" if no format recognized the data.
+	Confidence string `json:"confidence"`       // high, medium or none.
+	Error      string `json:"error,omitempty"`  // Why the data was rejected, e.g. "json: line 3, column 5: invalid character '}' ...".
+	Line       int    `json:"line,omitempty"`   // Line of the error, when known.
+	Column     int    `json:"column,omitempty"` // Column of the error, when known.
+}
+
 // ValidationMessageType represents the type of message produced during validation: error or warning.
 type ValidationMessageType string
 
@@ -18,12 +54,52 @@ const (
 
 	// MessageTypeWarning indicates a non-critical issue or suggestion.
 	MessageTypeWarning ValidationMessageType = "warning"
+
+	// MessageTypeInfo indicates an informational finding, e.g. a regex pattern match.
+	MessageTypeInfo ValidationMessageType = "info"
 )
 
+// SchemaDraft identifies a JSON Schema draft.
+type SchemaDraft string
+
+const (
+	// SchemaDraftAuto selects the draft from the schema's "$schema" keyword.
+	SchemaDraftAuto SchemaDraft = ""
+	// SchemaDraft4 is JSON Schema draft-04.
+	SchemaDraft4 SchemaDraft = "draft-04"
+	// SchemaDraft6 is JSON Schema draft-06.
+	SchemaDraft6 SchemaDraft = "draft-06"
+	// SchemaDraft7 is JSON Schema draft-07.
+	SchemaDraft7 SchemaDraft = "draft-07"
+	// SchemaDraft2019 is JSON Schema 2019-09.
+	SchemaDraft2019 SchemaDraft = "2019-09"
+	// SchemaDraft2020 is JSON Schema 2020-12.
+	SchemaDraft2020 SchemaDraft = "2020-12"
+)
+
+// Finding is a single structured result of a check, so tools do not have to parse the message strings.
+// Positions are 1-based; zero means unknown. The end position is exclusive.
+type Finding struct {
+	Severity     ValidationMessageType `json:"severity"`               // error, warning or info.
+	RuleID       string                `json:"ruleId"`                 // Check that produced the finding, e.g. "schema/required" or "whitespace/trailing".
+	Message      string                `json:"message"`                // Human-readable description, without the position prefix.
+	File         string                `json:"file,omitempty"`         // Data file the finding refers to, when known.
+	Document     int                   `json:"document"`               // Index of the document (in a multi-document stream) the finding refers to.
+	Line         int                   `json:"line,omitempty"`         // Line the finding starts on.
+	Column       int                   `json:"column,omitempty"`       // Column the finding starts at.
+	EndLine      int                   `json:"endLine,omitempty"`      // Line the finding ends on.
+	EndColumn    int                   `json:"endColumn,omitempty"`    // Column just past the end of the finding.
+	Pointer      string                `json:"pointer,omitempty"`      // RFC 6901 JSON pointer to the value, when the finding refers to one.
+	AnchorLine   int                   `json:"anchorLine,omitempty"`   // For a value used through a YAML alias: line it is defined at, within the anchored value.
+	AnchorColumn int                   `json:"anchorColumn,omitempty"` // Column the aliased value is defined at.
+}
+
 // SchemaValidationMessage represents a single validation result message.
 type SchemaValidationMessage struct {
-	Type    ValidationMessageType // The severity of the message: error or warning.
-	Message string                // A human-readable description of the validation issue.
+	Type     ValidationMessageType // The severity of the message: error or warning.
+	Message  string                // A human-readable description of the validation issue.
+	Document int                   // Index of the document (in a multi-document stream) the message refers to.
+	Finding  Finding               // Structured form of the message.
 }
 
 // RegexPatternRulesCheckEnvConfig defines configuration options for validating environment variables.
@@ -41,37 +117,247 @@ type RegexPatternRules struct {
 
 // RegexPatternRulesOutput contains the results of applying a single regex pattern rule.
 type RegexPatternRulesOutput struct {
-	Name               string   `json:"name"`                // Name of the regex rule.
-	CheckEnv           bool     `json:"checkEnv"`            // Indicates if environment validation was enabled.
-	CheckEnvStrictMode bool     `json:"checkEnvStrictMode"`  // Indicates if strict environment validation was used.
-	Data               []string `json:"data"`                // Matched data from the input.
-	Errors             []string `json:"errors,omitempty"`    // List of errors encountered during validation.
-	EnvValues          []string `json:"envValues,omitempty"` // Environment variable values that matched the pattern.
-	Messages           []string `json:"messages,omitempty"`  // Additional context or informational messages.
+	Name               string    `json:"name"`                // Name of the regex rule.
+	CheckEnv           bool      `json:"checkEnv"`            // Indicates if environment validation was enabled.
+	CheckEnvStrictMode bool      `json:"checkEnvStrictMode"`  // Indicates if strict environment validation was used.
+	Data               []string  `json:"data"`                // Matched data from the input.
+	Errors             []string  `json:"errors,omitempty"`    // List of errors encountered during validation.
+	EnvValues          []string  `json:"envValues,omitempty"` // Environment variable values that matched the pattern.
+	Messages           []string  `json:"messages,omitempty"`  // Additional context or informational messages.
+	Findings           []Finding `json:"findings,omitempty"`  // Structured form of the matches and errors.
 }
 
+// Syntaxes of SearchPathsDef.PathKey.
+const (
+	SearchPathSyntaxLegacy   = "legacy"   // Dot notation, e.g. spec.ports[].name; keys not found are searched for recursively.
+	SearchPathSyntaxJSONPath = "jsonpath" // JSONPath (RFC 9535), e.g. $..ports[?@.port > 80].name
+)
+
 // SearchPathsDef defines a configuration for searching specific paths in structured data.
 type SearchPathsDef struct {
-	PathName string `json:"pathName" yaml:"pathName"` // User-friendly label for the search path.
-	PathKey  string `json:"pathKey" yaml:"pathKey"`   // Dot notation key used to traverse the data structure.
+	PathName string                 `json:"pathName" yaml:"pathName"` // User-friendly label for the search path.
+	PathKey  string                 `json:"pathKey" yaml:"pathKey"`   // Key used to traverse the data structure, in the given syntax.
+	Syntax   string                 `json:"syntax" yaml:"syntax"`     // Syntax of PathKey: legacy (default) or jsonpath.
+	Expect   *SearchPathExpectation `json:"expect" yaml:"expect"`     // Optional assertions on the values found.
+}
+
+// SearchPathExpectation asserts on the values a search path finds in each document; every field set is checked.
+// Value checks apply to every value found.
+type SearchPathExpectation struct {
+	Severity  ValidationMessageType `json:"severity" yaml:"severity"`   // error (default) or warning.
+	Required  bool                  `json:"required" yaml:"required"`   // At least one value must be found.
+	Forbidden bool                  `json:"forbidden" yaml:"forbidden"` // No value may be found.
+	MinCount  *int                  `json:"minCount" yaml:"minCount"`   // Minimum number of values found.
+	MaxCount  *int                  `json:"maxCount" yaml:"maxCount"`   // Maximum number of values found.
+	Equals    interface{}           `json:"equals" yaml:"equals"`       // Value every value must equal; numbers compare by value.
+	OneOf     []interface{}         `json:"oneOf" yaml:"oneOf"`         // Allowed values.
+	Pattern   string                `json:"pattern" yaml:"pattern"`     // Regular expression scalar values must match (searched, not anchored).
+	Minimum   *float64              `json:"minimum" yaml:"minimum"`     // Inclusive lower bound of numeric values.
+	Maximum   *float64              `json:"maximum" yaml:"maximum"`     // Inclusive upper bound of numeric values.
+	Type      string                `json:"type" yaml:"type"`           // string, number, integer, boolean, null, array or object.
 }
 
 // SearchPathsOutputResultItem represents a single match found during a search operation.
 type SearchPathsOutputResultItem struct {
 	FullPath string `json:"fullPath"` // Full dot-notated path to the matched value.
 	Raw      string `json:"raw"`      // Raw value found at the specified path.
+	Document int    `json:"document"` // Index of the document (in a multi-document stream) the value was found in.
+	Line     int    `json:"line"`     // Line the value starts on.
+	Column   int    `json:"column"`   // Column the value starts at.
 }
 
 // SearchPathsOutput contains the complete result of a search operation for a specific path definition.
 type SearchPathsOutput struct {
-	PathName string                        `json:"pathName"` // Label of the search path used.
-	PathKey  string                        `json:"pathKey"`  // Key used for data traversal.
-	Results  []SearchPathsOutputResultItem `json:"results"`  // List of matched items found.
+	PathName string                        `json:"pathName"`           // Label of the search path used.
+	PathKey  string                        `json:"pathKey"`            // Key used for data traversal.
+	Results  []SearchPathsOutputResultItem `json:"results"`            // List of matched items found.
+	Errors   []string                      `json:"errors,omitempty"`   // Problems with the path itself, e.g. an invalid JSONPath query, and failed expectations.
+	Warnings []string                      `json:"warnings,omitempty"` // Failed expectations of severity warning.
+	Findings []Finding                     `json:"findings,omitempty"` // Structured form of the errors and warnings.
 }
 
 // WhitespaceCheckResult contains the result of checking for trailing whitespace or tab characters.
 type WhitespaceCheckResult struct {
-	Errors   []string `json:"errors,omitempty"`   // List of error messages related to whitespace.
-	Warnings []string `json:"warnings,omitempty"` // List of warnings related to formatting.
-	Messages []string `json:"messages,omitempty"` // General messages or suggestions.
+	Errors   []string  `json:"errors,omitempty"`   // List of error messages related to whitespace.
+	Warnings []string  `json:"warnings,omitempty"` // List of warnings related to formatting.
+	Messages []string  `json:"messages,omitempty"` // General messages or suggestions.
+	Findings []Finding `json:"findings,omitempty"` // Structured form of the errors and warnings.
+}
+
+// FormattingRule enables a formatting check and sets the severity of its findings.
+type FormattingRule struct {
+	Enabled  *bool                 `json:"enabled,omitempty" yaml:"enabled"`   // Nil uses the rule's default, or enables it when Severity is set.
+	Severity ValidationMessageType `json:"severity,omitempty" yaml:"severity"` // error, warning or info; empty uses the rule's default.
+}
+
+// FormattingRules configures the checks of CheckFormatting. The zero value checks only tabs and trailing
+// whitespace, like CheckTabsAndWhitespacesFinder; the other rules are enabled with Enabled or a Severity.
+type FormattingRules struct {
+	Tabs               FormattingRule `json:"tabs" yaml:"tabs"`                             // Tabs in the indentation; error by default.
+	TrailingWhitespace FormattingRule `json:"trailingWhitespace" yaml:"trailingWhitespace"` // Spaces or tabs at the end of a line; warning by default.
+	LineEndings        FormattingRule `json:"lineEndings" yaml:"lineEndings"`               // Line endings other than EndOfLine; opt-in, enabled by setting EndOfLine; warning by default.
+	BOM                FormattingRule `json:"bom" yaml:"bom"`                               // UTF-8 byte order mark; opt-in; warning by default.
+	FinalNewline       FormattingRule `json:"finalNewline" yaml:"finalNewline"`             // Missing newline at the end of the data; opt-in; warning by default.
+	Indentation        FormattingRule `json:"indentation" yaml:"indentation"`               // Nested YAML block collections not indented by IndentWidth; opt-in, enabled by setting IndentWidth; warning by default.
+	EndOfLine          string         `json:"endOfLine" yaml:"endOfLine"`                   // Expected line ending: "lf" (default) or "crlf".
+	IndentWidth        int            `json:"indentWidth" yaml:"indentWidth"`               // Expected indentation width; 0 takes it from the first nested collection.
+}
+
+// YAMLLintRule enables a YAML lint rule and sets the severity of its findings.
+type YAMLLintRule struct {
+	Enabled  *bool                 `json:"enabled,omitempty" yaml:"enabled"`   // Nil uses the rule's default.
+	Severity ValidationMessageType `json:"severity,omitempty" yaml:"severity"` // error, warning or info; empty uses the rule's default.
+}
+
+// YAMLLintTruthyRule reports plain truthy values (yes, on, True, ...) outside AllowedValues.
+type YAMLLintTruthyRule struct {
+	YAMLLintRule  `yaml:",inline"`
+	AllowedValues []string `json:"allowedValues,omitempty" yaml:"allowedValues"` // Defaults to "true" and "false".
+	CheckKeys     *bool    `json:"checkKeys,omitempty" yaml:"checkKeys"`         // Also check mapping keys; default true.
+}
+
+// YAMLLintDocumentStartRule requires (or forbids) the "---" document start marker.
+type YAMLLintDocumentStartRule struct {
+	YAMLLintRule `yaml:",inline"`
+	Present      *bool `json:"present,omitempty" yaml:"present"` // True (default) requires the marker, false forbids it.
+}
+
+// YAMLLintLineLengthRule limits the length of lines.
+type YAMLLintLineLengthRule struct {
+	YAMLLintRule           `yaml:",inline"`
+	Max                    int   `json:"max,omitempty" yaml:"max"`                                       // Maximum number of characters; default 80.
+	AllowNonBreakableWords *bool `json:"allowNonBreakableWords,omitempty" yaml:"allowNonBreakableWords"` // Allow long lines holding a single word, e.g. a URL; default true.
+}
+
+// YAMLLintCommentsRule checks the spacing of comments.
+type YAMLLintCommentsRule struct {
+	YAMLLintRule         `yaml:",inline"`
+	RequireStartingSpace *bool `json:"requireStartingSpace,omitempty" yaml:"requireStartingSpace"` // Require a space after "#"; default true.
+	MinSpacesFromContent int   `json:"minSpacesFromContent,omitempty" yaml:"minSpacesFromContent"` // Spaces between content and an inline comment; default 2.
+}
+
+// YAMLLintQuotedStringsRule checks how string values are quoted.
+type YAMLLintQuotedStringsRule struct {
+	YAMLLintRule `yaml:",inline"`
+	QuoteType    string `json:"quoteType,omitempty" yaml:"quoteType"` // "any" (default), "single" or "double".
+	Required     string `json:"required,omitempty" yaml:"required"`   // "true" (default), "false" or "only-when-needed".
+}
+
+// YAMLLintBracesRule checks the spaces inside flow mapping braces.
+type YAMLLintBracesRule struct {
+	YAMLLintRule    `yaml:",inline"`
+	MinSpacesInside int `json:"minSpacesInside,omitempty" yaml:"minSpacesInside"` // Default 0.
+	MaxSpacesInside int `json:"maxSpacesInside,omitempty" yaml:"maxSpacesInside"` // Default 0; -1 for no limit.
+}
+
+// JSONLinesConfig configures the JSON Lines (NDJSON) data mode, in which every line of the data is a record
+// validated on its own.
+type JSONLinesConfig struct {
+	MaxErrors int `json:"maxErrors" yaml:"maxErrors"` // Errors reported before the rest are only counted; 0 uses DefaultJSONLinesMaxErrors.
+}
+
+// YAMLLintConfig configures the rules of LintYAML. Rules are enabled by default, except keyOrdering and quotedStrings;
+// keyDuplicates reports errors, the other rules warnings.
+type YAMLLintConfig struct {
+	KeyDuplicates YAMLLintRule              `json:"keyDuplicates" yaml:"keyDuplicates"` // Keys defined twice in one mapping.
+	KeyOrdering   YAMLLintRule              `json:"keyOrdering" yaml:"keyOrdering"`     // Keys of a mapping not in alphabetical order.
+	Truthy        YAMLLintTruthyRule        `json:"truthy" yaml:"truthy"`
+	DocumentStart YAMLLintDocumentStartRule `json:"documentStart" yaml:"documentStart"`
+	LineLength    YAMLLintLineLengthRule    `json:"lineLength" yaml:"lineLength"`
+	Comments      YAMLLintCommentsRule      `json:"comments" yaml:"comments"`
+	QuotedStrings YAMLLintQuotedStringsRule `json:"quotedStrings" yaml:"quotedStrings"`
+	Braces        YAMLLintBracesRule        `json:"braces" yaml:"braces"`
+}
+
+// YAMLLintResult contains the findings of LintYAML.
+type YAMLLintResult struct {
+	Valid    bool      `json:"valid"`              // False if a rule reported an error.
+	Errors   []string  `json:"errors,omitempty"`   // Findings of error severity, as "Line N: message".
+	Warnings []string  `json:"warnings,omitempty"` // Findings of warning severity.
+	Messages []string  `json:"messages,omitempty"` // Findings of info severity and general messages.
+	Findings []Finding `json:"findings,omitempty"` // Structured form of every finding.
+}
+
+// DuplicateKeysCheckResult holds the outcome of the duplicate key check.
+type DuplicateKeysCheckResult struct {
+	Valid    bool      `json:"valid"`              // False if a key is defined more than once.
+	Errors   []string  `json:"errors,omitempty"`   // One "Line N: message" per repeated key.
+	Messages []string  `json:"messages,omitempty"` // General messages, e.g. when the data cannot be parsed.
+	Findings []Finding `json:"findings,omitempty"` // Structured form of the errors.
+}
+
+// WhitespaceFixOptions configures how FixWhitespace rewrites data.
+type WhitespaceFixOptions struct {
+	IndentWidth int             `json:"indentWidth" yaml:"indentWidth"` // Spaces that replace each tab in the indentation; 0 uses DefaultIndentWidth.
+	Rules       FormattingRules `json:"rules" yaml:"rules"`             // Formatting checks the fixes follow: issues of disabled rules, e.g. tabs allowed by an .editorconfig, are kept.
+}
+
+// WhitespaceFixResult contains the data rewritten by FixWhitespace and what was changed.
+type WhitespaceFixResult struct {
+	Data     []byte   `json:"-"`                  // Fixed data.
+	Changed  bool     `json:"changed"`            // True if the fixed data differs from the input.
+	Messages []string `json:"messages,omitempty"` // One message per fix, e.g. "Line 3: Removed trailing whitespace."
+}
+
+// FormatOptions configures the canonical style FormatData writes.
+type FormatOptions struct {
+	IndentWidth int    `json:"indentWidth" yaml:"indentWidth"` // Spaces per indentation level; 0 uses DefaultIndentWidth.
+	SortKeys    bool   `json:"sortKeys" yaml:"sortKeys"`       // Sort mapping keys instead of keeping their order.
+	QuoteStyle  string `json:"quoteStyle" yaml:"quoteStyle"`   // Quotes for strings that need them: "double" (default) or "single".
+}
+
+// FormatResult contains the data rewritten by FormatData.
+type FormatResult struct {
+	Data     []byte `json:"-"`        // Formatted data.
+	Changed  bool   `json:"changed"`  // True if the formatted data differs from the input, i.e. the input is not formatted.
+	DataType string `json:"dataType"` // Format of the data: "json" or "yaml".
+}
+
+// SchemaRoute maps documents matching a selector to the schema they are validated against.
+// All selector fields are optional; '*' in a selector matches any sequence of characters.
+type SchemaRoute struct {
+	Name       string `json:"name" yaml:"name"`             // User-friendly label for the route.
+	APIVersion string `json:"apiVersion" yaml:"apiVersion"` // Matches the document's top-level apiVersion, e.g. "apps/v1".
+	Kind       string `json:"kind" yaml:"kind"`             // Matches the document's top-level kind, e.g. "Deployment".
+	Pointer    string `json:"pointer" yaml:"pointer"`       // JSON pointer that must exist in the document, e.g. "/metadata/labels/app".
+	Value      string `json:"value" yaml:"value"`           // Expected scalar value at Pointer; empty only requires the pointer to exist.
+	Schema     string `json:"schema" yaml:"schema"`         // Schema location; may use SchemaRouteParams placeholders, e.g. "{{.Group}}/{{.Kind}}_{{.Version}}.json".
+}
+
+// SchemaRouteParams holds the values available to SchemaRoute.Schema templates.
+type SchemaRouteParams struct {
+	APIVersion string // Full apiVersion of the document, e.g. "apps/v1".
+	Group      string // API group taken from apiVersion, e.g. "apps"; empty for the core group.
+	Version    string // Version taken from apiVersion, e.g. "v1".
+	Kind       string // Kind of the document, e.g. "Deployment".
+	Value      string // Scalar value found at the route Pointer, if any.
+}
+
+// SchemaCacheConfig configures the on-disk cache used for remote schemas.
+type SchemaCacheConfig struct {
+	Dir     string            `json:"dir" yaml:"dir"`         // Cache directory; defaults to "yj-valid8r/schemas" under the user cache dir.
+	TTL     string            `json:"ttl" yaml:"ttl"`         // How long a cached schema is used before refetching, e.g. "24h"; empty never expires.
+	Offline bool              `json:"offline" yaml:"offline"` // If true, never touches the network and only uses cached or local schemas.
+	Pins    map[string]string `json:"pins" yaml:"pins"`       // Optional sha256 digest (hex, optionally "sha256:" prefixed) per schema URL.
+}
+
+// SchemaMapping maps remote schema URLs below Prefix to files in a local directory, like a schema catalog.
+type SchemaMapping struct {
+	Prefix string `json:"prefix" yaml:"prefix"` // URL prefix, e.g. "https://example.com/schemas/".
+	Dir    string `json:"dir" yaml:"dir"`       // Local directory holding the files below the prefix.
+}
+
+// SchemaOptions configures how schemas and the schemas they $ref are fetched and compiled.
+type SchemaOptions struct {
+	Cache    *SchemaCache   // Optional on-disk cache for remote schemas.
+	Resolver SchemaResolver // Optional mapping of schema URLs to other locations, e.g. a PrefixSchemaResolver.
+	Draft    SchemaDraft    // Forces a JSON Schema draft; SchemaDraftAuto uses the schema's "$schema".
+
+	MaxSchemas int // SchemaRegistry only: compiled schemas kept before the least recently used is dropped; 0 uses DefaultMaxRegistrySchemas, negative keeps all.
+}
+
+// SchemaInferenceOptions configures how a schema is inferred from sample data.
+type SchemaInferenceOptions struct {
+	Draft         SchemaDraft // Draft of the generated schema: SchemaDraft7 (used for SchemaDraftAuto) or SchemaDraft2020.
+	MaxEnumValues int         // Strings with at most this many distinct values, at least one repeated, become an enum; 0 uses DefaultMaxEnumValues, negative disables enums.
 }
@@ -0,0 +1,198 @@
+package yjvalid8r_lib
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContextLines is the number of unchanged lines shown around each change of a unified diff.
+const diffContextLines = 3
+
+// diffOp is one line of an edit script: ' ' (unchanged), '-' (removed) or '+' (added).
+type diffOp struct {
+	kind byte
+	line string
+}
+
+// UnifiedDiff returns the changes from oldData to newData in unified diff format, with the usual
+// "--- oldName" and "+++ newName" headers and three lines of context. It returns "" if the data is equal.
+func UnifiedDiff(oldName, newName string, oldData, newData []byte) string {
+	if string(oldData) == string(newData) {
+		return ""
+	}
+	ops := diffLines(splitLines(string(oldData)), splitLines(string(newData)))
+
+	// Positions in the old and new data before each op
+	oldPos := make([]int, len(ops)+1)
+	newPos := make([]int, len(ops)+1)
+	for i, op := range ops {
+		oldPos[i+1], newPos[i+1] = oldPos[i], newPos[i]
+		if op.kind != '+' {
+			oldPos[i+1]++
+		}
+		if op.kind != '-' {
+			newPos[i+1]++
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", oldName, newName)
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			i++
+			continue
+		}
+
+		// Extend the hunk while the next change is close enough for the contexts to touch
+		start := max(0, i-diffContextLines)
+		last := i
+		for j := i + 1; j < len(ops) && j-last <= 2*diffContextLines; j++ {
+			if ops[j].kind != ' ' {
+				last = j
+			}
+		}
+		stop := min(len(ops), last+diffContextLines+1)
+
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n",
+			hunkRange(oldPos[start], oldPos[stop]-oldPos[start]),
+			hunkRange(newPos[start], newPos[stop]-newPos[start]))
+		for _, op := range ops[start:stop] {
+			sb.WriteByte(op.kind)
+			sb.WriteString(op.line)
+			if !strings.HasSuffix(op.line, "\n") {
+				sb.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		i = stop
+	}
+	return sb.String()
+}
+
+// hunkRange formats the "start,count" of a hunk header; start is 1-based, or the line before an empty range.
+func hunkRange(pos, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", pos)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", pos+1)
+	}
+	return fmt.Sprintf("%d,%d", pos+1, count)
+}
+
+// splitLines splits data into lines, each keeping its "\n" terminator (except a last line without one).
+func splitLines(data string) []string {
+	lines := strings.SplitAfter(data, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines returns a shortest edit script turning a into b, using the linear space variant of Myers'
+// algorithm: the middle snake of an optimal path splits the problem in two, which are solved recursively.
+func diffLines(a, b []string) []diffOp {
+	size := len(a) + len(b) + 4
+	d := &lineDiff{a: a, b: b, forward: make([]int, size), backward: make([]int, size)}
+	d.diff(0, len(a), 0, len(b))
+	return d.ops
+}
+
+// lineDiff holds the state of diffLines; forward and backward are reused by every middleSnake call.
+type lineDiff struct {
+	a, b              []string
+	forward, backward []int
+	ops               []diffOp
+}
+
+// diff appends the edit script turning a[aLo:aHi] into b[bLo:bHi].
+func (d *lineDiff) diff(aLo, aHi, bLo, bHi int) {
+	for aLo < aHi && bLo < bHi && d.a[aLo] == d.b[bLo] {
+		d.ops = append(d.ops, diffOp{' ', d.a[aLo]})
+		aLo++
+		bLo++
+	}
+	suffix := 0
+	for aLo < aHi-suffix && bLo < bHi-suffix && d.a[aHi-1-suffix] == d.b[bHi-1-suffix] {
+		suffix++
+	}
+	aHi -= suffix
+	bHi -= suffix
+
+	switch {
+	case aLo == aHi:
+		for _, line := range d.b[bLo:bHi] {
+			d.ops = append(d.ops, diffOp{'+', line})
+		}
+	case bLo == bHi:
+		for _, line := range d.a[aLo:aHi] {
+			d.ops = append(d.ops, diffOp{'-', line})
+		}
+	default:
+		// Both ranges are non-empty and differ in their first and last lines, so at least two edits
+		// are needed and each half of the split needs fewer.
+		x, y, u, v := d.middleSnake(aLo, aHi, bLo, bHi)
+		d.diff(aLo, x, bLo, y)
+		for _, line := range d.a[x:u] {
+			d.ops = append(d.ops, diffOp{' ', line})
+		}
+		d.diff(u, aHi, v, bHi)
+	}
+
+	for _, line := range d.a[aHi : aHi+suffix] {
+		d.ops = append(d.ops, diffOp{' ', line})
+	}
+}
+
+// middleSnake returns the start (x, y) and end (u, v) of the snake in the middle of a shortest edit script
+// turning a[aLo:aHi] into b[bLo:bHi], searching forward from the start and backward from the end at once.
+// forward[off+k] is the furthest x reached on diagonal k = x - y; backward[off+k] the furthest distance
+// from the end reached on diagonal k of the reversed ranges, which is diagonal delta - k going forward.
+func (d *lineDiff) middleSnake(aLo, aHi, bLo, bHi int) (x, y, u, v int) {
+	n, m := aHi-aLo, bHi-bLo
+	delta := n - m
+	odd := delta%2 != 0
+	limit := (n + m + 1) / 2
+	off := limit + 1
+	forward, backward := d.forward, d.backward
+	forward[off+1], backward[off+1] = 0, 0
+
+	for depth := 0; depth <= limit; depth++ {
+		for k := -depth; k <= depth; k += 2 {
+			var fx int
+			if k == -depth || (k != depth && forward[off+k-1] < forward[off+k+1]) {
+				fx = forward[off+k+1]
+			} else {
+				fx = forward[off+k-1] + 1
+			}
+			fy := fx - k
+			startX, startY := fx, fy
+			for fx < n && fy < m && d.a[aLo+fx] == d.b[bLo+fy] {
+				fx++
+				fy++
+			}
+			forward[off+k] = fx
+			if c := delta - k; odd && c >= -(depth-1) && c <= depth-1 && fx+backward[off+c] >= n {
+				return aLo + startX, bLo + startY, aLo + fx, bLo + fy
+			}
+		}
+		for k := -depth; k <= depth; k += 2 {
+			var bx int
+			if k == -depth || (k != depth && backward[off+k-1] < backward[off+k+1]) {
+				bx = backward[off+k+1]
+			} else {
+				bx = backward[off+k-1] + 1
+			}
+			by := bx - k
+			startX, startY := bx, by
+			for bx < n && by < m && d.a[aHi-1-bx] == d.b[bHi-1-by] {
+				bx++
+				by++
+			}
+			backward[off+k] = bx
+			if c := delta - k; !odd && c >= -depth && c <= depth && bx+forward[off+c] >= n {
+				return aHi - bx, bHi - by, aHi - startX, bHi - startY
+			}
+		}
+	}
+	panic("unified diff: no middle snake found")
+}
@@ -1,212 +1,145 @@
 package yjvalid8r_lib
 
 import (
-	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
-	"net/url"
-	"os"
-	"path/filepath"
-	"runtime"
 	"strconv"
-	"strings"
-	"time"
 
-	"github.com/xeipuuv/gojsonschema"
 	"gopkg.in/yaml.v3"
 )
 
 // ValidateAgainstSchemaFinder validates the input data against a JSON schema from a URL.
+// Every document of a multi-document YAML stream is validated; each message carries the
+// index of the document it belongs to.
 // It returns a slice of SchemaValidationMessage and any error encountered.
+// To validate several data files against the same schema, compile it once with CompileSchema
+// or share a SchemaRegistry instead.
 func ValidateAgainstSchemaFinder(schemaURL string, dataBytes []byte) ([]SchemaValidationMessage, error) {
-	exists, normalizedURL := checkURLExists(schemaURL)
-	if !exists {
-		return nil, fmt.Errorf("schema does not exist or is unreachable: %s", normalizedURL)
-	}
-
-	// Parse YAML into yaml.Node to preserve line info
-	var rootNode yaml.Node
-	if err := yaml.Unmarshal(dataBytes, &rootNode); err != nil {
-		return nil, fmt.Errorf("parse yaml/json into node: %w", err)
-	}
-
-	// Also unmarshal into map[string]interface{} for JSON schema validation
-	var dataMap map[string]interface{}
-	if err := yaml.Unmarshal(dataBytes, &dataMap); err != nil {
-		return nil, fmt.Errorf("parse yaml/json into map: %w", err)
-	}
-
-	props, err := extractTopLevelSchemaProperties(normalizedURL)
+	schema, err := CompileSchema(schemaURL, SchemaOptions{})
 	if err != nil {
-		return nil, fmt.Errorf("schema load failed: %w", err)
+		return nil, err
 	}
+	return schema.Validate(dataBytes)
+}
 
-	var messages []SchemaValidationMessage
-
-	if topLevelFieldMismatch(props, dataMap) {
-		messages = append(messages, SchemaValidationMessage{
-			Type:    MessageTypeWarning,
-			Message: "schema appears irrelevant: no overlapping top-level fields between schema and data.",
-		})
-	}
-
-	jsonDataBytes, err := json.Marshal(dataMap)
-	if err != nil {
-		return nil, fmt.Errorf("to json: %w", err)
-	}
-
-	schemaLoader := gojsonschema.NewReferenceLoader(normalizedURL)
-	documentLoader := gojsonschema.NewBytesLoader(jsonDataBytes)
-
-	result, err := gojsonschema.Validate(schemaLoader, documentLoader)
-	if err != nil {
-		hint := `Note:
-- For absolute $ref (file:///...), run from any folder.
-- For relative $ref (file://...), run from the schema's folder.`
-		return nil, fmt.Errorf("validation error: %w (%s)", err, hint)
-	}
+// nodeLocation is where a path leads to in a YAML node tree.
+type nodeLocation struct {
+	key      *yaml.Node // key node, nil unless the value belongs to a mapping
+	value    *yaml.Node // value node; an alias is resolved to the node it refers to
+	alias    *yaml.Node // first alias passed on the way to the value, nil if none
+	keyAlias *yaml.Node // first alias passed on the way to the key, nil if none
+}
 
-	if !result.Valid() {
-		for _, desc := range result.Errors() {
-			dataType := DetectDataType(dataBytes)
-			if dataType == DataTypeJSON || dataType == DataTypeYAML {
-				// JSON path like: workloads.1.flows.0.processors.4.switch.cases.1.processors.5.log.level
-				path := strings.Split(desc.Field(), ".")
-				node := findNodeByPath(&rootNode, path)
+// Walk YAML node by JSON path segments, e.g. ["workloads", "1", "flows", "0", "processors", "4"].
+// Segments are taken literally, so keys containing dots are found too.
+// Aliases are followed to their anchors, and keys missing from a mapping are looked up in its merge keys (<<),
+// so values shared through anchors are found at every place they are used.
+func findNodeByPath(root *yaml.Node, path []string) nodeLocation {
+	var found nodeLocation
+	node := root
+	for {
+		node = found.follow(node)
+		if node == nil {
+			return nodeLocation{}
+		}
+		if len(path) == 0 {
+			found.value = node
+			return found
+		}
 
-				if node != nil {
-					messages = append(messages, SchemaValidationMessage{
-						Type:    "error",
-						Message: fmt.Sprintf("Line %d: %s: %s", node.Line, desc.Field(), desc.Description()),
-					})
-				} else {
-					messages = append(messages, SchemaValidationMessage{
-						Type:    "error",
-						Message: fmt.Sprintf("Line unknown: %s: %s", desc.Field(), desc.Description()),
-					})
-				}
+		switch node.Kind {
+		case yaml.DocumentNode:
+			if len(node.Content) == 0 {
+				return nodeLocation{}
 			}
-
+			node = node.Content[0]
+			continue
+		case yaml.MappingNode:
+			key, value, via := mappingEntry(node, path[0], make(map[*yaml.Node]bool))
+			if key == nil {
+				return nodeLocation{}
+			}
+			if found.alias == nil {
+				found.alias = via
+			}
+			found.key, found.keyAlias = key, found.alias
+			node = value
+		case yaml.SequenceNode:
+			// Sequence nodes: Content is a list of nodes, key must be an index
+			idx, err := strconv.Atoi(path[0])
+			if err != nil || idx < 0 || idx >= len(node.Content) {
+				return nodeLocation{}
+			}
+			found.key, found.keyAlias = nil, nil
+			node = node.Content[idx]
+		default:
+			return nodeLocation{}
 		}
+		path = path[1:]
 	}
-
-	return messages, nil
 }
 
-// Walk YAML node by JSON path segments, e.g. ["workloads", "1", "flows", "0", "processors", "4"]
-func findNodeByPath(root *yaml.Node, path []string) *yaml.Node {
-	if len(path) == 0 {
-		return root
-	}
-
-	key := path[0]
-	rest := path[1:]
-
-	switch root.Kind {
-	case yaml.DocumentNode:
-		return findNodeByPath(root.Content[0], path)
-	case yaml.MappingNode:
-		// Mapping nodes: Content is [keyNode, valueNode, keyNode, valueNode, ...]
-		for i := 0; i < len(root.Content); i += 2 {
-			k := root.Content[i]
-			v := root.Content[i+1]
-			if k.Value == key {
-				return findNodeByPath(v, rest)
-			}
-		}
-	case yaml.SequenceNode:
-		// Sequence nodes: Content is a list of nodes, key must be an index
-		idx, err := strconv.Atoi(key)
-		if err == nil && idx < len(root.Content) {
-			return findNodeByPath(root.Content[idx], rest)
+// follow resolves an alias to the node it refers to, remembering the first alias passed.
+func (l *nodeLocation) follow(node *yaml.Node) *yaml.Node {
+	if node != nil && node.Kind == yaml.AliasNode {
+		if l.alias == nil {
+			l.alias = node
 		}
+		return node.Alias
 	}
-	return nil
+	return node
 }
 
-func extractTopLevelSchemaProperties(schemaURL string) (map[string]interface{}, error) {
-	var schemaBytes []byte
-	var err error
-
-	if strings.HasPrefix(schemaURL, "file://") {
-		localPath := strings.TrimPrefix(schemaURL, "file://")
-		schemaBytes, err = os.ReadFile(localPath)
-		if err != nil {
-			return nil, fmt.Errorf("read schema file: %w", err)
-		}
-	} else {
-		resp, err := http.Get(schemaURL)
-		if err != nil {
-			return nil, fmt.Errorf("fetch schema: %w", err)
-		}
-		defer resp.Body.Close()
+func extractTopLevelSchemaProperties(document interface{}) map[string]interface{} {
+	schemaMap, _ := document.(map[string]interface{})
+	props, _ := schemaMap["properties"].(map[string]interface{})
+	return props
+}
 
-		schemaBytes, err = io.ReadAll(resp.Body)
-		if err != nil {
-			return nil, fmt.Errorf("read schema response: %w", err)
+// topLevelFieldMismatch reports whether the data looks unrelated to the schema. For an object, none of its
+// keys is a top-level property of the schema; for an array, none of the keys of its object items is a
+// property of the schema that applies to that item. Scalars and arrays without objects have no fields to compare.
+func topLevelFieldMismatch(schema interface{}, data interface{}) bool {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		return !hasMatchingField(extractTopLevelSchemaProperties(schema), v)
+	case []interface{}:
+		objects := 0
+		for i, item := range v {
+			object, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			objects++
+			if hasMatchingField(extractTopLevelSchemaProperties(itemSchema(schema, i)), object) {
+				return false
+			}
 		}
+		return objects > 0
+	default:
+		return false
 	}
-
-	var schemaMap map[string]interface{}
-	if err := json.Unmarshal(schemaBytes, &schemaMap); err != nil {
-		return nil, fmt.Errorf("parse schema JSON: %w", err)
-	}
-
-	props, _ := schemaMap["properties"].(map[string]interface{})
-	return props, nil
 }
 
-func topLevelFieldMismatch(schemaProps map[string]interface{}, data map[string]interface{}) bool {
+func hasMatchingField(schemaProps map[string]interface{}, data map[string]interface{}) bool {
 	for key := range data {
 		if _, ok := schemaProps[key]; ok {
-			return false // ✅ At least one field matches
+			return true // ✅ At least one field matches
 		}
 	}
-	return true // ❌ No matches found — schema likely irrelevant
+	return false // ❌ No matches found — schema likely irrelevant
 }
 
-func checkURLExists(pathOrURL string) (bool, string) {
-	parsedURL, err := url.Parse(pathOrURL)
-	// If not a valid URL or it's a file URL or missing scheme, treat as local file
-	if err != nil || parsedURL.Scheme == "" || parsedURL.Scheme == "file" {
-		var normalizedPath string
-		if parsedURL != nil && parsedURL.Scheme == "file" {
-			// For file URLs, use .Path (which is always slash-separated)
-			// On Windows, remove leading slash if present (e.g., /C:/path)
-			normalizedPath = parsedURL.Path
-			if runtime.GOOS == "windows" && strings.HasPrefix(normalizedPath, "/") && len(normalizedPath) > 2 && normalizedPath[2] == ':' {
-				normalizedPath = normalizedPath[1:]
-			}
-			// URL decoding
-			normalizedPath, _ = url.PathUnescape(normalizedPath)
-		} else {
-			// Just a path, not a file URL
-			normalizedPath = pathOrURL
-		}
-		// Convert slashes for Windows if needed
-		if runtime.GOOS == "windows" {
-			normalizedPath = filepath.FromSlash(normalizedPath)
-		}
-		_, err := os.Stat(normalizedPath)
-		if err == nil {
-			// File exists, return with file:// prefix (properly constructed)
-			fileURL := (&url.URL{Scheme: "file", Path: filepath.ToSlash(normalizedPath)}).String()
-			return true, fileURL
-		}
-		fileURL := (&url.URL{Scheme: "file", Path: filepath.ToSlash(normalizedPath)}).String()
-		return false, fileURL
-	}
-
-	// Handle remote URL
-	client := http.Client{
-		Timeout: 5 * time.Second,
+// itemSchema returns the schema that applies to the item at index of an array: from prefixItems (2020-12),
+// a tuple "items" and "additionalItems" (earlier drafts), or a single "items" schema. Nil if there is none.
+func itemSchema(schema interface{}, index int) interface{} {
+	schemaMap, _ := schema.(map[string]interface{})
+	if prefixItems, ok := schemaMap["prefixItems"].([]interface{}); ok && index < len(prefixItems) {
+		return prefixItems[index]
 	}
-	resp, err := client.Head(pathOrURL)
-	if err != nil {
-		return false, pathOrURL
+	if tupleItems, ok := schemaMap["items"].([]interface{}); ok {
+		if index < len(tupleItems) {
+			return tupleItems[index]
+		}
+		return schemaMap["additionalItems"]
 	}
-	defer resp.Body.Close()
-
-	return resp.StatusCode == http.StatusOK, pathOrURL
+	return schemaMap["items"]
 }
@@ -0,0 +1,213 @@
+package yjvalid8r_lib
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultMaxAliasExpansion is the number of nodes aliases may add to a document when it is decoded.
+// It is far above what shared configuration blocks need, but stops "billion laughs" documents,
+// whose few lines of nested aliases expand to billions of values.
+const DefaultMaxAliasExpansion = 100000
+
+// CheckAliasExpansion reports the first document whose aliases, once expanded, add more than limit nodes,
+// and documents with an anchor that contains an alias to itself. Validation, search paths and schema
+// inference decode every alias into a copy of its anchored value, so untrusted data should be checked first.
+// A limit of 0 means DefaultMaxAliasExpansion; a negative limit allows any expansion.
+func CheckAliasExpansion(docs []Document, limit int) error {
+	if limit == 0 {
+		limit = DefaultMaxAliasExpansion
+	}
+	for _, doc := range docs {
+		counter := aliasCounter{limit: -1, sizes: make(map[*yaml.Node]int), open: make(map[*yaml.Node]bool)}
+		if limit > 0 {
+			counter.limit = limit + countNodes(doc.Node)
+		}
+		size, err := counter.expandedSize(doc.Node)
+		if err != nil {
+			return fmt.Errorf("document %d: %w", doc.Index, err)
+		}
+		if limit > 0 && size > counter.limit {
+			return fmt.Errorf("document %d: aliases expand to more than %d nodes", doc.Index, limit)
+		}
+	}
+	return nil
+}
+
+// countNodes counts the nodes of a tree without following aliases.
+func countNodes(node *yaml.Node) int {
+	if node == nil {
+		return 0
+	}
+	count := 1
+	for _, child := range node.Content {
+		count += countNodes(child)
+	}
+	return count
+}
+
+// aliasCounter computes the size of node trees with their aliases expanded. Sizes of shared nodes are
+// computed once and capped just above the limit, so even deeply nested aliases are counted quickly.
+type aliasCounter struct {
+	limit int                 // size at which counting stops; negative for no limit
+	sizes map[*yaml.Node]int  // expanded size of the nodes counted so far
+	open  map[*yaml.Node]bool // nodes being counted, to detect anchors containing themselves
+}
+
+func (c *aliasCounter) expandedSize(node *yaml.Node) (int, error) {
+	if node == nil {
+		return 0, nil
+	}
+	if node.Kind == yaml.AliasNode {
+		if c.open[node.Alias] {
+			return 0, fmt.Errorf("line %d: anchor &%s contains an alias to itself", node.Line, node.Value)
+		}
+		return c.expandedSize(node.Alias)
+	}
+	if size, ok := c.sizes[node]; ok {
+		return size, nil
+	}
+
+	c.open[node] = true
+	size := 1
+	for _, child := range node.Content {
+		childSize, err := c.expandedSize(child)
+		if err != nil {
+			return 0, err
+		}
+		size += childSize
+		if c.limit > 0 && size > c.limit {
+			size = c.limit + 1
+			break
+		}
+	}
+	delete(c.open, node)
+	c.sizes[node] = size
+	return size, nil
+}
+
+// mappingEntry returns the key and value nodes of a key in a mapping. Keys defined in the mapping itself take
+// precedence over merged ones; merge keys (<<) are searched in order, following their aliases. via is the first
+// alias passed to reach a merged key, nil if none. visited guards against merges of a mapping into itself.
+func mappingEntry(mapping *yaml.Node, key string, visited map[*yaml.Node]bool) (keyNode, value, via *yaml.Node) {
+	if visited[mapping] {
+		return nil, nil, nil
+	}
+	visited[mapping] = true
+
+	// Mapping nodes: Content is [keyNode, valueNode, keyNode, valueNode, ...]
+	var merges []*yaml.Node
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		k := mapping.Content[i]
+		if isMergeKey(k) {
+			merges = append(merges, mapping.Content[i+1])
+			continue
+		}
+		if k.Value == key {
+			return k, mapping.Content[i+1], nil
+		}
+	}
+
+	for _, merge := range merges {
+		// <<: *base or <<: [*base, *other]
+		sources := []*yaml.Node{merge}
+		if merge.Kind == yaml.SequenceNode {
+			sources = merge.Content
+		}
+		for _, source := range sources {
+			var alias *yaml.Node
+			if source.Kind == yaml.AliasNode {
+				alias, source = source, source.Alias
+			}
+			if source == nil || source.Kind != yaml.MappingNode {
+				continue
+			}
+			if keyNode, value, nested := mappingEntry(source, key, visited); keyNode != nil {
+				if alias == nil {
+					alias = nested
+				}
+				return keyNode, value, alias
+			}
+		}
+	}
+	return nil, nil, nil
+}
+
+// followAlias resolves an alias to the node it refers to. at is the node itself, where the value appears in the document.
+func followAlias(node *yaml.Node) (value, at *yaml.Node) {
+	if node.Kind == yaml.AliasNode && node.Alias != nil {
+		return node.Alias, node
+	}
+	return node, node
+}
+
+// mappingValue returns the value of a key in a mapping, or in the mappings it merges, with aliases resolved
+// like followAlias. The value is nil if node is not a mapping or has no such key.
+func mappingValue(node *yaml.Node, key string) (value, at *yaml.Node) {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	keyNode, value, _ := mappingEntry(node, key, make(map[*yaml.Node]bool))
+	if keyNode == nil {
+		return nil, nil
+	}
+	return followAlias(value)
+}
+
+// mappingPair is a key and its value, resolved like followAlias.
+type mappingPair struct {
+	key, value, at *yaml.Node
+}
+
+// mappingPairs returns the pairs of a mapping as a decoder sees them, in document order: each merge key (<<) is
+// replaced by the pairs it merges, leaving out keys defined earlier or in the mapping itself.
+func mappingPairs(mapping *yaml.Node) []mappingPair {
+	return collectMappingPairs(mapping, make(map[*yaml.Node]bool))
+}
+
+func collectMappingPairs(mapping *yaml.Node, visited map[*yaml.Node]bool) []mappingPair {
+	if visited[mapping] {
+		return nil
+	}
+	visited[mapping] = true
+	defer delete(visited, mapping)
+
+	defined := make(map[string]bool)
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if key := mapping.Content[i]; !isMergeKey(key) {
+			defined[key.Value] = true
+		}
+	}
+
+	var pairs []mappingPair
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		key, value := mapping.Content[i], mapping.Content[i+1]
+		if !isMergeKey(key) {
+			value, at := followAlias(value)
+			pairs = append(pairs, mappingPair{key: key, value: value, at: at})
+			continue
+		}
+		sources := []*yaml.Node{value}
+		if value.Kind == yaml.SequenceNode {
+			sources = value.Content
+		}
+		for _, source := range sources {
+			if source, _ = followAlias(source); source.Kind != yaml.MappingNode {
+				continue
+			}
+			for _, pair := range collectMappingPairs(source, visited) {
+				if !defined[pair.key.Value] {
+					defined[pair.key.Value] = true
+					pairs = append(pairs, pair)
+				}
+			}
+		}
+	}
+	return pairs
+}
+
+// isMergeKey reports whether a mapping key is the YAML merge key: a plain <<, not a quoted "<<".
+func isMergeKey(node *yaml.Node) bool {
+	return node.Kind == yaml.ScalarNode && node.Value == "<<" && node.Tag == "!!merge"
+}
@@ -0,0 +1,466 @@
+package yjvalid8r_lib
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Defaults of the configurable YAML lint rule options.
+const (
+	DefaultYAMLLintMaxLineLength        = 80
+	DefaultYAMLLintMinSpacesFromContent = 2
+)
+
+// yamlTruthyValues are the plain scalars YAML 1.1 reads as booleans.
+var yamlTruthyValues = map[string]bool{
+	"YES": true, "Yes": true, "yes": true, "NO": true, "No": true, "no": true,
+	"TRUE": true, "True": true, "true": true, "FALSE": true, "False": true, "false": true,
+	"ON": true, "On": true, "on": true, "OFF": true, "Off": true, "off": true,
+}
+
+// Validate checks the severities and options of the rules.
+func (c YAMLLintConfig) Validate() error {
+	rules := []struct {
+		name string
+		rule YAMLLintRule
+	}{
+		{"keyDuplicates", c.KeyDuplicates},
+		{"keyOrdering", c.KeyOrdering},
+		{"truthy", c.Truthy.YAMLLintRule},
+		{"documentStart", c.DocumentStart.YAMLLintRule},
+		{"lineLength", c.LineLength.YAMLLintRule},
+		{"comments", c.Comments.YAMLLintRule},
+		{"quotedStrings", c.QuotedStrings.YAMLLintRule},
+		{"braces", c.Braces.YAMLLintRule},
+	}
+	for _, named := range rules {
+		switch named.rule.Severity {
+		case "", MessageTypeError, MessageTypeWarning, MessageTypeInfo:
+		default:
+			return fmt.Errorf("yaml lint rule %s: unknown severity %q", named.name, named.rule.Severity)
+		}
+	}
+
+	switch c.QuotedStrings.QuoteType {
+	case "", "any", "single", "double":
+	default:
+		return fmt.Errorf("yaml lint rule quotedStrings: unknown quoteType %q, expected any, single or double", c.QuotedStrings.QuoteType)
+	}
+	switch c.QuotedStrings.Required {
+	case "", "true", "false", "only-when-needed":
+	default:
+		return fmt.Errorf("yaml lint rule quotedStrings: unknown required %q, expected true, false or only-when-needed", c.QuotedStrings.Required)
+	}
+	if c.LineLength.Max < 0 {
+		return fmt.Errorf("yaml lint rule lineLength: max must not be negative")
+	}
+	if c.Braces.MaxSpacesInside != -1 && c.Braces.MinSpacesInside > c.Braces.MaxSpacesInside {
+		return fmt.Errorf("yaml lint rule braces: invalid spaces inside, min %d and max %d", c.Braces.MinSpacesInside, c.Braces.MaxSpacesInside)
+	}
+	return nil
+}
+
+// resolve returns whether the rule is enabled and its severity.
+func (r YAMLLintRule) resolve(defaultEnabled bool, defaultSeverity ValidationMessageType) (bool, ValidationMessageType) {
+	enabled := defaultEnabled
+	if r.Enabled != nil {
+		enabled = *r.Enabled
+	}
+	severity := r.Severity
+	if severity == "" {
+		severity = defaultSeverity
+	}
+	return enabled, severity
+}
+
+// yamlLintRule is a resolved rule: the zero value is disabled.
+type yamlLintRule struct {
+	enabled  bool
+	severity ValidationMessageType
+}
+
+func newYAMLLintRule(rule YAMLLintRule, defaultEnabled bool, defaultSeverity ValidationMessageType) yamlLintRule {
+	enabled, severity := rule.resolve(defaultEnabled, defaultSeverity)
+	return yamlLintRule{enabled: enabled, severity: severity}
+}
+
+// yamlLinter holds the state of one LintYAML run.
+type yamlLinter struct {
+	config YAMLLintConfig
+	report *findingReport
+	lines  [][]rune     // lines of the data without line endings
+	block  map[int]bool // 1-based numbers of the lines inside block scalars
+
+	keyDuplicates, keyOrdering, truthy, documentStart yamlLintRule
+	lineLength, comments, quotedStrings, braces       yamlLintRule
+	truthyAllowed                                     map[string]bool
+}
+
+// LintYAML checks the style of YAML data, like yamllint: duplicate keys, key ordering, truthy values,
+// the document start marker, line length, comment spacing, quoting of strings and spaces inside braces.
+// Each rule can be disabled, given a severity and configured; see YAMLLintConfig.
+// Rules on the document structure are skipped when the data cannot be parsed.
+func LintYAML(dataBytes []byte, config YAMLLintConfig) YAMLLintResult {
+	data := strings.TrimPrefix(string(dataBytes), utf8BOM)
+	l := &yamlLinter{
+		config:        config,
+		report:        newFindingReport(),
+		block:         make(map[int]bool),
+		keyDuplicates: newYAMLLintRule(config.KeyDuplicates, true, MessageTypeError),
+		keyOrdering:   newYAMLLintRule(config.KeyOrdering, false, MessageTypeWarning),
+		truthy:        newYAMLLintRule(config.Truthy.YAMLLintRule, true, MessageTypeWarning),
+		documentStart: newYAMLLintRule(config.DocumentStart.YAMLLintRule, true, MessageTypeWarning),
+		lineLength:    newYAMLLintRule(config.LineLength.YAMLLintRule, true, MessageTypeWarning),
+		comments:      newYAMLLintRule(config.Comments.YAMLLintRule, true, MessageTypeWarning),
+		quotedStrings: newYAMLLintRule(config.QuotedStrings.YAMLLintRule, false, MessageTypeWarning),
+		braces:        newYAMLLintRule(config.Braces.YAMLLintRule, true, MessageTypeWarning),
+		truthyAllowed: map[string]bool{"true": true, "false": true},
+	}
+	if len(config.Truthy.AllowedValues) > 0 {
+		l.truthyAllowed = make(map[string]bool)
+		for _, value := range config.Truthy.AllowedValues {
+			l.truthyAllowed[value] = true
+		}
+	}
+	for _, line := range strings.Split(data, "\n") {
+		l.lines = append(l.lines, []rune(strings.TrimSuffix(line, "\r")))
+	}
+
+	docs, err := decodeYAMLDocuments([]byte(data))
+	if err != nil {
+		l.report.messages = append(l.report.messages, fmt.Sprintf("YAML lint rules on the document structure skipped: %v", err))
+	}
+	for _, doc := range docs {
+		l.walk(doc.Node, doc.Index, false)
+	}
+
+	l.checkDocumentStart(len(docs) > 0)
+	l.checkLines()
+
+	return YAMLLintResult{
+		Valid:    len(l.report.errors) == 0,
+		Errors:   l.report.errors,
+		Warnings: l.report.warnings,
+		Messages: l.report.messages,
+		Findings: l.report.sortedFindings(),
+	}
+}
+
+func (l *yamlLinter) add(rule yamlLintRule, ruleID, message string, line, column, endColumn int) {
+	l.report.add(lineFinding(rule.severity, ruleID, message, line, column, endColumn))
+}
+
+func (l *yamlLinter) addNode(rule yamlLintRule, ruleID, message string, document int, node *yaml.Node) {
+	l.report.add(nodeFinding(rule.severity, ruleID, message, document, node))
+}
+
+// walk checks a node of the given document and its descendants; isKey tells whether the node is a mapping key.
+func (l *yamlLinter) walk(node *yaml.Node, document int, isKey bool) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			l.walk(child, document, false)
+		}
+	case yaml.SequenceNode:
+		for _, child := range node.Content {
+			l.walk(child, document, false)
+		}
+	case yaml.MappingNode:
+		l.checkKeys(node, document)
+		if node.Style&yaml.FlowStyle != 0 && l.braces.enabled {
+			l.checkBraces(node)
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			l.walk(node.Content[i], document, true)
+			l.walk(node.Content[i+1], document, false)
+		}
+	case yaml.ScalarNode:
+		if node.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 {
+			endLine, _ := nodeEnd(node)
+			for line := node.Line + 1; line <= endLine; line++ {
+				l.block[line] = true
+			}
+		}
+		l.checkTruthy(node, document, isKey)
+		if !isKey {
+			l.checkQuotedString(node, document)
+		}
+	}
+}
+
+// checkKeys reports duplicate keys and keys out of alphabetical order in a mapping.
+func (l *yamlLinter) checkKeys(mapping *yaml.Node, document int) {
+	if l.keyDuplicates.enabled {
+		for _, duplicate := range duplicateMappingKeys(mapping) {
+			l.addNode(l.keyDuplicates, RuleYAMLKeyDuplicates, duplicateKeyMessage(duplicate.key.Value, duplicate.first.Line), document, duplicate.key)
+		}
+	}
+
+	var previous string
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		key := mapping.Content[i]
+		if key.Kind != yaml.ScalarNode || key.Value == "<<" {
+			continue
+		}
+		if l.keyOrdering.enabled && i > 0 && key.Value < previous {
+			l.addNode(l.keyOrdering, RuleYAMLKeyOrdering, fmt.Sprintf("Wrong ordering of key %q in mapping.", key.Value), document, key)
+		}
+		previous = key.Value
+	}
+}
+
+// checkTruthy reports plain truthy values (yes, On, ...) that are not allowed.
+func (l *yamlLinter) checkTruthy(node *yaml.Node, document int, isKey bool) {
+	if !l.truthy.enabled || node.Style != 0 || !yamlTruthyValues[node.Value] || l.truthyAllowed[node.Value] {
+		return
+	}
+	if isKey && l.config.Truthy.CheckKeys != nil && !*l.config.Truthy.CheckKeys {
+		return
+	}
+
+	allowed := make([]string, 0, len(l.truthyAllowed))
+	for value := range l.truthyAllowed {
+		allowed = append(allowed, value)
+	}
+	sort.Strings(allowed)
+	l.addNode(l.truthy, RuleYAMLTruthy, fmt.Sprintf("Truthy value should be one of [%s].", strings.Join(allowed, ", ")), document, node)
+}
+
+// checkQuotedString reports string values quoted against the quoteType and required options.
+func (l *yamlLinter) checkQuotedString(node *yaml.Node, document int) {
+	if !l.quotedStrings.enabled || node.Tag != "!!str" || strings.Contains(node.Value, "\n") {
+		return
+	}
+	if node.Style&(yaml.LiteralStyle|yaml.FoldedStyle|yaml.TaggedStyle) != 0 {
+		return
+	}
+
+	quoteType := l.config.QuotedStrings.QuoteType
+	style := ""
+	switch {
+	case node.Style&yaml.DoubleQuotedStyle != 0:
+		style = "double"
+	case node.Style&yaml.SingleQuotedStyle != 0:
+		style = "single"
+	}
+
+	switch {
+	case style == "" && (l.config.QuotedStrings.Required == "" || l.config.QuotedStrings.Required == "true"):
+		l.addNode(l.quotedStrings, RuleYAMLQuotedStrings, "String value is not quoted.", document, node)
+	case style != "" && l.config.QuotedStrings.Required == "only-when-needed" && !yamlNeedsQuotes(node.Value):
+		l.addNode(l.quotedStrings, RuleYAMLQuotedStrings, fmt.Sprintf("String value is redundantly quoted with %s quotes.", style), document, node)
+	case style != "" && quoteType != "" && quoteType != "any" && style != quoteType:
+		l.addNode(l.quotedStrings, RuleYAMLQuotedStrings, fmt.Sprintf("String value is not quoted with %s quotes.", quoteType), document, node)
+	}
+}
+
+// yamlNeedsQuotes tells whether a string would be read differently, or not at all, as a plain scalar.
+func yamlNeedsQuotes(value string) bool {
+	out, err := yaml.Marshal(value)
+	return err != nil || strings.TrimSuffix(string(out), "\n") != value
+}
+
+// checkBraces reports too few or too many spaces after "{" and before "}" of a flow mapping.
+func (l *yamlLinter) checkBraces(mapping *yaml.Node) {
+	line, column := mapping.Line-1, mapping.Column-1
+	if len(mapping.Content) == 0 || line >= len(l.lines) || column >= len(l.lines[line]) || l.lines[line][column] != '{' {
+		return
+	}
+
+	// After "{", unless the content continues on the next line
+	runes := l.lines[line]
+	after := 0
+	for column+1+after < len(runes) && runes[column+1+after] == ' ' {
+		after++
+	}
+	if column+1+after < len(runes) {
+		l.checkBraceSpaces(after, mapping.Line, column+2)
+	}
+
+	// Before "}", unless it starts its line
+	endLine, endColumn, ok := l.matchingBrace(line, column)
+	if !ok {
+		return
+	}
+	runes = l.lines[endLine]
+	before := 0
+	for endColumn-before-1 >= 0 && runes[endColumn-before-1] == ' ' {
+		before++
+	}
+	if endColumn-before > 0 {
+		l.checkBraceSpaces(before, endLine+1, endColumn-before+1)
+	}
+}
+
+func (l *yamlLinter) checkBraceSpaces(spaces, line, column int) {
+	minSpaces, maxSpaces := l.config.Braces.MinSpacesInside, l.config.Braces.MaxSpacesInside
+	switch {
+	case maxSpaces >= 0 && spaces > maxSpaces:
+		l.add(l.braces, RuleYAMLBraces, "Too many spaces inside braces.", line, column, column+spaces)
+	case spaces < minSpaces:
+		l.add(l.braces, RuleYAMLBraces, "Too few spaces inside braces.", line, column, column+spaces)
+	}
+}
+
+// matchingBrace returns the 0-based position of the "}" closing the "{" at line, column,
+// skipping nested collections, quoted strings and comments.
+func (l *yamlLinter) matchingBrace(line, column int) (int, int, bool) {
+	depth := 0
+	for ; line < len(l.lines); line, column = line+1, 0 {
+		runes := l.lines[line]
+		for ; column < len(runes); column++ {
+			switch ch := runes[column]; ch {
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+				if depth == 0 {
+					return line, column, ch == '}'
+				}
+			case '"', '\'':
+				column = closingQuote(runes, column)
+			case '#':
+				if column == 0 || runes[column-1] == ' ' || runes[column-1] == '\t' {
+					column = len(runes)
+				}
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// closingQuote returns the index of the quote closing the one at start, or the end of the line.
+func closingQuote(runes []rune, start int) int {
+	quote := runes[start]
+	for i := start + 1; i < len(runes); i++ {
+		switch {
+		case quote == '"' && runes[i] == '\\':
+			i++
+		case quote == '\'' && runes[i] == '\'' && i+1 < len(runes) && runes[i+1] == '\'':
+			i++ // escaped quote
+		case runes[i] == quote:
+			return i
+		}
+	}
+	return len(runes)
+}
+
+// checkDocumentStart reports a missing (or, with present: false, any) "---" marker.
+func (l *yamlLinter) checkDocumentStart(hasContent bool) {
+	if !l.documentStart.enabled {
+		return
+	}
+	present := l.config.DocumentStart.Present == nil || *l.config.DocumentStart.Present
+
+	for i, runes := range l.lines {
+		line := string(runes)
+		isMarker := line == "---" || strings.HasPrefix(line, "--- ") || strings.HasPrefix(line, "---\t")
+		if !present {
+			if isMarker {
+				l.add(l.documentStart, RuleYAMLDocumentStart, `Found forbidden document start "---".`, i+1, 1, 4)
+			}
+			continue
+		}
+
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(line, "%") {
+			continue // comments and directives may precede the marker
+		}
+		if !isMarker && hasContent {
+			l.add(l.documentStart, RuleYAMLDocumentStart, `Missing document start "---".`, i+1, 1, 1)
+		}
+		return
+	}
+}
+
+// checkLines runs the line-based rules: line length and comments.
+func (l *yamlLinter) checkLines() {
+	maxLength := l.config.LineLength.Max
+	if maxLength == 0 {
+		maxLength = DefaultYAMLLintMaxLineLength
+	}
+	allowNonBreakable := l.config.LineLength.AllowNonBreakableWords == nil || *l.config.LineLength.AllowNonBreakableWords
+
+	for i, runes := range l.lines {
+		if l.lineLength.enabled && len(runes) > maxLength && !(allowNonBreakable && isNonBreakableLine(runes)) {
+			l.add(l.lineLength, RuleYAMLLineLength, fmt.Sprintf("Line too long (%d > %d characters).", len(runes), maxLength), i+1, maxLength+1, len(runes)+1)
+		}
+		if l.comments.enabled && !l.block[i+1] {
+			if column := commentColumn(runes); column >= 0 {
+				l.checkComment(runes, i+1, column)
+			}
+		}
+	}
+}
+
+// isNonBreakableLine tells whether a line is a single word, e.g. a long URL, after its indentation
+// and a leading "- " or "# ".
+func isNonBreakableLine(runes []rune) bool {
+	start := 0
+	for start < len(runes) && runes[start] == ' ' {
+		start++
+	}
+	if start == len(runes) {
+		return false
+	}
+	switch runes[start] {
+	case '#':
+		for start < len(runes) && runes[start] == '#' {
+			start++
+		}
+		start++
+	case '-':
+		start += 2
+	}
+	return start >= len(runes) || !strings.ContainsRune(string(runes[start:]), ' ')
+}
+
+// commentColumn returns the 0-based index of the "#" starting a comment on a line, or -1.
+func commentColumn(runes []rune) int {
+	for i := 0; i < len(runes); i++ {
+		switch runes[i] {
+		case '"', '\'':
+			if i == 0 || strings.ContainsRune(" \t[{,", runes[i-1]) {
+				i = closingQuote(runes, i)
+			}
+		case '#':
+			if i == 0 || runes[i-1] == ' ' || runes[i-1] == '\t' {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// checkComment checks the starting space of a comment and its distance from the content before it.
+func (l *yamlLinter) checkComment(runes []rune, line, column int) {
+	requireStartingSpace := l.config.Comments.RequireStartingSpace == nil || *l.config.Comments.RequireStartingSpace
+	minSpaces := l.config.Comments.MinSpacesFromContent
+	if minSpaces == 0 {
+		minSpaces = DefaultYAMLLintMinSpacesFromContent
+	}
+
+	isShebang := line == 1 && column == 0 && strings.HasPrefix(string(runes), "#!")
+	if requireStartingSpace && !isShebang {
+		i := column + 1
+		for i < len(runes) && runes[i] == '#' {
+			i++
+		}
+		if i < len(runes) && runes[i] != ' ' && runes[i] != '\t' {
+			l.add(l.comments, RuleYAMLComments, "Missing starting space in comment.", line, i+1, i+2)
+		}
+	}
+
+	if content := strings.TrimSpace(string(runes[:column])); content != "" {
+		spaces := 0
+		for column-spaces-1 >= 0 && (runes[column-spaces-1] == ' ' || runes[column-spaces-1] == '\t') {
+			spaces++
+		}
+		if spaces < minSpaces {
+			l.add(l.comments, RuleYAMLComments, "Too few spaces before comment.", line, column+1, column+2)
+		}
+	}
+}
@@ -7,8 +7,11 @@ require (
 	gopkg.in/yaml.v3 v3.0.1
 )
 
-// replace github.com/sassoftware/yj-valid8r/yj-valid8r-lib => ../yj-valid8r-lib // Local replace for development purposes
-// replace github.com/sassoftware/yj-valid8r/yj-valid8r-common => ../yj-valid8r-common // Local replace for development purposes
+require github.com/santhosh-tekuri/jsonschema/v6 v6.0.3 // indirect
+
+replace github.com/sassoftware/yj-valid8r/yj-valid8r-lib => ../yj-valid8r-lib // Local replace for development purposes
+
+replace github.com/sassoftware/yj-valid8r/yj-valid8r-common => ../yj-valid8r-common // Local replace for development purposes
 
 require (
 	github.com/bytedance/sonic v1.11.6 // indirect
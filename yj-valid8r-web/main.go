@@ -1,7 +1,51 @@
 package main
 
-import "github.com/sassoftware/yj-valid8r/yj-valid8r-web/web"
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+
+	validator "github.com/sassoftware/yj-valid8r/yj-valid8r-lib"
+	"github.com/sassoftware/yj-valid8r/yj-valid8r-web/web"
+)
 
 func main() {
-	web.StartServer()
+	schemaCacheDirFlag := flag.String("schemaCacheDir", "", "Directory used to cache remote schemas (enables the schema cache)")
+	schemaCacheTTLFlag := flag.String("schemaCacheTTL", "", "How long cached schemas are used before refetching, e.g. \"24h\" (enables the schema cache)")
+	offlineFlag := flag.Bool("offline", false, "Never touch the network; only use cached or local schemas (enables the schema cache)")
+	schemaPinsFlag := flag.String("schemaPins", "", "JSON object mapping schema URLs to sha256 digests (enables the schema cache)")
+	schemaMappingsFlag := flag.String("schemaMappings", "", "JSON array of {\"prefix\", \"dir\"} objects serving schema URLs from local directories")
+	maxAliasExpansionFlag := flag.Int("maxAliasExpansion", 0, fmt.Sprintf("Number of nodes YAML aliases may add to a document before it is rejected (default %d); -1 allows any", validator.DefaultMaxAliasExpansion))
+	schemaDraftFlag := flag.String("schemaDraft", "", "JSON Schema draft: \"auto\" (from $schema), \"draft-04\", \"draft-06\", \"draft-07\", \"2019-09\", \"2020-12\"")
+
+	flag.Parse()
+
+	var schemaCacheConfig *validator.SchemaCacheConfig
+	if *schemaCacheDirFlag != "" || *schemaCacheTTLFlag != "" || *offlineFlag || *schemaPinsFlag != "" {
+		schemaCacheConfig = &validator.SchemaCacheConfig{
+			Dir:     *schemaCacheDirFlag,
+			TTL:     *schemaCacheTTLFlag,
+			Offline: *offlineFlag,
+		}
+		if *schemaPinsFlag != "" {
+			if err := json.Unmarshal([]byte(*schemaPinsFlag), &schemaCacheConfig.Pins); err != nil {
+				log.Fatalf("Error parsing --schemaPins: %v\n", err)
+			}
+		}
+	}
+
+	var schemaMappings []validator.SchemaMapping
+	if *schemaMappingsFlag != "" {
+		if err := json.Unmarshal([]byte(*schemaMappingsFlag), &schemaMappings); err != nil {
+			log.Fatalf("Error parsing --schemaMappings: %v\n", err)
+		}
+	}
+
+	schemaDraft, err := validator.ParseSchemaDraft(*schemaDraftFlag)
+	if err != nil {
+		log.Fatalf("Error parsing --schemaDraft: %v\n", err)
+	}
+
+	web.StartServer(schemaCacheConfig, schemaMappings, schemaDraft, *maxAliasExpansionFlag)
 }
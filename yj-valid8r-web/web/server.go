@@ -18,8 +18,24 @@ import (
 //go:embed templates/*
 var tmpl embed.FS
 
-func StartServer() {
+func StartServer(schemaCacheConfig *validator.SchemaCacheConfig, schemaMappings []validator.SchemaMapping, schemaDraft validator.SchemaDraft, maxAliasExpansion int) {
 	log.Println("Application started")
+
+	// Remote schemas are cached on disk when configured; compiled schemas are shared by all requests
+	var schemaCache *validator.SchemaCache
+	if schemaCacheConfig != nil {
+		var err error
+		schemaCache, err = validator.NewSchemaCache(*schemaCacheConfig)
+		if err != nil {
+			log.Fatalf("Failed to set up schema cache: %v", err)
+		}
+	}
+	schemaRegistry := validator.NewSchemaRegistry(validator.SchemaOptions{
+		Cache:    schemaCache,
+		Resolver: validator.NewPrefixSchemaResolver(schemaMappings),
+		Draft:    schemaDraft,
+	})
+
 	port := "7070"
 	router := gin.New()
 	router.Use(gin.Recovery())
@@ -34,14 +50,20 @@ func StartServer() {
 	router.GET("/", func(c *gin.Context) {
 		c.HTML(http.StatusOK, "validator.html", nil)
 	})
-	router.POST("/api/validate", handleValidate)
+	router.POST("/api/validate", handleValidate(schemaRegistry, maxAliasExpansion))
 
 	if err := router.Run(":" + port); err != nil {
 		log.Fatalf("Server error: %v\n", err)
 	}
 }
 
-func handleValidate(c *gin.Context) {
+func handleValidate(schemaRegistry *validator.SchemaRegistry, maxAliasExpansion int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		validate(c, schemaRegistry, maxAliasExpansion)
+	}
+}
+
+func validate(c *gin.Context, schemaRegistry *validator.SchemaRegistry, maxAliasExpansion int) {
 	contentType := c.GetHeader("Content-Type")
 	var req internal.ValidationRequest
 
@@ -76,9 +98,11 @@ func handleValidate(c *gin.Context) {
 
 	dataBytes := []byte(req.Data)
 
-	if validator.IsUnknownDataType(dataBytes) {
+	detection := validator.DetectDataFormat(dataBytes)
+	if detection.Format == validator.DataTypeUNKNOWN && req.JSONLines == nil {
 		c.JSON(http.StatusUnsupportedMediaType, gin.H{
-			"error": "Provided data is neither valid JSON nor YAML. Please check if your YAML/JSON is correct.",
+			"error":     "Provided data is not valid JSON, YAML, TOML or JSON5: " + detection.Error,
+			"detection": detection,
 		})
 		return
 	}
@@ -88,7 +112,26 @@ func handleValidate(c *gin.Context) {
 		checkTrailingWhitespace = *req.CheckTrailingWhitespace
 	}
 
-	results := internal.InitValidation(req.Schemas, dataBytes, checkTrailingWhitespace, req.RegexPatternRules, req.SearchPaths, req.Plugins)
+	checkDuplicateKeys := false
+	if req.CheckDuplicateKeys != nil {
+		checkDuplicateKeys = *req.CheckDuplicateKeys
+	}
+
+	results := internal.InitValidation(dataBytes, internal.ValidationOptions{
+		Detection:               &detection,
+		Schemas:                 req.Schemas,
+		SchemaRoutes:            req.SchemaRoutes,
+		SchemaRegistry:          schemaRegistry,
+		JSONLines:               req.JSONLines,
+		MaxAliasExpansion:       maxAliasExpansion,
+		CheckTrailingWhitespace: checkTrailingWhitespace,
+		Formatting:              req.Formatting,
+		CheckDuplicateKeys:      checkDuplicateKeys,
+		YAMLLint:                req.YAMLLint,
+		RegexPatternRules:       req.RegexPatternRules,
+		SearchPaths:             req.SearchPaths,
+		Plugins:                 req.Plugins,
+	})
 
 	c.JSON(http.StatusOK, results)
 }